@@ -135,7 +135,7 @@ func (c *generateCommand) network() (monero.Network, error) {
 		return monero.NetworkFakechain, nil
 	}
 
-	err := fmt.Errorf("unknown network %s", c.network)
+	err := fmt.Errorf("unknown network %s", c.networkName)
 	return monero.NetworkFakechain, err
 }
 
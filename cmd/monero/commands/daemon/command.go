@@ -12,5 +12,5 @@ var RootCommand = &cobra.Command{
 }
 
 func init() {
-	options.Bind(RootCommand)
+	options.BindDaemon(RootCommand)
 }
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type flushCacheCommand struct {
+	BadTxs    bool
+	BadBlocks bool
+
+	JSON bool
+}
+
+func (c *flushCacheCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush-cache",
+		Short: "flush the caches of invalid transactions and blocks",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().BoolVar(&c.BadTxs, "bad-txs",
+		false, "flush the cache of invalid transactions")
+	cmd.Flags().BoolVar(&c.BadBlocks, "bad-blocks",
+		false, "flush the cache of invalid blocks")
+
+	return cmd
+}
+
+func (c *flushCacheCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.FlushCache(ctx, c.BadTxs, c.BadBlocks)
+	if err != nil {
+		return fmt.Errorf("flush cache: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *flushCacheCommand) pretty(v *daemon.FlushCacheResult) {
+	fmt.Println(v.Status)
+}
+
+func init() {
+	RootCommand.AddCommand((&flushCacheCommand{}).Cmd())
+}
@@ -0,0 +1,63 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type flushTxpoolCommand struct {
+	Txns []string
+
+	JSON bool
+}
+
+func (c *flushTxpoolCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "flush-txpool",
+		Short: "remove transactions from the transaction pool",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringArrayVar(&c.Txns, "txn",
+		[]string{}, "id of a transaction to remove (all if none)")
+
+	return cmd
+}
+
+func (c *flushTxpoolCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.FlushTxpool(ctx, c.Txns)
+	if err != nil {
+		return fmt.Errorf("flush txpool: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *flushTxpoolCommand) pretty(v *daemon.FlushTxpoolResult) {
+	fmt.Println(v.Status)
+}
+
+func init() {
+	RootCommand.AddCommand((&flushTxpoolCommand{}).Cmd())
+}
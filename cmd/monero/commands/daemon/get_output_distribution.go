@@ -0,0 +1,175 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+// outputDistributionBarWidth is the width of the bar of the bucket with the
+// most outputs.
+//
+const outputDistributionBarWidth = 50
+
+type getOutputDistributionCommand struct {
+	Amount     uint64
+	FromHeight uint64
+	ToHeight   uint64
+	Cumulative bool
+	Buckets    uint64
+
+	JSON bool
+}
+
+func (c *getOutputDistributionCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-output-distribution",
+		Short: "distribution of outputs over a range of blocks",
+		Long: `Retrieves the number of outputs of a given amount (by default, 0 -
+RingCT outputs) created at each block in a range of heights, rendering it
+grouped in buckets of blocks.`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	cmd.Flags().Uint64Var(&c.Amount, "amount",
+		0, "amount of the outputs (0 for RingCT)")
+	cmd.Flags().Uint64Var(&c.FromHeight, "from-height",
+		0, "height of the first block in the range")
+	cmd.Flags().Uint64Var(&c.ToHeight, "to-height",
+		0, "height of the last block in the range (0 for the top of the chain)")
+	cmd.Flags().BoolVar(&c.Cumulative, "cumulative",
+		false, "show the number of outputs created up to each bucket "+
+			"rather than in it")
+	cmd.Flags().Uint64Var(&c.Buckets, "buckets",
+		20, "number of buckets to group the blocks in")
+
+	return cmd
+}
+
+func (c *getOutputDistributionCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+		Amounts:    []uint64{c.Amount},
+		FromHeight: c.FromHeight,
+		ToHeight:   c.ToHeight,
+		Cumulative: c.Cumulative,
+		Compress:   true,
+	})
+	if err != nil {
+		return fmt.Errorf("get output distribution: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	if len(resp.Distributions) != 1 {
+		return fmt.Errorf("expected 1 distribution, got %d",
+			len(resp.Distributions))
+	}
+
+	c.pretty(&resp.Distributions[0])
+	return nil
+}
+
+// nolint:forbidigo
+func (c *getOutputDistributionCommand) pretty(v *daemon.OutputDistribution) {
+	var (
+		blocks     = v.BlockDistribution()
+		cumulative = v.CumulativeDistribution()
+		total      = uint64(0)
+	)
+
+	for _, n := range blocks {
+		total += n
+	}
+
+	table := display.NewTable()
+
+	table.AddRow("Amount:", v.Amount)
+	table.AddRow("Start Height:", v.StartHeight)
+	table.AddRow("Blocks:", len(blocks))
+	table.AddRow("Outputs Before Start:", v.Base)
+	table.AddRow("Outputs:", total)
+
+	fmt.Println(table)
+	fmt.Println("")
+
+	if len(blocks) == 0 {
+		return
+	}
+
+	buckets := c.Buckets
+	if buckets == 0 || buckets > uint64(len(blocks)) {
+		buckets = uint64(len(blocks))
+	}
+
+	size := (uint64(len(blocks)) + buckets - 1) / buckets
+
+	type bucket struct {
+		from, to uint64
+		outputs  uint64
+	}
+
+	rows := []bucket{}
+	max := uint64(0)
+
+	for start := uint64(0); start < uint64(len(blocks)); start += size {
+		end := start + size
+		if end > uint64(len(blocks)) {
+			end = uint64(len(blocks))
+		}
+
+		row := bucket{
+			from: v.StartHeight + start,
+			to:   v.StartHeight + end - 1,
+		}
+
+		if c.Cumulative {
+			row.outputs = cumulative[end-1]
+		} else {
+			for _, n := range blocks[start:end] {
+				row.outputs += n
+			}
+		}
+
+		if row.outputs > max {
+			max = row.outputs
+		}
+
+		rows = append(rows, row)
+	}
+
+	table = display.NewTable()
+	table.AddRow("FROM", "TO", "OUTPUTS", "")
+
+	for _, row := range rows {
+		width := 0
+		if max > 0 {
+			width = int(row.outputs * outputDistributionBarWidth / max)
+		}
+
+		table.AddRow(row.from, row.to, row.outputs, strings.Repeat("#", width))
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&getOutputDistributionCommand{}).Cmd())
+}
@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type inPeersCommand struct {
+	Limit int64
+
+	JSON bool
+}
+
+func (c *inPeersCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "in-peers",
+		Short: "limit the number of incoming peers",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().Int64Var(&c.Limit, "limit",
+		-1, "maximum number of incoming peers (-1 for just retrieving "+
+			"the current limit)")
+
+	return cmd
+}
+
+func (c *inPeersCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.InPeers(ctx, c.Limit)
+	if err != nil {
+		return fmt.Errorf("in peers: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *inPeersCommand) pretty(v *daemon.InPeersResult) {
+	fmt.Println(v.InPeers)
+}
+
+func init() {
+	RootCommand.AddCommand((&inPeersCommand{}).Cmd())
+}
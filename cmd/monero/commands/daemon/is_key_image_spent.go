@@ -0,0 +1,76 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type isKeyImageSpentCommand struct {
+	KeyImages []string
+
+	JSON bool
+}
+
+func (c *isKeyImageSpentCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "is-key-image-spent",
+		Short: "check whether key images have been spent",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	cmd.Flags().StringArrayVar(&c.KeyImages, "key-image",
+		[]string{}, "hex-encoded key image to check (can be repeated)")
+	_ = cmd.MarkFlagRequired("key-image")
+
+	return cmd
+}
+
+func (c *isKeyImageSpentCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.IsKeyImageSpent(ctx, c.KeyImages)
+	if err != nil {
+		return fmt.Errorf("is key image spent: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *isKeyImageSpentCommand) pretty(v *daemon.IsKeyImageSpentResult) {
+	table := display.NewTable()
+
+	table.AddRow("KEY IMAGE", "STATUS")
+	for idx, status := range v.SpentStatus {
+		if idx >= len(c.KeyImages) {
+			break
+		}
+
+		table.AddRow(c.KeyImages[idx], status)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&isKeyImageSpentCommand{}).Cmd())
+}
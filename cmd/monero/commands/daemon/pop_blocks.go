@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type popBlocksCommand struct {
+	Blocks uint64
+
+	JSON bool
+}
+
+func (c *popBlocksCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pop-blocks",
+		Short: "remove the most recent blocks from the chain",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().Uint64Var(&c.Blocks, "blocks",
+		0, "number of blocks to remove")
+	_ = cmd.MarkFlagRequired("blocks")
+
+	return cmd
+}
+
+func (c *popBlocksCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.PopBlocks(ctx, c.Blocks)
+	if err != nil {
+		return fmt.Errorf("pop blocks: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *popBlocksCommand) pretty(v *daemon.PopBlocksResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Height:", v.Height)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&popBlocksCommand{}).Cmd())
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type pruneBlockchainCommand struct {
+	Check bool
+
+	JSON bool
+}
+
+func (c *pruneBlockchainCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune-blockchain",
+		Short: "prune the blockchain (or check whether it's pruned)",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().BoolVar(&c.Check, "check",
+		false, "only check whether the blockchain is pruned")
+
+	return cmd
+}
+
+func (c *pruneBlockchainCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.PruneBlockchain(ctx, c.Check)
+	if err != nil {
+		return fmt.Errorf("prune blockchain: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *pruneBlockchainCommand) pretty(v *daemon.PruneBlockchainResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	table.AddRow("Pruned:", v.Pruned)
+	table.AddRow("Pruning Seed:", v.PruningSeed)
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&pruneBlockchainCommand{}).Cmd())
+}
@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type sendRawTransactionCommand struct {
+	File           string
+	DoNotRelay     bool
+	NoSanityChecks bool
+
+	JSON bool
+}
+
+func (c *sendRawTransactionCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "send-raw-transaction",
+		Short: "broadcast a transaction to the network",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	cmd.Flags().StringVar(&c.File, "file",
+		"", "file containing the transaction, either hex-encoded or "+
+			"raw ('-' for stdin)")
+	_ = cmd.MarkFlagRequired("file")
+
+	cmd.Flags().BoolVar(&c.DoNotRelay, "do-not-relay",
+		false, "add the transaction to the pool of the node without "+
+			"relaying it to the network")
+
+	cmd.Flags().BoolVar(&c.NoSanityChecks, "no-sanity-checks",
+		false, "skip the sanity checks of the transaction")
+
+	return cmd
+}
+
+func (c *sendRawTransactionCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	blob, err := readHexBlob(c.File)
+	if err != nil {
+		return fmt.Errorf("read hex blob: %w", err)
+	}
+
+	params := daemon.SendRawTransactionRequestParameters{
+		TxAsHex:    blob,
+		DoNotRelay: c.DoNotRelay,
+	}
+
+	if c.NoSanityChecks {
+		doSanityChecks := false
+		params.DoSanityChecks = &doSanityChecks
+	}
+
+	resp, err := client.SendRawTransaction(ctx, params)
+	if err != nil {
+		// show why the transaction got rejected before failing.
+		//
+		var rejected *daemon.SendRawTransactionError
+		if errors.As(err, &rejected) {
+			if err := c.display(rejected.Result); err != nil {
+				return err
+			}
+		}
+
+		return fmt.Errorf("send raw transaction: %w", err)
+	}
+
+	return c.display(resp)
+}
+
+func (c *sendRawTransactionCommand) display(v *daemon.SendRawTransactionResult) error {
+	if c.JSON {
+		return display.JSON(v)
+	}
+
+	c.pretty(v)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *sendRawTransactionCommand) pretty(v *daemon.SendRawTransactionResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	if v.Reason != "" {
+		table.AddRow("Reason:", v.Reason)
+	}
+
+	for _, flag := range []struct {
+		name string
+		set  bool
+	}{
+		{"Not Relayed", v.NotRelayed},
+		{"Low Mixin", v.LowMixin},
+		{"Double Spend", v.DoubleSpend},
+		{"Invalid Input", v.InvalidInput},
+		{"Invalid Output", v.InvalidOutput},
+		{"Too Big", v.TooBig},
+		{"Overspend", v.Overspend},
+		{"Fee Too Low", v.FeeTooLow},
+		{"Too Few Outputs", v.TooFewOutputs},
+		{"Sanity Check Failed", v.SanityCheckFailed},
+		{"Tx Extra Too Big", v.TxExtraTooBig},
+		{"Nonzero Unlock Time", v.NonzeroUnlockTime},
+	} {
+		if flag.set {
+			table.AddRow(flag.name+":", flag.set)
+		}
+	}
+
+	fmt.Println(table)
+}
+
+// readHexBlob reads the blob in the file at `path` ('-' for stdin), giving
+// it back hex-encoded - it can either be hex-encoded already or raw.
+//
+func readHexBlob(path string) (string, error) {
+	var r io.Reader = os.Stdin
+
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", fmt.Errorf("open '%s': %w", path, err)
+		}
+
+		defer f.Close()
+
+		r = f
+	}
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("read all: %w", err)
+	}
+
+	trimmed := strings.TrimSpace(string(b))
+	if _, err := hex.DecodeString(trimmed); err == nil && trimmed != "" {
+		return trimmed, nil
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func init() {
+	RootCommand.AddCommand((&sendRawTransactionCommand{}).Cmd())
+}
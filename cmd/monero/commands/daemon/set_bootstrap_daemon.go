@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type setBootstrapDaemonCommand struct {
+	Address  string
+	Username string
+	Password string
+	Proxy    string
+
+	JSON bool
+}
+
+func (c *setBootstrapDaemonCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-bootstrap-daemon",
+		Short: "set the daemon to forward requests to while syncing",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+	cmd.Flags().StringVar(&c.Address, "address",
+		"", "address of the bootstrap daemon ('auto' for picking one "+
+			"automatically, empty for disabling it)")
+	cmd.Flags().StringVar(&c.Username, "username",
+		"", "username for authenticating against the bootstrap daemon")
+	cmd.Flags().StringVar(&c.Password, "password",
+		"", "password for authenticating against the bootstrap daemon")
+	cmd.Flags().StringVar(&c.Proxy, "proxy",
+		"", "socks proxy to connect to the bootstrap daemon through")
+
+	return cmd
+}
+
+func (c *setBootstrapDaemonCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	resp, err := client.SetBootstrapDaemon(ctx, daemon.SetBootstrapDaemonRequestParameters{
+		Address:  c.Address,
+		Username: c.Username,
+		Password: c.Password,
+		Proxy:    c.Proxy,
+	})
+	if err != nil {
+		return fmt.Errorf("set bootstrap daemon: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *setBootstrapDaemonCommand) pretty(v *daemon.SetBootstrapDaemonResult) {
+	fmt.Println(v.Status)
+}
+
+func init() {
+	RootCommand.AddCommand((&setBootstrapDaemonCommand{}).Cmd())
+}
@@ -0,0 +1,81 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/cmd/monero/options"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+type submitBlockCommand struct {
+	Files []string
+
+	JSON bool
+}
+
+func (c *submitBlockCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "submit-block",
+		Short: "submit a mined block to the network",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the result as json")
+
+	cmd.Flags().StringArrayVar(&c.Files, "file",
+		[]string{}, "file containing a block, either hex-encoded or "+
+			"raw ('-' for stdin, can be repeated)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func (c *submitBlockCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := options.RootOpts.Context()
+	defer cancel()
+
+	client, err := options.RootOpts.Client()
+	if err != nil {
+		return fmt.Errorf("client: %w", err)
+	}
+
+	blobs := make([]string, len(c.Files))
+	for idx, file := range c.Files {
+		blobs[idx], err = readHexBlob(file)
+		if err != nil {
+			return fmt.Errorf("read hex blob: %w", err)
+		}
+	}
+
+	resp, err := client.SubmitBlock(ctx, blobs)
+	if err != nil {
+		return fmt.Errorf("submit block: %w", err)
+	}
+
+	if c.JSON {
+		return display.JSON(resp)
+	}
+
+	c.pretty(resp)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *submitBlockCommand) pretty(v *daemon.SubmitBlockResult) {
+	table := display.NewTable()
+
+	table.AddRow("Status:", v.Status)
+	if v.BlockID != "" {
+		table.AddRow("Block ID:", v.BlockID)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&submitBlockCommand{}).Cmd())
+}
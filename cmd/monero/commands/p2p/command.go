@@ -1,10 +1,91 @@
 package p2p
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
+	"golang.org/x/net/proxy"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
 )
 
 var RootCommand = &cobra.Command{
 	Use:   "p2p",
 	Short: "execute p2p commands against a monero node",
 }
+
+// clientOptions gathers the options for levin clients talking to nodes of
+// network `networkName`, optionally going through the SOCKS5 proxy at
+// `proxyAddr`.
+//
+func clientOptions(networkName, proxyAddr string) ([]levin.ClientOption, error) {
+	network, err := monero.ParseNetwork(networkName)
+	if err != nil {
+		return nil, fmt.Errorf("parse network: %w", err)
+	}
+
+	opts := []levin.ClientOption{
+		levin.WithNetwork(network),
+	}
+
+	if proxyAddr == "" {
+		return opts, nil
+	}
+
+	dialer, err := proxyDialer(proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("proxy dialer: %w", err)
+	}
+
+	return append(opts, levin.WithContextDialer(dialer)), nil
+}
+
+// proxyDialer creates a dialer that goes through the SOCKS5 proxy at
+// `proxyAddr`.
+//
+func proxyDialer(proxyAddr string) (levin.ContextDialer, error) {
+	dialer, err := proxy.SOCKS5("tcp", proxyAddr, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("socks5 '%s': %w", proxyAddr, err)
+	}
+
+	contextDialer, ok := dialer.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("can't cast proxy dialer " +
+			"to proxy context dialer")
+	}
+
+	return contextDialer, nil
+}
+
+func parseZone(name string) (levin.Zone, error) {
+	for _, zone := range levin.Zones {
+		if name == string(zone) {
+			return zone, nil
+		}
+	}
+
+	return "", fmt.Errorf("unknown zone %s", name)
+}
+
+func networkOptions() string {
+	strs := []string{}
+
+	for _, network := range monero.Networks {
+		strs = append(strs, string(network))
+	}
+
+	return "(" + strings.Join(strs, ",") + ")"
+}
+
+func zoneOptions() string {
+	strs := []string{}
+
+	for _, zone := range levin.Zones {
+		strs = append(strs, string(zone))
+	}
+
+	return "(" + strings.Join(strs, ",") + ")"
+}
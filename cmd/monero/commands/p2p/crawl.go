@@ -0,0 +1,212 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+type crawlCommand struct {
+	Seeds       []string
+	Timeout     time.Duration
+	PeerTimeout time.Duration
+	Concurrency int
+	MaxNodes    int
+	Proxy       string
+	Network     string
+	Zones       []string
+	JSON        bool
+}
+
+func (c *crawlCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "crawl",
+		Short: "map the reachable p2p network starting from a set of seeds",
+		RunE:  c.RunE,
+	}
+
+	cmd.Flags().StringSliceVar(&c.Seeds,
+		"seed",
+		nil,
+		"address of a node to start crawling from (can be repeated)")
+	_ = cmd.MarkFlagRequired("seed")
+
+	cmd.Flags().DurationVar(&c.Timeout,
+		"timeout",
+		10*time.Minute,
+		"how long to crawl for")
+
+	cmd.Flags().DurationVar(&c.PeerTimeout,
+		"peer-timeout",
+		levin.DefaultCrawlerPeerTimeout,
+		"how long to wait for a node to complete the handshake "+
+			"before considering it unreachable")
+
+	cmd.Flags().IntVar(&c.Concurrency,
+		"concurrency",
+		levin.DefaultCrawlerConcurrency,
+		"maximum number of nodes to handshake with at the same time")
+
+	cmd.Flags().IntVar(&c.MaxNodes,
+		"max-nodes",
+		0,
+		"maximum number of nodes to try to reach (0 for no limit)")
+
+	cmd.Flags().StringVar(&c.Proxy,
+		"proxy",
+		"",
+		"proxy to proxy connections through (useful for tor)")
+
+	cmd.Flags().StringVar(&c.Network,
+		"network",
+		string(monero.NetworkMainnet),
+		"network that the nodes are part of "+networkOptions())
+
+	cmd.Flags().StringSliceVar(&c.Zones,
+		"zone",
+		[]string{string(levin.ZonePublic)},
+		"zones whose addresses should be followed "+zoneOptions())
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the results as json lines")
+
+	return cmd
+}
+
+func (c *crawlCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	zones := []levin.Zone{}
+	for _, name := range c.Zones {
+		zone, err := parseZone(name)
+		if err != nil {
+			return fmt.Errorf("parse zone: %w", err)
+		}
+
+		zones = append(zones, zone)
+	}
+
+	clientOpts, err := clientOptions(c.Network, c.Proxy)
+	if err != nil {
+		return fmt.Errorf("client options: %w", err)
+	}
+
+	crawler := levin.NewCrawler(
+		levin.WithConcurrency(c.Concurrency),
+		levin.WithPeerTimeout(c.PeerTimeout),
+		levin.WithMaxNodes(c.MaxNodes),
+		levin.WithZones(zones...),
+		levin.WithClientOptions(clientOpts...),
+	)
+
+	var (
+		results = []*levin.CrawlResult{}
+		encoder = json.NewEncoder(os.Stdout)
+		encErr  error
+	)
+
+	err = crawler.Crawl(ctx, c.Seeds, func(r *levin.CrawlResult) {
+		if !c.JSON {
+			results = append(results, r)
+			return
+		}
+
+		if err := encoder.Encode(r); err != nil && encErr == nil {
+			encErr = fmt.Errorf("encode: %w", err)
+			cancel()
+		}
+	})
+	if encErr != nil {
+		return encErr
+	}
+
+	// running out of time just means that we're done crawling.
+	//
+	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("crawl: %w", err)
+	}
+
+	if !c.JSON {
+		c.pretty(results)
+	}
+
+	return nil
+}
+
+// nolint:forbidigo
+func (c *crawlCommand) pretty(results []*levin.CrawlResult) {
+	var (
+		reachable = 0
+		versions  = map[uint8]int{}
+		heights   = map[uint64]int{}
+	)
+
+	for _, result := range results {
+		if !result.Reachable {
+			continue
+		}
+
+		reachable++
+		versions[result.TopVersion]++
+		heights[result.CurrentHeight]++
+	}
+
+	table := display.NewTable()
+	table.AddRow("Nodes:", len(results))
+	table.AddRow("Reachable:", reachable)
+	table.AddRow("Unreachable:", len(results)-reachable)
+	fmt.Println(table)
+	fmt.Println("")
+
+	table = display.NewTable()
+	table.AddRow("TOP VERSION", "NODES")
+
+	sortedVersions := []uint8{}
+	for version := range versions {
+		sortedVersions = append(sortedVersions, version)
+	}
+
+	sort.Slice(sortedVersions, func(i, j int) bool {
+		return sortedVersions[i] > sortedVersions[j]
+	})
+
+	for _, version := range sortedVersions {
+		table.AddRow(version, versions[version])
+	}
+
+	fmt.Println(table)
+	fmt.Println("")
+
+	table = display.NewTable()
+	table.AddRow("HEIGHT", "NODES")
+
+	sortedHeights := []uint64{}
+	for height := range heights {
+		sortedHeights = append(sortedHeights, height)
+	}
+
+	sort.Slice(sortedHeights, func(i, j int) bool {
+		return sortedHeights[i] > sortedHeights[j]
+	})
+
+	for _, height := range sortedHeights {
+		table.AddRow(height, heights[height])
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&crawlCommand{}).Cmd())
+}
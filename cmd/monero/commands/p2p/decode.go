@@ -0,0 +1,72 @@
+package p2p
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+type decodeCommand struct {
+	File string
+}
+
+func (c *decodeCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decode",
+		Short: "decode levin messages from captured traffic",
+		Long: `Decodes the levin messages exchanged in captured traffic, printing
+each one of them (header and portable storage tree) as a line of json.
+
+The capture can either be a pcap or pcapng file (e.g., from
+'tcpdump -w capture.pcap port 18080'), or a raw dump of the bytes sent
+by one end of a connection.`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.File,
+		"file",
+		"",
+		"file containing the capture ('-' for stdin)")
+	_ = cmd.MarkFlagRequired("file")
+
+	return cmd
+}
+
+func (c *decodeCommand) RunE(_ *cobra.Command, _ []string) error {
+	var r io.Reader = os.Stdin
+
+	if c.File != "-" {
+		f, err := os.Open(c.File)
+		if err != nil {
+			return fmt.Errorf("open '%s': %w", c.File, err)
+		}
+
+		defer f.Close()
+
+		r = f
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	err := levin.DecodeCapture(r, func(msg *levin.DecodedMessage) error {
+		if err := encoder.Encode(msg); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("decode capture: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	RootCommand.AddCommand((&decodeCommand{}).Cmd())
+}
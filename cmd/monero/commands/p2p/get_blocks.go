@@ -0,0 +1,138 @@
+package p2p
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+type getBlocksCommand struct {
+	NodeAddress string
+	Timeout     time.Duration
+	Proxy       string
+	Network     string
+	From        uint64
+	To          uint64
+	OutputDir   string
+}
+
+func (c *getBlocksCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get-blocks",
+		Short: "download blocks and their transactions from a node",
+		Long: `Downloads blocks (and the transactions they include) straight from a
+node's p2p port, writing their raw blobs to disk as
+
+	<output-dir>/<height>.block
+	<output-dir>/<height>.<n>.tx
+
+where <n> is the position of the transaction in the block.`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.NodeAddress,
+		"node-address",
+		"",
+		"address of the node to connect to")
+	_ = cmd.MarkFlagRequired("node-address")
+
+	cmd.Flags().DurationVar(&c.Timeout,
+		"timeout",
+		10*time.Minute,
+		"how long to wait until considering the download a failure")
+
+	cmd.Flags().StringVar(&c.Proxy,
+		"proxy",
+		"",
+		"proxy to proxy connections through (useful for tor)")
+
+	cmd.Flags().StringVar(&c.Network,
+		"network",
+		string(monero.NetworkMainnet),
+		"network that the node is part of "+networkOptions())
+
+	cmd.Flags().Uint64Var(&c.From,
+		"from",
+		0,
+		"height of the first block to download")
+
+	cmd.Flags().Uint64Var(&c.To,
+		"to",
+		0,
+		"height of the last block to download")
+	_ = cmd.MarkFlagRequired("to")
+
+	cmd.Flags().StringVar(&c.OutputDir,
+		"output-dir",
+		".",
+		"directory where the blobs should be written to")
+
+	return cmd
+}
+
+// nolint:forbidigo
+func (c *getBlocksCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	defer cancel()
+
+	opts, err := clientOptions(c.Network, c.Proxy)
+	if err != nil {
+		return fmt.Errorf("client options: %w", err)
+	}
+
+	if err := os.MkdirAll(c.OutputDir, 0o755); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", c.OutputDir, err)
+	}
+
+	client, err := levin.NewClient(ctx, c.NodeAddress, opts...)
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	defer client.Close()
+
+	if _, err := client.Handshake(ctx); err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+
+	err = client.GetBlocks(ctx, c.From, c.To, func(height uint64, block *levin.BlockCompleteEntry) error {
+		if err := c.write(fmt.Sprintf("%d.block", height), block.Block); err != nil {
+			return err
+		}
+
+		for idx, tx := range block.Txs {
+			if err := c.write(fmt.Sprintf("%d.%d.tx", height, idx), tx.Blob); err != nil {
+				return err
+			}
+		}
+
+		fmt.Printf("%d\t%d txs\n", height, len(block.Txs))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("get blocks: %w", err)
+	}
+
+	return nil
+}
+
+func (c *getBlocksCommand) write(name string, blob []byte) error {
+	fpath := filepath.Join(c.OutputDir, name)
+
+	if err := os.WriteFile(fpath, blob, 0o644); err != nil { // nolint:gosec
+		return fmt.Errorf("write file '%s': %w", fpath, err)
+	}
+
+	return nil
+}
+
+func init() {
+	RootCommand.AddCommand((&getBlocksCommand{}).Cmd())
+}
@@ -0,0 +1,230 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/cmd/monero/display"
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+type observeCommand struct {
+	Seeds       []string
+	Duration    time.Duration
+	Peers       int
+	PeerTimeout time.Duration
+	Proxy       string
+	Network     string
+	Zones       []string
+	StatsFile   string
+	JSON        bool
+}
+
+func (c *observeCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "observe",
+		Short: "record when transactions and blocks are announced by peers",
+		Long: `Keeps connections to a number of peers (starting from the seeds and
+following their peer lists), recording when and by whom each transaction
+(NOTIFY_NEW_TRANSACTIONS) and block (NOTIFY_NEW_FLUFFY_BLOCK) is first
+announced, as well as how long the other peers take to announce them too.
+
+Once done (either after --duration or on interrupt), statistics of the
+arrival latencies are printed (or written to --stats-file as json).`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringSliceVar(&c.Seeds,
+		"seed",
+		nil,
+		"address of a node to start connecting to (can be repeated)")
+	_ = cmd.MarkFlagRequired("seed")
+
+	cmd.Flags().DurationVar(&c.Duration,
+		"duration",
+		0,
+		"how long to observe for (0 for until interrupted)")
+
+	cmd.Flags().IntVar(&c.Peers,
+		"peers",
+		levin.DefaultObserverPeers,
+		"number of peers to keep connections to")
+
+	cmd.Flags().DurationVar(&c.PeerTimeout,
+		"peer-timeout",
+		levin.DefaultObserverPeerTimeout,
+		"how long to wait for a node to complete the handshake")
+
+	cmd.Flags().StringVar(&c.Proxy,
+		"proxy",
+		"",
+		"proxy to proxy connections through (useful for tor)")
+
+	cmd.Flags().StringVar(&c.Network,
+		"network",
+		string(monero.NetworkMainnet),
+		"network that the nodes are part of "+networkOptions())
+
+	cmd.Flags().StringSliceVar(&c.Zones,
+		"zone",
+		[]string{string(levin.ZonePublic)},
+		"zones whose addresses should be connected to "+zoneOptions())
+
+	cmd.Flags().StringVar(&c.StatsFile,
+		"stats-file",
+		"",
+		"file to write the latency statistics to as json")
+
+	cmd.Flags().BoolVar(&c.JSON, "json",
+		false, "whether or not to output the announcements and statistics as json")
+
+	return cmd
+}
+
+func (c *observeCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if c.Duration > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Duration)
+		defer cancel()
+	}
+
+	zones := []levin.Zone{}
+	for _, name := range c.Zones {
+		zone, err := parseZone(name)
+		if err != nil {
+			return fmt.Errorf("parse zone: %w", err)
+		}
+
+		zones = append(zones, zone)
+	}
+
+	clientOpts, err := clientOptions(c.Network, c.Proxy)
+	if err != nil {
+		return fmt.Errorf("client options: %w", err)
+	}
+
+	observer := levin.NewObserver(
+		levin.WithObservedPeers(c.Peers),
+		levin.WithObserverPeerTimeout(c.PeerTimeout),
+		levin.WithObserverZones(zones...),
+		levin.WithObserverClientOptions(clientOpts...),
+	)
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var (
+		encoder   = json.NewEncoder(os.Stdout)
+		encodeErr error
+	)
+
+	err = observer.Observe(ctx, c.Seeds, func(a *levin.Announcement) {
+		if !c.JSON {
+			c.prettyAnnouncement(a)
+			return
+		}
+
+		// there's no point in observing any further if what's
+		// observed can't be written out (e.g., stdout closed).
+		//
+		if encodeErr != nil {
+			return
+		}
+
+		if err := encoder.Encode(a); err != nil {
+			encodeErr = err
+			stop()
+		}
+	})
+
+	if encodeErr != nil {
+		return fmt.Errorf("encode: %w", encodeErr)
+	}
+
+	// being interrupted or running out of time just means that we're
+	// done observing.
+	//
+	if err != nil && !errors.Is(err, context.Canceled) &&
+		!errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("observe: %w", err)
+	}
+
+	stats := observer.Stats()
+
+	if c.StatsFile != "" {
+		b, err := json.MarshalIndent(stats, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal stats: %w", err)
+		}
+
+		if err := os.WriteFile(c.StatsFile, b, 0o644); err != nil { // nolint:gosec
+			return fmt.Errorf("write file '%s': %w", c.StatsFile, err)
+		}
+	}
+
+	if c.JSON {
+		if err := encoder.Encode(stats); err != nil {
+			return fmt.Errorf("encode: %w", err)
+		}
+
+		return nil
+	}
+
+	c.prettyStats(stats)
+	return nil
+}
+
+// nolint:forbidigo
+func (c *observeCommand) prettyAnnouncement(a *levin.Announcement) {
+	if !a.First {
+		return
+	}
+
+	fmt.Printf("%s  %-5s  %s  %s\n",
+		a.Time.Format(time.RFC3339Nano), a.Kind, a.Hash, a.Peer)
+}
+
+// nolint:forbidigo
+func (c *observeCommand) prettyStats(stats *levin.ObserverStats) {
+	fmt.Println("")
+
+	table := display.NewTable()
+	table.AddRow("KIND", "OBJECTS", "ANNOUNCEMENTS", "P50", "P90", "P99", "MAX")
+	for _, row := range []struct {
+		kind    levin.ObjectKind
+		latency levin.LatencyStats
+	}{
+		{levin.ObjectTransaction, stats.Transactions},
+		{levin.ObjectBlock, stats.Blocks},
+	} {
+		table.AddRow(row.kind, row.latency.Objects, row.latency.Announcements,
+			row.latency.P50, row.latency.P90, row.latency.P99, row.latency.Max)
+	}
+
+	fmt.Println(table)
+	fmt.Println("")
+
+	table = display.NewTable()
+	table.AddRow("PEER", "ANNOUNCEMENTS", "FIRST", "MEAN DELAY")
+	for _, peer := range stats.Peers {
+		table.AddRow(peer.Address, peer.Announcements, peer.First, peer.MeanDelay)
+	}
+
+	fmt.Println(table)
+}
+
+func init() {
+	RootCommand.AddCommand((&observeCommand{}).Cmd())
+}
@@ -6,15 +6,17 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/net/proxy"
 
 	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
 )
 
 type peerListCommand struct {
 	NodeAddress string
 	Timeout     time.Duration
 	Proxy       string
+	Network     string
+	Zone        string
 }
 
 func (c *peerListCommand) Cmd() *cobra.Command {
@@ -40,6 +42,16 @@ func (c *peerListCommand) Cmd() *cobra.Command {
 		"",
 		"proxy to proxy connections through (useful for tor)")
 
+	cmd.Flags().StringVar(&c.Network,
+		"network",
+		string(monero.NetworkMainnet),
+		"network that the node is part of "+networkOptions())
+
+	cmd.Flags().StringVar(&c.Zone,
+		"zone",
+		"",
+		"only display peers reachable through a given zone "+zoneOptions())
+
 	return cmd
 }
 
@@ -48,21 +60,15 @@ func (c *peerListCommand) RunE(_ *cobra.Command, _ []string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
 	defer cancel()
 
-	opts := []levin.ClientOption{}
-
-	if c.Proxy != "" {
-		dialer, err := proxy.SOCKS5("tcp", c.Proxy, nil, nil)
-		if err != nil {
-			return fmt.Errorf("socks5 '%s': %w", c.Proxy, err)
-		}
-
-		contextDialer, ok := dialer.(proxy.ContextDialer)
-		if !ok {
-			return fmt.Errorf("can't cast proxy dialer " +
-				"to proxy context dialer")
+	if c.Zone != "" {
+		if _, err := parseZone(c.Zone); err != nil {
+			return fmt.Errorf("parse zone: %w", err)
 		}
+	}
 
-		opts = append(opts, levin.WithContextDialer(contextDialer))
+	opts, err := clientOptions(c.Network, c.Proxy)
+	if err != nil {
+		return fmt.Errorf("client options: %w", err)
 	}
 
 	client, err := levin.NewClient(ctx, c.NodeAddress, opts...)
@@ -77,7 +83,11 @@ func (c *peerListCommand) RunE(_ *cobra.Command, _ []string) error {
 		return fmt.Errorf("handshake: %w", err)
 	}
 
-	for addr := range pl.Peers {
+	for addr, peer := range pl.Peers {
+		if c.Zone != "" && peer.Address.Zone() != levin.Zone(c.Zone) {
+			continue
+		}
+
 		fmt.Println(addr)
 	}
 
@@ -0,0 +1,93 @@
+package p2p
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+type proxyCommand struct {
+	Listen   string
+	Upstream string
+	Proxy    string
+}
+
+func (c *proxyCommand) Cmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "proxy",
+		Short: "relay p2p traffic to a node logging every message",
+		Long: `Relays the levin traffic between nodes that connect to --listen and
+the node at --upstream, printing every message that goes through it
+(direction, size, header and portable storage tree) as a line of json.
+
+Dummy (noise) packets and the fragments of fragmented messages are
+printed as they go through too, followed by the message the fragments
+carry once it's been reassembled.
+
+For instance, to observe what a local monerod exchanges w/ a given peer:
+
+	monero p2p proxy --listen :28080 --upstream node:18080
+	monerod --add-exclusive-node 127.0.0.1:28080`,
+		RunE: c.RunE,
+	}
+
+	cmd.Flags().StringVar(&c.Listen,
+		"listen",
+		":28080",
+		"address to listen for connections on")
+
+	cmd.Flags().StringVar(&c.Upstream,
+		"upstream",
+		"",
+		"address of the node to relay connections to")
+	_ = cmd.MarkFlagRequired("upstream")
+
+	cmd.Flags().StringVar(&c.Proxy,
+		"proxy",
+		"",
+		"proxy to proxy upstream connections through (useful for tor)")
+
+	return cmd
+}
+
+func (c *proxyCommand) RunE(_ *cobra.Command, _ []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(),
+		os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	encoder := json.NewEncoder(os.Stdout)
+
+	opts := []levin.ProxyOption{
+		levin.WithMessageHandler(func(m *levin.ProxiedMessage) {
+			if err := encoder.Encode(m); err != nil {
+				fmt.Fprintf(os.Stderr, "encode: %v\n", err)
+			}
+		}),
+	}
+
+	if c.Proxy != "" {
+		dialer, err := proxyDialer(c.Proxy)
+		if err != nil {
+			return fmt.Errorf("proxy dialer: %w", err)
+		}
+
+		opts = append(opts, levin.WithUpstreamDialer(dialer))
+	}
+
+	if err := levin.NewProxy(c.Upstream, opts...).ListenAndServe(ctx, c.Listen); err != nil {
+		return fmt.Errorf("listen and serve: %w", err)
+	}
+
+	return nil
+}
+
+func init() {
+	RootCommand.AddCommand((&proxyCommand{}).Cmd())
+}
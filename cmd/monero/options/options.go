@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -12,6 +14,8 @@ import (
 	mhttp "github.com/cirocosta/go-monero/pkg/http"
 	"github.com/cirocosta/go-monero/pkg/rpc"
 	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+	"github.com/cirocosta/go-monero/pkg/rpc/pool"
+	"github.com/cirocosta/go-monero/pkg/rpc/quorum"
 	"github.com/cirocosta/go-monero/pkg/rpc/wallet"
 )
 
@@ -26,6 +30,12 @@ type options struct {
 	address string
 	mhttp.ClientConfig
 	shortenAddresses bool
+
+	retries      int
+	retryBackoff time.Duration
+
+	strategy string
+	quorum   string
 }
 
 // AddrFmter provides the function that should be used when displaying
@@ -59,22 +69,165 @@ func (o *options) initializeFromEnv() {
 
 // Client instantiates a new daemon RPC client based on the options filled.
 //
+// With more than one address, requests go to the nodes according to the
+// strategy option, failing over to the others.
+//
 func (o *options) Client() (*daemon.Client, error) {
 	o.initializeFromEnv()
 
-	httpClient, err := mhttp.NewClient(o.ClientConfig)
+	clientOpts, err := o.clientOptions()
 	if err != nil {
-		return nil, fmt.Errorf("new httpclient: %w", err)
+		return nil, fmt.Errorf("client options: %w", err)
+	}
+
+	addresses := o.addresses()
+	if o.quorum != "" {
+		return o.quorumClient(addresses, clientOpts)
+	}
+
+	if len(addresses) == 1 {
+		client, err := rpc.NewClient(addresses[0], clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new daemon client for '%s': %w",
+				addresses[0], err,
+			)
+		}
+
+		return daemon.NewClient(client), nil
 	}
 
-	client, err := rpc.NewClient(o.address, rpc.WithHTTPClient(httpClient))
+	clients := make([]*rpc.Client, len(addresses))
+	for idx, address := range addresses {
+		clients[idx], err = rpc.NewClient(address, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new daemon client for '%s': %w",
+				address, err,
+			)
+		}
+	}
+
+	strategy, err := pool.ParseStrategy(o.strategy)
 	if err != nil {
-		return nil, fmt.Errorf("new daemon client for '%s': %w",
-			o.address, err,
-		)
+		return nil, fmt.Errorf("parse strategy: %w", err)
+	}
+
+	p, err := pool.New(clients, pool.WithStrategy(strategy))
+	if err != nil {
+		return nil, fmt.Errorf("new pool: %w", err)
+	}
+
+	// only the latencies measured by a health check tell which node is
+	// the fastest, thus, there's no point in checking the nodes upfront
+	// otherwise.
+	//
+	if strategy == pool.LowestLatency {
+		ctx, cancel := o.Context()
+		defer cancel()
+
+		p.CheckHealth(ctx)
 	}
 
-	return daemon.NewClient(client), nil
+	return daemon.NewClient(p), nil
+}
+
+// quorumClient instantiates a daemon RPC client whose results are
+// cross-checked across all the nodes in `addresses` according to the quorum
+// option (`<threshold>/<nodes>`), warning about the nodes that diverge.
+//
+func (o *options) quorumClient(
+	addresses []string, clientOpts []rpc.ClientOption,
+) (*daemon.Client, error) {
+	threshold, total, err := parseQuorum(o.quorum)
+	if err != nil {
+		return nil, fmt.Errorf("parse quorum: %w", err)
+	}
+
+	if total != len(addresses) {
+		return nil, fmt.Errorf("quorum of %d nodes, but %d addresses "+
+			"specified", total, len(addresses))
+	}
+
+	clients := make([]*rpc.Client, len(addresses))
+	for idx, address := range addresses {
+		clients[idx], err = rpc.NewClient(address, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("new daemon client for '%s': %w",
+				address, err,
+			)
+		}
+	}
+
+	q, err := quorum.New(clients, threshold,
+		quorum.WithReporter(reportDivergence),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("new quorum: %w", err)
+	}
+
+	return daemon.NewClient(q), nil
+}
+
+// parseQuorum parses a quorum in the form `<threshold>/<nodes>`, making sure
+// that the threshold can be reached.
+//
+func parseQuorum(v string) (int, int, error) {
+	thresholdStr, totalStr, found := strings.Cut(v, "/")
+	if !found {
+		return 0, 0, fmt.Errorf("'%s' not in the form "+
+			"'<threshold>/<nodes>'", v)
+	}
+
+	threshold, err := strconv.Atoi(thresholdStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("threshold '%s': %w", thresholdStr, err)
+	}
+
+	total, err := strconv.Atoi(totalStr)
+	if err != nil {
+		return 0, 0, fmt.Errorf("nodes '%s': %w", totalStr, err)
+	}
+
+	if threshold < 1 || threshold > total {
+		return 0, 0, fmt.Errorf("threshold of '%s' must be between 1 "+
+			"and the number of nodes (%d)", v, total)
+	}
+
+	return threshold, total, nil
+}
+
+// reportDivergence warns (to stderr) about the nodes that diverged on a
+// request.
+//
+func reportDivergence(report quorum.Report) {
+	for _, node := range report.Divergent() {
+		if node.Err != nil {
+			fmt.Fprintf(os.Stderr, "warning: '%s' failed on '%s': %v\n",
+				node.Address, report.Method, node.Err)
+			continue
+		}
+
+		fmt.Fprintf(os.Stderr, "warning: '%s' diverged on '%s'\n",
+			node.Address, report.Method)
+	}
+}
+
+// addresses gives back the addresses of the nodes to reach out to, split
+// from the comma-separated list in `address`.
+//
+func (o *options) addresses() []string {
+	addresses := []string{}
+
+	for _, address := range strings.Split(o.address, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+
+	if len(addresses) == 0 {
+		return []string{o.address}
+	}
+
+	return addresses
 }
 
 // WalletClient instantiates a new wallet RPC client based on the options
@@ -83,12 +236,16 @@ func (o *options) Client() (*daemon.Client, error) {
 func (o *options) WalletClient() (*wallet.Client, error) {
 	o.initializeFromEnv()
 
-	httpClient, err := mhttp.NewClient(o.ClientConfig)
+	if len(o.addresses()) > 1 {
+		return nil, fmt.Errorf("only one wallet address can be specified")
+	}
+
+	clientOpts, err := o.clientOptions()
 	if err != nil {
-		return nil, fmt.Errorf("new httpclient: %w", err)
+		return nil, fmt.Errorf("client options: %w", err)
 	}
 
-	client, err := rpc.NewClient(o.address, rpc.WithHTTPClient(httpClient))
+	client, err := rpc.NewClient(o.address, clientOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("new daemon client for '%s': %w",
 			o.address, err,
@@ -98,6 +255,29 @@ func (o *options) WalletClient() (*wallet.Client, error) {
 	return wallet.NewClient(client), nil
 }
 
+// clientOptions builds the options for instantiating RPC clients based on
+// the options filled.
+//
+func (o *options) clientOptions() ([]rpc.ClientOption, error) {
+	httpClient, err := mhttp.NewClient(o.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("new httpclient: %w", err)
+	}
+
+	opts := []rpc.ClientOption{
+		rpc.WithHTTPClient(httpClient),
+	}
+
+	if o.retries > 0 {
+		opts = append(opts, rpc.WithRetry(rpc.RetryPolicy{
+			MaxAttempts: o.retries + 1,
+			Backoff:     o.retryBackoff,
+		}))
+	}
+
+	return opts, nil
+}
+
 // Bind binds the flags defined by `options` to a `cobra` command so that they
 // can be filled either via comand arguments or environment variables.
 //
@@ -116,7 +296,8 @@ func Bind(cmd *cobra.Command) {
 	cmd.PersistentFlags().StringVarP(&RootOpts.address,
 		"address", "a",
 		"http://localhost:18081",
-		"full address of the monero node to reach out to "+
+		"full address of the monero node to reach out to, or a "+
+			"comma-separated list of them to fail over between "+
 			"[MONERO_ADDRESS]")
 
 	cmd.PersistentFlags().StringVarP(&RootOpts.Username,
@@ -153,4 +334,40 @@ func Bind(cmd *cobra.Command) {
 		"request-timeout",
 		1*time.Minute,
 		"max wait time until considering the request a failure")
+
+	cmd.PersistentFlags().IntVar(&RootOpts.retries,
+		"retries",
+		0,
+		"number of times to retry requests that failed due to "+
+			"transient errors (e.g., node busy or unreachable) - "+
+			"requests with side effects (like relaying a "+
+			"transaction) are only retried if certainly not "+
+			"processed")
+
+	cmd.PersistentFlags().DurationVar(&RootOpts.retryBackoff,
+		"retry-backoff",
+		500*time.Millisecond,
+		"time to wait before the first retry, doubling (with "+
+			"jitter) for each of the following ones")
+}
+
+// BindDaemon binds, in addition to those from `Bind`, the flags that only
+// apply to commands that talk to a daemon.
+//
+func BindDaemon(cmd *cobra.Command) {
+	Bind(cmd)
+
+	cmd.PersistentFlags().StringVar(&RootOpts.strategy,
+		"strategy",
+		pool.RoundRobin.String(),
+		"how to pick the node in --address that requests go to "+
+			"first: round-robin (in the order given) or "+
+			"lowest-latency (checking all of them upfront)")
+
+	cmd.PersistentFlags().StringVar(&RootOpts.quorum,
+		"quorum",
+		"",
+		"cross-check results across all the nodes in --address, "+
+			"requiring '<threshold>/<nodes>' of them to agree "+
+			"(e.g., 2/3)")
 }
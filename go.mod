@@ -1,6 +1,6 @@
 module github.com/cirocosta/go-monero
 
-go 1.17
+go 1.18
 
 require (
 	github.com/dustin/go-humanize v1.0.0
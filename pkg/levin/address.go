@@ -0,0 +1,221 @@
+package levin
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// see https://github.com/monero-project/monero/blob/e45619e61e4831eea70a43fe6985f4d57ea02e9e/contrib/epee/include/net/net_utils_base.h
+
+// AddressType is the discriminator of the kind of address carried in a peer
+// list entry.
+//
+type AddressType uint8
+
+const (
+	AddressTypeInvalid AddressType = 0
+	AddressTypeIPv4    AddressType = 1
+	AddressTypeIPv6    AddressType = 2
+	AddressTypeI2P     AddressType = 3
+	AddressTypeTor     AddressType = 4
+)
+
+// Zone is the network that an address is reachable through.
+//
+type Zone string
+
+const (
+	ZonePublic Zone = "public"
+	ZoneTor    Zone = "tor"
+	ZoneI2P    Zone = "i2p"
+)
+
+// Zones are all the known zones.
+//
+var Zones = []Zone{
+	ZonePublic,
+	ZoneTor,
+	ZoneI2P,
+}
+
+// NetworkAddress is an address that a peer can be reached at: one of
+// `IPv4Address`, `IPv6Address`, `TorAddress` or `I2PAddress`.
+//
+type NetworkAddress interface {
+	Type() AddressType
+	Zone() Zone
+
+	// String is the address in the `host:port` form.
+	//
+	String() string
+}
+
+// IPv4Address is the address of a peer in the public internet reachable
+// through ipv4.
+//
+type IPv4Address struct {
+	IP   net.IP
+	Port uint16
+}
+
+func (a IPv4Address) Type() AddressType { return AddressTypeIPv4 }
+func (a IPv4Address) Zone() Zone        { return ZonePublic }
+
+func (a IPv4Address) String() string {
+	return net.JoinHostPort(a.IP.String(), strconv.Itoa(int(a.Port)))
+}
+
+// IPv6Address is the address of a peer in the public internet reachable
+// through ipv6.
+//
+type IPv6Address struct {
+	IP   net.IP
+	Port uint16
+}
+
+func (a IPv6Address) Type() AddressType { return AddressTypeIPv6 }
+func (a IPv6Address) Zone() Zone        { return ZonePublic }
+
+func (a IPv6Address) String() string {
+	return net.JoinHostPort(a.IP.String(), strconv.Itoa(int(a.Port)))
+}
+
+// TorAddress is the address of a peer reachable as a tor (v3) onion service.
+//
+type TorAddress struct {
+	Host string
+	Port uint16
+}
+
+func (a TorAddress) Type() AddressType { return AddressTypeTor }
+func (a TorAddress) Zone() Zone        { return ZoneTor }
+
+func (a TorAddress) String() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(int(a.Port)))
+}
+
+// I2PAddress is the address of a peer reachable through its i2p b32 address.
+//
+type I2PAddress struct {
+	Host string
+	Port uint16
+}
+
+func (a I2PAddress) Type() AddressType { return AddressTypeI2P }
+func (a I2PAddress) Zone() Zone        { return ZoneI2P }
+
+func (a I2PAddress) String() string {
+	return net.JoinHostPort(a.Host, strconv.Itoa(int(a.Port)))
+}
+
+// ParseNetworkAddress parses an address in the `host:port` form, picking the
+// type of address according to the host (`.onion` for tor, `.i2p` for i2p,
+// and ip addresses otherwise).
+//
+func ParseNetworkAddress(addr string) (NetworkAddress, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("split host port: %w", err)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("parse port '%s': %w", portStr, err)
+	}
+
+	switch {
+	case strings.HasSuffix(host, ".onion"):
+		return TorAddress{Host: host, Port: uint16(port)}, nil
+	case strings.HasSuffix(host, ".i2p"):
+		return I2PAddress{Host: host, Port: uint16(port)}, nil
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid host '%s'", host)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return IPv4Address{IP: ip4, Port: uint16(port)}, nil
+	}
+
+	return IPv6Address{IP: ip, Port: uint16(port)}, nil
+}
+
+// netAddress is the epee representation of a network address, where `type`
+// determines which of the fields of `addr` are set.
+//
+type netAddress struct {
+	Type AddressType `epee:"type"`
+	Addr struct {
+		// ipv4
+		//
+		IP   uint32 `epee:"m_ip,omitempty"`
+		Port uint16 `epee:"m_port,omitempty"`
+
+		// ipv6
+		//
+		Addr []byte `epee:"addr,omitempty"`
+
+		// tor and i2p (overlay networks)
+		//
+		Host        string `epee:"host,omitempty"`
+		OverlayPort uint16 `epee:"port,omitempty"`
+	} `epee:"addr"`
+}
+
+// networkAddress converts the epee representation into its typed form, nil
+// if the type is not known.
+//
+func (a netAddress) networkAddress() NetworkAddress {
+	switch a.Type {
+	case AddressTypeIPv4:
+		return IPv4Address{IP: ipzify(a.Addr.IP), Port: a.Addr.Port}
+	case AddressTypeIPv6:
+		if len(a.Addr.Addr) != net.IPv6len {
+			return nil
+		}
+
+		return IPv6Address{IP: net.IP(a.Addr.Addr), Port: a.Addr.Port}
+	case AddressTypeTor:
+		return TorAddress{Host: a.Addr.Host, Port: a.Addr.OverlayPort}
+	case AddressTypeI2P:
+		return I2PAddress{Host: a.Addr.Host, Port: a.Addr.OverlayPort}
+	case AddressTypeInvalid:
+		// old peers don't set the type, with the kind of address being
+		// deduced from the fields that are present.
+		//
+		if len(a.Addr.Addr) == net.IPv6len {
+			return IPv6Address{IP: net.IP(a.Addr.Addr), Port: a.Addr.Port}
+		}
+
+		if a.Addr.IP != 0 {
+			return IPv4Address{IP: ipzify(a.Addr.IP), Port: a.Addr.Port}
+		}
+	}
+
+	return nil
+}
+
+func newNetAddress(addr NetworkAddress) netAddress {
+	a := netAddress{Type: addr.Type()}
+
+	switch addr := addr.(type) {
+	case IPv4Address:
+		a.Addr.IP = unipzify(addr.IP)
+		a.Addr.Port = addr.Port
+	case IPv6Address:
+		a.Addr.Addr = addr.IP.To16()
+		a.Addr.Port = addr.Port
+	case TorAddress:
+		a.Addr.Host = addr.Host
+		a.Addr.OverlayPort = addr.Port
+	case I2PAddress:
+		a.Addr.Host = addr.Host
+		a.Addr.OverlayPort = addr.Port
+	}
+
+	return a
+}
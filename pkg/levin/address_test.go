@@ -0,0 +1,101 @@
+package levin_test
+
+import (
+	"net"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+func TestAddress(t *testing.T) {
+	spec.Run(t, "ParseNetworkAddress", func(t *testing.T, when spec.G, it spec.S) {
+		it("parses ipv4", func() {
+			addr, err := levin.ParseNetworkAddress("1.2.3.4:18080")
+			require.NoError(t, err)
+			assert.Equal(t, levin.IPv4Address{
+				IP:   net.ParseIP("1.2.3.4").To4(),
+				Port: 18080,
+			}, addr)
+			assert.Equal(t, levin.ZonePublic, addr.Zone())
+			assert.Equal(t, "1.2.3.4:18080", addr.String())
+		})
+
+		it("parses ipv6", func() {
+			addr, err := levin.ParseNetworkAddress("[::1]:18080")
+			require.NoError(t, err)
+			assert.Equal(t, levin.AddressTypeIPv6, addr.Type())
+			assert.Equal(t, levin.ZonePublic, addr.Zone())
+			assert.Equal(t, "[::1]:18080", addr.String())
+		})
+
+		it("parses tor", func() {
+			addr, err := levin.ParseNetworkAddress("foo.onion:18083")
+			require.NoError(t, err)
+			assert.Equal(t, levin.TorAddress{Host: "foo.onion", Port: 18083}, addr)
+			assert.Equal(t, levin.ZoneTor, addr.Zone())
+		})
+
+		it("parses i2p", func() {
+			addr, err := levin.ParseNetworkAddress("foo.b32.i2p:0")
+			require.NoError(t, err)
+			assert.Equal(t, levin.I2PAddress{Host: "foo.b32.i2p"}, addr)
+			assert.Equal(t, levin.ZoneI2P, addr.Zone())
+		})
+
+		it("fails w/ hostname", func() {
+			_, err := levin.ParseNetworkAddress("example.com:18080")
+			assert.Error(t, err)
+		})
+
+		it("fails w/ missing port", func() {
+			_, err := levin.ParseNetworkAddress("1.2.3.4")
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "ParsePeerList", func(t *testing.T, when spec.G, it spec.S) {
+		peerListEntry := func(adr levin.Entries) levin.Entry {
+			return levin.Entry{
+				Value: levin.Entries{
+					{Name: "adr", Value: adr},
+					{Name: "id", Value: uint64(1)},
+				},
+			}
+		}
+
+		it("deduces the type of legacy entries", func() {
+			peers, err := levin.ParsePeerList(levin.Entry{
+				Name: "local_peerlist_new",
+				Value: levin.Entries{
+					peerListEntry(levin.Entries{
+						{Name: "addr", Value: levin.Entries{
+							{Name: "m_ip", Value: uint32(0x04030201)},
+							{Name: "m_port", Value: uint16(18080)},
+						}},
+					}),
+				},
+			})
+			require.NoError(t, err)
+			require.Contains(t, peers, "1.2.3.4:18080")
+			assert.Equal(t, levin.AddressTypeIPv4, peers["1.2.3.4:18080"].Address.Type())
+		})
+
+		it("skips unknown types", func() {
+			peers, err := levin.ParsePeerList(levin.Entry{
+				Name: "local_peerlist_new",
+				Value: levin.Entries{
+					peerListEntry(levin.Entries{
+						{Name: "type", Value: uint8(42)},
+					}),
+				},
+			})
+			require.NoError(t, err)
+			assert.Empty(t, peers)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
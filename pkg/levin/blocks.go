@@ -0,0 +1,161 @@
+package levin
+
+import (
+	"context"
+	"fmt"
+)
+
+// MaxObjectRequestCount is the maximum number of blocks that peers accept
+// being asked for in a single NOTIFY_REQUEST_GET_OBJECTS
+// (CURRENCY_PROTOCOL_MAX_OBJECT_REQUEST_COUNT).
+//
+const MaxObjectRequestCount = 100
+
+// BlockHandler is called w/ every block fetched by `GetBlocks`, in order of
+// height.
+//
+type BlockHandler func(height uint64, block *BlockCompleteEntry) error
+
+// RequestChain asks the peer for the ids of the blocks that follow the most
+// recent block in the sparse chain history `blockIDs` that it knows about.
+//
+// The history must end w/ the genesis block.
+//
+func (c *Client) RequestChain(ctx context.Context, blockIDs []Hash) (*ResponseChainEntry, error) {
+	resp, err := c.Request(ctx, &RequestChain{
+		BlockIDs: blockIDs,
+	}, NotifyResponseChainEntry)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+
+	return resp.(*ResponseChainEntry), nil
+}
+
+// GetObjects asks the peer for the blocks (along w/ their transactions)
+// whose ids are `blocks`.
+//
+func (c *Client) GetObjects(ctx context.Context, blocks []Hash) (*ResponseGetObjects, error) {
+	if len(blocks) > MaxObjectRequestCount {
+		return nil, fmt.Errorf("%d blocks over max of %d",
+			len(blocks), MaxObjectRequestCount)
+	}
+
+	resp, err := c.Request(ctx, &RequestGetObjects{
+		Blocks: blocks,
+	}, NotifyResponseGetObjects)
+	if err != nil {
+		return nil, fmt.Errorf("request: %w", err)
+	}
+
+	return resp.(*ResponseGetObjects), nil
+}
+
+// GetBlocks fetches from the peer the blocks from height `from` up to `to`
+// (inclusive, capped to the peer's current height), handing each one of
+// them to `fn`.
+//
+// As block ids are not known upfront, they're discovered by walking the
+// chain from the genesis block w/ NOTIFY_REQUEST_CHAIN, with the blocks
+// themselves then fetched in batches w/ NOTIFY_REQUEST_GET_OBJECTS.
+//
+func (c *Client) GetBlocks(ctx context.Context, from, to uint64, fn BlockHandler) error {
+	genesis := c.local.Genesis
+	if genesis == (Hash{}) {
+		return fmt.Errorf("genesis block of the network not known")
+	}
+
+	if from > to {
+		return fmt.Errorf("from (%d) after to (%d)", from, to)
+	}
+
+	history := []Hash{genesis}
+	height := from
+
+	for height <= to {
+		chain, err := c.RequestChain(ctx, history)
+		if err != nil {
+			return fmt.Errorf("request chain: %w", err)
+		}
+
+		if chain.TotalHeight == 0 || len(chain.BlockIDs) == 0 {
+			return fmt.Errorf("empty chain entry")
+		}
+
+		if chain.StartHeight > height {
+			return fmt.Errorf("chain entry starting at %d after %d",
+				chain.StartHeight, height)
+		}
+
+		if to >= chain.TotalHeight {
+			to = chain.TotalHeight - 1
+		}
+
+		if height > to {
+			return fmt.Errorf("height %d beyond the peer's chain of %d blocks",
+				height, chain.TotalHeight)
+		}
+
+		last := chain.StartHeight + uint64(len(chain.BlockIDs)) - 1
+		if last < height && len(chain.BlockIDs) == 1 {
+			return fmt.Errorf("no progress past height %d", last)
+		}
+
+		if last >= height {
+			end := last
+			if end > to {
+				end = to
+			}
+
+			ids := chain.BlockIDs[height-chain.StartHeight : end-chain.StartHeight+1]
+			if err := c.getBlocks(ctx, height, ids, fn); err != nil {
+				return err
+			}
+
+			height = end + 1
+		}
+
+		history = []Hash{chain.BlockIDs[len(chain.BlockIDs)-1], genesis}
+	}
+
+	return nil
+}
+
+// getBlocks fetches the blocks `ids` (starting at height `height`) in
+// batches of at most MaxObjectRequestCount.
+//
+func (c *Client) getBlocks(ctx context.Context, height uint64, ids []Hash, fn BlockHandler) error {
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > MaxObjectRequestCount {
+			batch = batch[:MaxObjectRequestCount]
+		}
+
+		resp, err := c.GetObjects(ctx, batch)
+		if err != nil {
+			return fmt.Errorf("get objects: %w", err)
+		}
+
+		if len(resp.MissedIDs) > 0 {
+			return fmt.Errorf("peer missing %d blocks (%s, ...)",
+				len(resp.MissedIDs), resp.MissedIDs[0])
+		}
+
+		if len(resp.Blocks) != len(batch) {
+			return fmt.Errorf("asked for %d blocks, got %d",
+				len(batch), len(resp.Blocks))
+		}
+
+		for idx := range resp.Blocks {
+			if err := fn(height, &resp.Blocks[idx]); err != nil {
+				return fmt.Errorf("handle block %d: %w", height, err)
+			}
+
+			height++
+		}
+
+		ids = ids[len(batch):]
+	}
+
+	return nil
+}
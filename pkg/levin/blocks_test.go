@@ -0,0 +1,175 @@
+package levin_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+// chainPeer is a fake peer that serves a chain of `height` blocks whose ids
+// are `hash(height)` (except for the genesis one), sending at most
+// `entries` ids per chain entry.
+//
+type chainPeer struct {
+	*fakePeer
+
+	height  int
+	entries int
+	genesis levin.Hash
+}
+
+func (p *chainPeer) id(height int) levin.Hash {
+	if height == 0 {
+		return p.genesis
+	}
+
+	return hash(byte(height))
+}
+
+func (p *chainPeer) heightOf(id levin.Hash) int {
+	for height := 0; height < p.height; height++ {
+		if p.id(height) == id {
+			return height
+		}
+	}
+
+	return -1
+}
+
+func (p *chainPeer) serve() {
+	for {
+		header, payload, err := p.tryRead()
+		if err != nil {
+			return
+		}
+
+		n, err := levin.DecodeNotification(header.Command, payload)
+		require.NoError(p.t, err)
+
+		var resp levin.Notification
+
+		switch n := n.(type) {
+		case *levin.RequestChain:
+			start := p.heightOf(n.BlockIDs[0])
+			require.NotEqual(p.t, -1, start)
+
+			entry := &levin.ResponseChainEntry{
+				StartHeight: uint64(start),
+				TotalHeight: uint64(p.height),
+			}
+
+			for height := start; height < p.height && height < start+p.entries; height++ {
+				entry.BlockIDs = append(entry.BlockIDs, p.id(height))
+			}
+
+			resp = entry
+		case *levin.RequestGetObjects:
+			objects := &levin.ResponseGetObjects{}
+
+			for _, id := range n.Blocks {
+				objects.Blocks = append(objects.Blocks, levin.BlockCompleteEntry{
+					Block: []byte{byte(p.heightOf(id))},
+				})
+			}
+
+			resp = objects
+		}
+
+		payload, err = levin.Marshal(resp)
+		require.NoError(p.t, err)
+
+		p.write(levin.NewNotificationHeader(resp.Command(), uint64(len(payload))), payload)
+	}
+}
+
+// nolint:funlen
+func TestBlocks(t *testing.T) {
+	spec.Run(t, "GetBlocks", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			client *levin.Client
+			ctx    context.Context
+			cancel context.CancelFunc
+			remote net.Conn
+		)
+
+		it.Before(func() {
+			var local net.Conn
+			local, remote = net.Pipe()
+
+			var err error
+			client, err = levin.NewClientFromConn(local)
+			require.NoError(t, err)
+
+			genesis, err := levin.GenesisHash(monero.NetworkMainnet)
+			require.NoError(t, err)
+
+			peer := &chainPeer{
+				fakePeer: &fakePeer{t: t, conn: remote},
+				height:   10,
+				entries:  4,
+				genesis:  genesis,
+			}
+
+			go peer.serve()
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		})
+
+		it.After(func() {
+			cancel()
+			client.Close()
+			remote.Close()
+		})
+
+		collect := func(from, to uint64) ([]uint64, error) {
+			heights := []uint64{}
+
+			err := client.GetBlocks(ctx, from, to, func(height uint64, block *levin.BlockCompleteEntry) error {
+				assert.Equal(t, []byte{byte(height)}, block.Block)
+				heights = append(heights, height)
+
+				return nil
+			})
+
+			return heights, err
+		}
+
+		it("walks the chain across multiple entries", func() {
+			heights, err := collect(2, 7)
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{2, 3, 4, 5, 6, 7}, heights)
+		})
+
+		it("caps at the peer's height", func() {
+			heights, err := collect(8, 100)
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{8, 9}, heights)
+		})
+
+		it("fetches the genesis block", func() {
+			heights, err := collect(0, 0)
+			require.NoError(t, err)
+			assert.Equal(t, []uint64{0}, heights)
+		})
+
+		it("fails beyond the peer's height", func() {
+			_, err := collect(20, 30)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "beyond the peer's chain")
+		})
+
+		it("fails w/ from after to", func() {
+			_, err := collect(5, 4)
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
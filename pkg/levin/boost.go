@@ -3,6 +3,7 @@ package levin
 import (
 	"encoding/binary"
 	"fmt"
+	"math"
 )
 
 const (
@@ -35,6 +36,62 @@ func (v BoostByte) Bytes() []byte {
 	}
 }
 
+type BoostInt8 int8
+
+func (v BoostInt8) Bytes() []byte {
+	return []byte{
+		BoostSerializeTypeInt8,
+		byte(v),
+	}
+}
+
+type BoostInt16 int16
+
+func (v BoostInt16) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeInt16,
+		0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint16(b[1:], uint16(v))
+	return b
+}
+
+type BoostInt32 int32
+
+func (v BoostInt32) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeInt32,
+		0x00, 0x00, 0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint32(b[1:], uint32(v))
+	return b
+}
+
+type BoostInt64 int64
+
+func (v BoostInt64) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeInt64,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	binary.LittleEndian.PutUint64(b[1:], uint64(v))
+
+	return b
+}
+
+type BoostUint16 uint16
+
+func (v BoostUint16) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeUint16,
+		0x00, 0x00,
+	}
+	binary.LittleEndian.PutUint16(b[1:], uint16(v))
+	return b
+}
+
 type BoostUint32 uint32
 
 func (v BoostUint32) Bytes() []byte {
@@ -60,6 +117,20 @@ func (v BoostUint64) Bytes() []byte {
 	return b
 }
 
+type BoostDouble float64
+
+func (v BoostDouble) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeDouble,
+		0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0x00, 0x00,
+	}
+
+	binary.LittleEndian.PutUint64(b[1:], math.Float64bits(float64(v)))
+
+	return b
+}
+
 type BoostString string
 
 func (v BoostString) Bytes() []byte {
@@ -72,3 +143,53 @@ func (v BoostString) Bytes() []byte {
 
 	return append(b, append(varInB, []byte(v)...)...)
 }
+
+type BoostBool bool
+
+func (v BoostBool) Bytes() []byte {
+	b := []byte{
+		BoostSerializeTypeBool,
+		0x00,
+	}
+
+	if v {
+		b[1] = 0x01
+	}
+
+	return b
+}
+
+// BoostArray is a homogeneous sequence of serializables all of type `Type`.
+//
+// Arrays of arrays are represented with `Type` set to
+// `BoostSerializeTypeArray` and `BoostArray`s as values.
+//
+type BoostArray struct {
+	Type   byte
+	Values []Serializable
+}
+
+func (v BoostArray) Bytes() []byte {
+	b := []byte{v.Type | BoostSerializeFlagArray}
+
+	varInB, err := VarIn(len(v.Values))
+	if err != nil {
+		panic(fmt.Errorf("varin '%d': %w", len(v.Values), err))
+	}
+
+	b = append(b, varInB...)
+	for _, value := range v.Values {
+		// the type marker is written only once for the whole array,
+		// not for each element - except for arrays of arrays, where
+		// each inner array declares the type of its own elements.
+		//
+		if v.Type == BoostSerializeTypeArray {
+			b = append(b, value.Bytes()...)
+			continue
+		}
+
+		b = append(b, value.Bytes()[1:]...)
+	}
+
+	return b
+}
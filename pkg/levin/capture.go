@@ -0,0 +1,681 @@
+package levin
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// see https://www.tcpdump.org/manpages/pcap-savefile.5.txt
+// see https://www.ietf.org/archive/id/draft-tuexen-opsawg-pcapng-05.html
+// see https://www.tcpdump.org/linktypes.html
+
+const (
+	pcapMagicMicroseconds uint32 = 0xa1b2c3d4
+	pcapMagicNanoseconds  uint32 = 0xa1b23c4d
+	pcapngMagic           uint32 = 0x0a0d0d0a
+	pcapngByteOrderMagic  uint32 = 0x1a2b3c4d
+
+	pcapngBlockInterfaceDescription uint32 = 0x00000001
+	pcapngBlockSimplePacket         uint32 = 0x00000003
+	pcapngBlockEnhancedPacket       uint32 = 0x00000006
+
+	pcapngOptionEnd      uint16 = 0
+	pcapngOptionTSResol  uint16 = 9
+	pcapMaxCaptureLength uint32 = 64 * 1024 * 1024
+
+	linkTypeNull     uint32 = 0
+	linkTypeEthernet uint32 = 1
+	linkTypeRaw      uint32 = 101
+	linkTypeLinuxSLL uint32 = 113
+	linkTypeIPv4     uint32 = 228
+	linkTypeIPv6     uint32 = 229
+	linkTypeSLL2     uint32 = 276
+
+	etherTypeIPv4  uint16 = 0x0800
+	etherTypeIPv6  uint16 = 0x86dd
+	etherTypeVLAN  uint16 = 0x8100
+	etherTypeQinQ  uint16 = 0x88a8
+	ipProtocolTCP  byte   = 6
+	tcpFlagSYN     byte   = 0x02
+	ipv4MoreFrags  uint16 = 0x2000
+	ipv4FragOffset uint16 = 0x1fff
+)
+
+// ErrUnknownCapture indicates that a capture is in a format that's not
+// supported.
+//
+var ErrUnknownCapture = errors.New("unknown capture format")
+
+// DecodeCapture decodes the levin messages exchanged in the TCP connections
+// captured in `r`, handing them to `fn` in the order they've been fully
+// received.
+//
+// `r` can either be a pcap or pcapng capture (e.g., from `tcpdump -w`), or a
+// raw dump of one side of a TCP connection (see DecodeStream).
+//
+func DecodeCapture(r io.Reader, fn func(*DecodedMessage) error) error {
+	br := bufio.NewReader(r)
+
+	magic, err := br.Peek(4)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("peek: %w", err)
+	}
+
+	if len(magic) < 4 {
+		return DecodeStream(br, fn)
+	}
+
+	assembler := newTCPAssembler()
+
+	switch m := binary.LittleEndian.Uint32(magic); {
+	case m == pcapngMagic:
+		err = readPcapng(br, assembler.add)
+	case isPcapMagic(m) || isPcapMagic(swap32(m)):
+		err = readPcap(br, assembler.add)
+	default:
+		return DecodeStream(br, fn)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range assembler.messages() {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// capturedPacket is a link-layer frame captured at a given time.
+//
+type capturedPacket struct {
+	Time     time.Time
+	LinkType uint32
+	Data     []byte
+}
+
+func isPcapMagic(m uint32) bool {
+	return m == pcapMagicMicroseconds || m == pcapMagicNanoseconds
+}
+
+func swap32(v uint32) uint32 {
+	return v>>24 | (v>>8)&0xff00 | (v<<8)&0xff0000 | v<<24
+}
+
+// readPcap reads the packets of a pcap capture, handing each one of them to
+// `fn`.
+//
+func readPcap(r io.Reader, fn func(*capturedPacket)) error {
+	header := make([]byte, 24)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fmt.Errorf("read header: %w", err)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if !isPcapMagic(order.Uint32(header)) {
+		order = binary.BigEndian
+	}
+
+	var (
+		nanoseconds = order.Uint32(header) == pcapMagicNanoseconds
+		linkType    = order.Uint32(header[20:]) & 0xffff
+		record      = make([]byte, 16)
+	)
+
+	for {
+		if _, err := io.ReadFull(r, record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read record header: %w", err)
+		}
+
+		var (
+			sec      = order.Uint32(record[0:])
+			frac     = order.Uint32(record[4:])
+			inclLen  = order.Uint32(record[8:])
+			fracNano = int64(frac) * 1000
+		)
+
+		if nanoseconds {
+			fracNano = int64(frac)
+		}
+
+		if inclLen > pcapMaxCaptureLength {
+			return fmt.Errorf("record of %d bytes over max of %d",
+				inclLen, pcapMaxCaptureLength)
+		}
+
+		data := make([]byte, inclLen)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return fmt.Errorf("read record: %w", err)
+		}
+
+		fn(&capturedPacket{
+			Time:     time.Unix(int64(sec), fracNano).UTC(),
+			LinkType: linkType,
+			Data:     data,
+		})
+	}
+}
+
+type pcapngInterface struct {
+	linkType uint32
+
+	// unitsPerSecond is the resolution of the timestamps of the packets
+	// captured in the interface.
+	//
+	unitsPerSecond uint64
+}
+
+// readPcapng reads the packets of a pcapng capture, handing each one of them
+// to `fn`.
+//
+func readPcapng(r io.Reader, fn func(*capturedPacket)) error {
+	var (
+		order      binary.ByteOrder = binary.LittleEndian
+		interfaces                  = []pcapngInterface{}
+		head                        = make([]byte, 8)
+	)
+
+	for {
+		if _, err := io.ReadFull(r, head); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read block header: %w", err)
+		}
+
+		blockType := order.Uint32(head)
+		if binary.LittleEndian.Uint32(head) == pcapngMagic {
+			blockType = pcapngMagic
+		}
+
+		// the byte order of a section is only known once its header
+		// has been read.
+		//
+		var rest []byte
+		if blockType == pcapngMagic {
+			magic := make([]byte, 4)
+			if _, err := io.ReadFull(r, magic); err != nil {
+				return fmt.Errorf("read byte order magic: %w", err)
+			}
+
+			order = binary.LittleEndian
+			if order.Uint32(magic) != pcapngByteOrderMagic {
+				order = binary.BigEndian
+			}
+
+			if order.Uint32(magic) != pcapngByteOrderMagic {
+				return fmt.Errorf("byte order magic %x: %w", magic, ErrUnknownCapture)
+			}
+
+			interfaces = interfaces[:0]
+			rest = magic
+		}
+
+		length := order.Uint32(head[4:])
+		if length < 12 || length%4 != 0 || length > pcapMaxCaptureLength {
+			return fmt.Errorf("block w/ invalid length %d", length)
+		}
+
+		block := make([]byte, int(length)-8)
+		copy(block, rest)
+
+		if _, err := io.ReadFull(r, block[len(rest):]); err != nil {
+			return fmt.Errorf("read block: %w", err)
+		}
+
+		body := block[:len(block)-4]
+
+		switch blockType {
+		case pcapngBlockInterfaceDescription:
+			if len(body) < 8 {
+				return fmt.Errorf("interface description block: %w", ErrTruncated)
+			}
+
+			interfaces = append(interfaces, pcapngInterface{
+				linkType:       uint32(order.Uint16(body)),
+				unitsPerSecond: pcapngResolution(order, body[8:]),
+			})
+		case pcapngBlockEnhancedPacket:
+			if len(body) < 20 {
+				return fmt.Errorf("enhanced packet block: %w", ErrTruncated)
+			}
+
+			var (
+				id      = order.Uint32(body[0:])
+				ts      = uint64(order.Uint32(body[4:]))<<32 | uint64(order.Uint32(body[8:]))
+				capLen  = order.Uint32(body[12:])
+				capData = body[20:]
+			)
+
+			if int(id) >= len(interfaces) {
+				return fmt.Errorf("packet from unknown interface %d", id)
+			}
+
+			if uint64(capLen) > uint64(len(capData)) {
+				return fmt.Errorf("packet of %d bytes: %w", capLen, ErrTruncated)
+			}
+
+			iface := interfaces[id]
+
+			fn(&capturedPacket{
+				Time:     pcapngTime(ts, iface.unitsPerSecond),
+				LinkType: iface.linkType,
+				Data:     capData[:capLen],
+			})
+		case pcapngBlockSimplePacket:
+			if len(body) < 4 || len(interfaces) == 0 {
+				return fmt.Errorf("simple packet block: %w", ErrTruncated)
+			}
+
+			data := body[4:]
+			if origLen := order.Uint32(body); uint64(origLen) < uint64(len(data)) {
+				data = data[:origLen]
+			}
+
+			fn(&capturedPacket{
+				LinkType: interfaces[0].linkType,
+				Data:     data,
+			})
+		}
+	}
+}
+
+// pcapngResolution figures out the resolution of the timestamps of an
+// interface from its options, defaulting to microseconds.
+//
+func pcapngResolution(order binary.ByteOrder, options []byte) uint64 {
+	units := uint64(1_000_000)
+
+	for len(options) >= 4 {
+		var (
+			code   = order.Uint16(options)
+			length = int(order.Uint16(options[2:]))
+			padded = (length + 3) &^ 3
+		)
+
+		if code == pcapngOptionEnd || len(options[4:]) < padded {
+			break
+		}
+
+		if code == pcapngOptionTSResol && length == 1 {
+			resol := options[4]
+
+			exp := float64(resol & 0x7f)
+			base := 10.0
+			if resol&0x80 != 0 {
+				base = 2
+			}
+
+			if v := math.Pow(base, exp); v >= 1 && v < math.MaxInt64 {
+				units = uint64(v)
+			}
+		}
+
+		options = options[4+padded:]
+	}
+
+	return units
+}
+
+func pcapngTime(ts, unitsPerSecond uint64) time.Time {
+	var (
+		sec  = ts / unitsPerSecond
+		frac = float64(ts%unitsPerSecond) / float64(unitsPerSecond)
+	)
+
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// tcpSegment is the part of a TCP segment that matters for reassembling
+// the stream it's part of.
+//
+type tcpSegment struct {
+	Source      string
+	Destination string
+	Seq         uint32
+	SYN         bool
+	Payload     []byte
+}
+
+// parseTCPSegment extracts the TCP segment carried by a link-layer frame of
+// type `linkType`, if any.
+//
+func parseTCPSegment(linkType uint32, data []byte) (*tcpSegment, bool) {
+	var etherType uint16
+
+	switch linkType {
+	case linkTypeEthernet:
+		if len(data) < 14 {
+			return nil, false
+		}
+
+		etherType, data = binary.BigEndian.Uint16(data[12:]), data[14:]
+		for (etherType == etherTypeVLAN || etherType == etherTypeQinQ) && len(data) >= 4 {
+			etherType, data = binary.BigEndian.Uint16(data[2:]), data[4:]
+		}
+	case linkTypeLinuxSLL:
+		if len(data) < 16 {
+			return nil, false
+		}
+
+		etherType, data = binary.BigEndian.Uint16(data[14:]), data[16:]
+	case linkTypeSLL2:
+		if len(data) < 20 {
+			return nil, false
+		}
+
+		etherType, data = binary.BigEndian.Uint16(data[0:]), data[20:]
+	case linkTypeNull:
+		if len(data) < 4 {
+			return nil, false
+		}
+
+		// the address family is in the byte order of the host that
+		// captured the packets.
+		//
+		family := binary.LittleEndian.Uint32(data)
+		if family > 0xffff {
+			family = binary.BigEndian.Uint32(data)
+		}
+
+		data = data[4:]
+
+		switch family {
+		case 2:
+			etherType = etherTypeIPv4
+		case 10, 24, 28, 30:
+			etherType = etherTypeIPv6
+		}
+	case linkTypeRaw, linkTypeIPv4, linkTypeIPv6:
+		if len(data) < 1 {
+			return nil, false
+		}
+
+		switch data[0] >> 4 {
+		case 4:
+			etherType = etherTypeIPv4
+		case 6:
+			etherType = etherTypeIPv6
+		}
+	default:
+		return nil, false
+	}
+
+	var (
+		src, dst net.IP
+		ok       bool
+	)
+
+	switch etherType {
+	case etherTypeIPv4:
+		src, dst, data, ok = parseIPv4(data)
+	case etherTypeIPv6:
+		src, dst, data, ok = parseIPv6(data)
+	}
+
+	if !ok || len(data) < 20 {
+		return nil, false
+	}
+
+	offset := int(data[12]>>4) * 4
+	if offset < 20 || offset > len(data) {
+		return nil, false
+	}
+
+	srcPort := strconv.Itoa(int(binary.BigEndian.Uint16(data[0:])))
+	dstPort := strconv.Itoa(int(binary.BigEndian.Uint16(data[2:])))
+
+	return &tcpSegment{
+		Source:      net.JoinHostPort(src.String(), srcPort),
+		Destination: net.JoinHostPort(dst.String(), dstPort),
+		Seq:         binary.BigEndian.Uint32(data[4:]),
+		SYN:         data[13]&tcpFlagSYN != 0,
+		Payload:     data[offset:],
+	}, true
+}
+
+// parseIPv4 parses an IPv4 packet carrying TCP, returning the addresses and
+// the TCP segment. Fragmented packets are not supported.
+//
+func parseIPv4(data []byte) (net.IP, net.IP, []byte, bool) {
+	if len(data) < 20 || data[0]>>4 != 4 {
+		return nil, nil, nil, false
+	}
+
+	var (
+		headerLen = int(data[0]&0x0f) * 4
+		totalLen  = int(binary.BigEndian.Uint16(data[2:]))
+		frag      = binary.BigEndian.Uint16(data[6:])
+	)
+
+	if headerLen < 20 || totalLen < headerLen || totalLen > len(data) {
+		return nil, nil, nil, false
+	}
+
+	if data[9] != ipProtocolTCP || frag&(ipv4MoreFrags|ipv4FragOffset) != 0 {
+		return nil, nil, nil, false
+	}
+
+	return net.IP(data[12:16]), net.IP(data[16:20]), data[headerLen:totalLen], true
+}
+
+// parseIPv6 parses an IPv6 packet carrying TCP (possibly after hop-by-hop,
+// routing or destination options extension headers), returning the
+// addresses and the TCP segment.
+//
+func parseIPv6(data []byte) (net.IP, net.IP, []byte, bool) {
+	if len(data) < 40 || data[0]>>4 != 6 {
+		return nil, nil, nil, false
+	}
+
+	var (
+		next    = data[6]
+		length  = int(binary.BigEndian.Uint16(data[4:]))
+		src     = net.IP(data[8:24])
+		dst     = net.IP(data[24:40])
+		payload = data[40:]
+	)
+
+	if length > len(payload) {
+		return nil, nil, nil, false
+	}
+
+	payload = payload[:length]
+
+	for next != ipProtocolTCP {
+		switch next {
+		case 0, 43, 60:
+		default:
+			return nil, nil, nil, false
+		}
+
+		if len(payload) < 8 {
+			return nil, nil, nil, false
+		}
+
+		extLen := (int(payload[1]) + 1) * 8
+		if extLen > len(payload) {
+			return nil, nil, nil, false
+		}
+
+		next, payload = payload[0], payload[extLen:]
+	}
+
+	return src, dst, payload, true
+}
+
+// tcpFlow is the reassembled stream of bytes sent from one end of a TCP
+// connection to the other.
+//
+type tcpFlow struct {
+	source      string
+	destination string
+
+	data   []byte
+	next   uint32
+	synced bool
+
+	// pending holds segments received ahead of the ones that should
+	// come before them.
+	//
+	pending map[uint32][]byte
+
+	// marks keep track of when each chunk of `data` has been received.
+	//
+	marks []flowMark
+}
+
+type flowMark struct {
+	end  int
+	time time.Time
+}
+
+func (f *tcpFlow) add(segment *tcpSegment, t time.Time) {
+	seq := segment.Seq
+
+	if segment.SYN {
+		seq++
+
+		if len(f.data) == 0 {
+			f.next, f.synced = seq, true
+		}
+	}
+
+	// captures starting midway through a connection begin from
+	// whatever segment comes first.
+	//
+	if !f.synced {
+		f.next, f.synced = seq, true
+	}
+
+	if len(segment.Payload) == 0 {
+		return
+	}
+
+	if int32(seq-f.next) > 0 {
+		f.pending[seq] = append([]byte{}, segment.Payload...)
+		return
+	}
+
+	f.append(seq, segment.Payload, t)
+
+	for progress := true; progress; {
+		progress = false
+
+		for seq, payload := range f.pending {
+			if int32(seq-f.next) > 0 {
+				continue
+			}
+
+			delete(f.pending, seq)
+			f.append(seq, payload, t)
+			progress = true
+		}
+	}
+}
+
+// append appends to the stream whatever is new in the segment starting at
+// `seq`, which must not be ahead of the stream.
+//
+func (f *tcpFlow) append(seq uint32, payload []byte, t time.Time) {
+	overlap := int(f.next - seq)
+	if overlap >= len(payload) {
+		return
+	}
+
+	f.data = append(f.data, payload[overlap:]...)
+	f.next += uint32(len(payload) - overlap)
+	f.marks = append(f.marks, flowMark{end: len(f.data), time: t})
+}
+
+// timeAt retrieves the time at which the byte at `offset` has been received.
+//
+func (f *tcpFlow) timeAt(offset int) time.Time {
+	idx := sort.Search(len(f.marks), func(i int) bool {
+		return f.marks[i].end > offset
+	})
+	if idx == len(f.marks) {
+		idx = len(f.marks) - 1
+	}
+
+	return f.marks[idx].time
+}
+
+type tcpAssembler struct {
+	flows map[string]*tcpFlow
+
+	// order holds the flows in the order they've first been seen.
+	//
+	order []*tcpFlow
+}
+
+func newTCPAssembler() *tcpAssembler {
+	return &tcpAssembler{
+		flows: map[string]*tcpFlow{},
+	}
+}
+
+func (a *tcpAssembler) add(packet *capturedPacket) {
+	segment, ok := parseTCPSegment(packet.LinkType, packet.Data)
+	if !ok {
+		return
+	}
+
+	key := segment.Source + ">" + segment.Destination
+
+	flow, found := a.flows[key]
+	if !found {
+		flow = &tcpFlow{
+			source:      segment.Source,
+			destination: segment.Destination,
+			pending:     map[uint32][]byte{},
+		}
+
+		a.flows[key] = flow
+		a.order = append(a.order, flow)
+	}
+
+	flow.add(segment, packet.Time)
+}
+
+// messages decodes the messages from all of the flows, sorted by the time
+// they've been fully received.
+//
+func (a *tcpAssembler) messages() []*DecodedMessage {
+	messages := []*DecodedMessage{}
+
+	for _, flow := range a.order {
+		if len(flow.data) == 0 {
+			continue
+		}
+
+		for _, msg := range decodeStream(flow.data, flow.timeAt) {
+			msg.Source = flow.source
+			msg.Destination = flow.destination
+
+			messages = append(messages, msg)
+		}
+	}
+
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Time.Before(*messages[j].Time)
+	})
+
+	return messages
+}
@@ -0,0 +1,336 @@
+package levin_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+type pingResponse struct {
+	Status string `epee:"status"`
+	PeerID uint64 `epee:"peer_id"`
+}
+
+// tcpFrame builds an Ethernet frame carrying an IPv4 packet w/ a TCP segment
+// from `src` to `dst`.
+//
+func tcpFrame(src, dst *net.TCPAddr, seq uint32, flags byte, payload []byte) []byte {
+	tcp := make([]byte, 20)
+	binary.BigEndian.PutUint16(tcp[0:], uint16(src.Port))
+	binary.BigEndian.PutUint16(tcp[2:], uint16(dst.Port))
+	binary.BigEndian.PutUint32(tcp[4:], seq)
+	tcp[12] = 5 << 4
+	tcp[13] = flags
+	tcp = append(tcp, payload...)
+
+	ip := make([]byte, 20)
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], uint16(20+len(tcp)))
+	ip[8] = 64
+	ip[9] = 6
+	copy(ip[12:], src.IP.To4())
+	copy(ip[16:], dst.IP.To4())
+	ip = append(ip, tcp...)
+
+	eth := make([]byte, 14)
+	binary.BigEndian.PutUint16(eth[12:], 0x0800)
+
+	return append(eth, ip...)
+}
+
+type capturedFrame struct {
+	time time.Time
+	data []byte
+}
+
+func pcapCapture(frames []capturedFrame) []byte {
+	b := make([]byte, 24)
+	binary.LittleEndian.PutUint32(b[0:], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(b[4:], 2)
+	binary.LittleEndian.PutUint16(b[6:], 4)
+	binary.LittleEndian.PutUint32(b[16:], 65535)
+	binary.LittleEndian.PutUint32(b[20:], 1) // ethernet
+
+	for _, frame := range frames {
+		record := make([]byte, 16)
+		binary.LittleEndian.PutUint32(record[0:], uint32(frame.time.Unix()))
+		binary.LittleEndian.PutUint32(record[4:], uint32(frame.time.Nanosecond()/1000))
+		binary.LittleEndian.PutUint32(record[8:], uint32(len(frame.data)))
+		binary.LittleEndian.PutUint32(record[12:], uint32(len(frame.data)))
+
+		b = append(b, record...)
+		b = append(b, frame.data...)
+	}
+
+	return b
+}
+
+func pcapngBlock(blockType uint32, body []byte) []byte {
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+
+	length := uint32(12 + len(body))
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint32(b[0:], blockType)
+	binary.BigEndian.PutUint32(b[4:], length)
+	b = append(b, body...)
+
+	trailer := make([]byte, 4)
+	binary.BigEndian.PutUint32(trailer, length)
+
+	return append(b, trailer...)
+}
+
+// pcapngCapture builds a big-endian pcapng capture w/ an interface of
+// `linkType` w/ nanosecond resolution.
+//
+func pcapngCapture(linkType uint16, frames []capturedFrame) []byte {
+	shb := make([]byte, 16)
+	binary.BigEndian.PutUint32(shb[0:], 0x1a2b3c4d)
+	binary.BigEndian.PutUint16(shb[4:], 1)
+	binary.BigEndian.PutUint64(shb[8:], ^uint64(0))
+
+	idb := make([]byte, 8)
+	binary.BigEndian.PutUint16(idb[0:], linkType)
+	idb = append(idb, 0x00, 0x09, 0x00, 0x01, 0x09, 0x00, 0x00, 0x00) // if_tsresol
+	idb = append(idb, 0x00, 0x00, 0x00, 0x00)                         // opt_endofopt
+
+	b := pcapngBlock(0x0a0d0d0a, shb)
+	b = append(b, pcapngBlock(0x00000001, idb)...)
+
+	for _, frame := range frames {
+		ts := uint64(frame.time.UnixNano())
+
+		epb := make([]byte, 20)
+		binary.BigEndian.PutUint32(epb[4:], uint32(ts>>32))
+		binary.BigEndian.PutUint32(epb[8:], uint32(ts))
+		binary.BigEndian.PutUint32(epb[12:], uint32(len(frame.data)))
+		binary.BigEndian.PutUint32(epb[16:], uint32(len(frame.data)))
+
+		b = append(b, pcapngBlock(0x00000006, append(epb, frame.data...))...)
+	}
+
+	return b
+}
+
+func TestDecodeCapture(t *testing.T) {
+	spec.Run(t, "DecodeCapture", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			client = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 51000}
+			server = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 18080}
+			start  = time.Date(2022, 3, 1, 10, 0, 0, 0, time.UTC)
+
+			ping     []byte
+			response []byte
+		)
+
+		decode := func(b []byte) []*levin.DecodedMessage {
+			messages := []*levin.DecodedMessage{}
+
+			err := levin.DecodeCapture(bytes.NewReader(b), func(msg *levin.DecodedMessage) error {
+				messages = append(messages, msg)
+				return nil
+			})
+			require.NoError(t, err)
+
+			return messages
+		}
+
+		it.Before(func() {
+			ping = levin.NewRequestHeader(levin.CommandPing, 0).Bytes()
+
+			payload, err := levin.Marshal(&pingResponse{Status: "OK", PeerID: 1234})
+			require.NoError(t, err)
+
+			response = append(levin.NewResponseHeader(levin.CommandPing, uint64(len(payload)), 1).Bytes(), payload...)
+		})
+
+		when("reading a pcap capture", func() {
+			it("reassembles the streams in both directions", func() {
+				frames := []capturedFrame{
+					{start, tcpFrame(client, server, 99, 0x02, nil)},
+					{start.Add(1 * time.Millisecond), tcpFrame(server, client, 499, 0x12, nil)},
+					// ping split in two, out of order.
+					{start.Add(2 * time.Millisecond), tcpFrame(client, server, 110, 0x18, ping[10:])},
+					{start.Add(3 * time.Millisecond), tcpFrame(client, server, 100, 0x18, ping[:10])},
+					// response split in two, w/ a retransmission.
+					{start.Add(4 * time.Millisecond), tcpFrame(server, client, 500, 0x18, response[:40])},
+					{start.Add(5 * time.Millisecond), tcpFrame(server, client, 500, 0x18, response[:40])},
+					{start.Add(6 * time.Millisecond), tcpFrame(server, client, 540, 0x18, response[40:])},
+				}
+
+				messages := decode(pcapCapture(frames))
+				require.Len(t, messages, 2)
+
+				assert.Empty(t, messages[0].Error)
+				assert.Equal(t, "10.0.0.1:51000", messages[0].Source)
+				assert.Equal(t, "10.0.0.2:18080", messages[0].Destination)
+				assert.Equal(t, "COMMAND_PING", messages[0].Header.CommandName)
+				assert.True(t, messages[0].Header.ExpectsResponse)
+				assert.Equal(t, start.Add(3*time.Millisecond), *messages[0].Time)
+				assert.Nil(t, messages[0].Storage)
+
+				assert.Empty(t, messages[1].Error)
+				assert.Equal(t, "10.0.0.2:18080", messages[1].Source)
+				assert.Equal(t, levin.CommandPing, messages[1].Header.Command)
+				assert.Equal(t, int32(1), messages[1].Header.ReturnCode)
+				assert.Equal(t, start.Add(6*time.Millisecond), *messages[1].Time)
+				assert.Equal(t, map[string]interface{}{
+					"status":  "OK",
+					"peer_id": uint64(1234),
+				}, messages[1].Storage)
+			})
+
+			it("reports streams cut short", func() {
+				frames := []capturedFrame{
+					{start, tcpFrame(server, client, 500, 0x18, response[:40])},
+				}
+
+				messages := decode(pcapCapture(frames))
+				require.Len(t, messages, 1)
+				assert.Contains(t, messages[0].Error, "read message")
+			})
+		})
+
+		when("reading a pcapng capture", func() {
+			it("reassembles fragmented messages", func() {
+				payload, err := levin.Marshal(&levin.RequestChain{
+					BlockIDs: []levin.Hash{{0xaa}, {0xbb}},
+				})
+				require.NoError(t, err)
+
+				fragments, err := levin.Fragment(levin.NewNotificationHeader(levin.NotifyRequestChain, 0), payload, 100)
+				require.NoError(t, err)
+				require.Len(t, fragments, 200)
+
+				// capture starting midway through the connection.
+				//
+				frames := []capturedFrame{
+					{start, tcpFrame(client, server, 1000, 0x18, fragments[:100])[14:]},
+					{start.Add(time.Microsecond), tcpFrame(client, server, 1100, 0x18, fragments[100:])[14:]},
+				}
+
+				messages := decode(pcapngCapture(101, frames))
+				require.Len(t, messages, 1)
+
+				assert.Empty(t, messages[0].Error)
+				assert.Equal(t, "NOTIFY_REQUEST_CHAIN", messages[0].Header.CommandName)
+				assert.Equal(t, start.Add(time.Microsecond), *messages[0].Time)
+				assert.Equal(t, levin.DecodedBlob{
+					Hex: "aa00000000000000000000000000000000000000000000000000000000000000" +
+						"bb00000000000000000000000000000000000000000000000000000000000000",
+				}, messages[0].Storage["block_ids"])
+			})
+		})
+
+		when("reading a raw stream", func() {
+			it("decodes it after skipping to the first message", func() {
+				stream := append([]byte{0xde, 0xad, 0xbe, 0xef, 0x01}, response...)
+				stream = append(stream, ping...)
+
+				messages := decode(stream)
+				require.Len(t, messages, 2)
+
+				assert.Nil(t, messages[0].Time)
+				assert.Empty(t, messages[0].Source)
+				assert.Equal(t, "OK", messages[0].Storage["status"])
+				assert.Equal(t, "COMMAND_PING", messages[1].Header.CommandName)
+			})
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
+
+func TestDecodeMessage(t *testing.T) {
+	spec.Run(t, "DecodeMessage", func(t *testing.T, when spec.G, it spec.S) {
+		it("tells empty objects and arrays apart", func() {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{
+						Name:         "object",
+						Serializable: &levin.Section{},
+					},
+					{
+						Name:         "array",
+						Serializable: levin.BoostArray{Type: levin.BoostSerializeTypeUint64},
+					},
+					{
+						Name: "objects",
+						Serializable: levin.BoostArray{
+							Type:   levin.BoostSerializeTypeObject,
+							Values: []levin.Serializable{levin.Section{}},
+						},
+					},
+				},
+			}
+			payload := ps.Bytes()
+
+			msg := levin.DecodeMessage(
+				levin.NewNotificationHeader(levin.NotifyNewTransactions,
+					uint64(len(payload))), payload)
+			require.Empty(t, msg.Error)
+
+			assert.Equal(t, map[string]interface{}{}, msg.Storage["object"])
+			assert.Equal(t, []interface{}{}, msg.Storage["array"])
+			assert.Equal(t, []interface{}{map[string]interface{}{}},
+				msg.Storage["objects"])
+		})
+
+		it("tells binary strings apart from text", func() {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{Name: "text", Serializable: levin.BoostString("00ff")},
+					{Name: "blob", Serializable: levin.BoostString("\x00\xff")},
+				},
+			}
+			payload := ps.Bytes()
+
+			msg := levin.DecodeMessage(
+				levin.NewNotificationHeader(levin.NotifyNewTransactions,
+					uint64(len(payload))), payload)
+			require.Empty(t, msg.Error)
+
+			assert.Equal(t, "00ff", msg.Storage["text"])
+			assert.Equal(t, levin.DecodedBlob{Hex: "00ff"}, msg.Storage["blob"])
+
+			b, err := json.Marshal(msg.Storage)
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"text": "00ff", "blob": {"hex": "00ff"}}`, string(b))
+		})
+
+		it("gives back doubles that aren't finite as strings", func() {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{Name: "nan", Serializable: levin.BoostDouble(math.NaN())},
+					{Name: "inf", Serializable: levin.BoostDouble(math.Inf(-1))},
+					{Name: "pi", Serializable: levin.BoostDouble(3.14)},
+				},
+			}
+			payload := ps.Bytes()
+
+			msg := levin.DecodeMessage(
+				levin.NewNotificationHeader(levin.NotifyNewTransactions,
+					uint64(len(payload))), payload)
+			require.Empty(t, msg.Error)
+
+			assert.Equal(t, "NaN", msg.Storage["nan"])
+			assert.Equal(t, "-Inf", msg.Storage["inf"])
+			assert.Equal(t, 3.14, msg.Storage["pi"])
+
+			_, err := json.Marshal(msg)
+			assert.NoError(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -3,20 +3,131 @@ package levin
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
 	"fmt"
-	"io"
 	"net"
+	"sync"
 	"time"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
 )
 
 const DialTimeout = 15 * time.Second
 
+const (
+	// PingOkStatus is the status that peers reply to a COMMAND_PING with.
+	//
+	PingOkStatus = "OK"
+
+	// SupportFlagFluffyBlocks signals that a node is capable of receiving
+	// NOTIFY_NEW_FLUFFY_BLOCK.
+	//
+	SupportFlagFluffyBlocks uint32 = 0x01
+
+	// returnCodeSuccess is the return code that monerod handlers reply
+	// successful invocations with (anything <= 0 is taken as a failure by
+	// some of them).
+	//
+	returnCodeSuccess int32 = 1
+)
+
+// ErrClosed indicates that the connection to the peer has already been
+// closed.
+//
+var ErrClosed = errors.New("connection closed")
+
+// NotificationHandler is called from the read loop of a Client for every
+// notification received of the command it's been registered for.
+//
+// Handlers must not block: packets from the peer (including responses to
+// in-flight requests) are not read while a handler is running.
+//
+type NotificationHandler func(n Notification)
+
+// Client is a connection to a peer in the p2p network.
+//
+// A goroutine owns the reading side of the connection, correlating responses
+// to the requests that are waiting for them, dispatching notifications to
+// the handlers registered via `Handle`, and answering requests that the peer
+// makes to us (COMMAND_TIMED_SYNC, COMMAND_PING, and
+// COMMAND_REQUEST_SUPPORT_FLAGS).
+//
+// All methods are safe for concurrent use.
+//
 type Client struct {
-	conn net.Conn
+	conn   net.Conn
+	frames *FrameReader
+	local  localNode
+
+	writeMu sync.Mutex
+
+	mu sync.Mutex
+
+	// pending holds, for each command, the callers waiting for a
+	// response in the order that the requests have been sent: peers must
+	// respond to requests in the same order as they've been received.
+	//
+	pending  map[uint32][]chan packet
+	handlers map[uint32]NotificationHandler
+
+	// expected holds, for each notification command, the callers of
+	// `Request` waiting for a notification of that command, taking
+	// precedence over the handlers.
+	//
+	expected map[uint32][]chan packet
+
+	// handshaked indicates whether the handshake has already been
+	// completed, lifting the limit on the size of the packets.
+	//
+	handshaked bool
+
+	done chan struct{}
+	err  error
+}
+
+// localNode is what we announce about ourselves to the peer.
+//
+type localNode struct {
+	NodeData     NodeData
+	CoreSyncData CoreSyncData
+	Peers        []*Peer
+
+	// Genesis is the hash of the genesis block of the network, zero if
+	// not known.
+	//
+	Genesis Hash
+
+	// acceptHandshakes indicates whether we're the responder side of
+	// the connection, thus answering COMMAND_HANDSHAKE.
+	//
+	acceptHandshakes bool
+}
+
+type packet struct {
+	header  *Header
+	payload []byte
 }
 
 type ClientConfig struct {
 	ContextDialer ContextDialer
+
+	// Network is the network that the peer is expected to be part of.
+	//
+	Network monero.Network
+
+	// NodeData is what's announced to the peer as `node_data`. Network
+	// id, peer id and support flags are filled according to `Network`, at
+	// random, and w/ SupportFlagFluffyBlocks respectively if not set.
+	//
+	NodeData NodeData
+
+	// CoreSyncData is what's announced to the peer as `payload_data`.
+	// Defaults to the state of a node that only knows about the genesis
+	// block of `Network`.
+	//
+	CoreSyncData *CoreSyncData
 }
 
 type ClientOption func(*ClientConfig)
@@ -31,22 +142,144 @@ func WithContextDialer(v ContextDialer) func(*ClientConfig) {
 	}
 }
 
-func NewClient(ctx context.Context, addr string, opts ...ClientOption) (*Client, error) {
+func WithNetwork(v monero.Network) func(*ClientConfig) {
+	return func(c *ClientConfig) {
+		c.Network = v
+	}
+}
+
+func WithLocalNodeData(v NodeData) func(*ClientConfig) {
+	return func(c *ClientConfig) {
+		c.NodeData = v
+	}
+}
+
+func WithLocalCoreSyncData(v CoreSyncData) func(*ClientConfig) {
+	return func(c *ClientConfig) {
+		c.CoreSyncData = &v
+	}
+}
+
+func newClientConfig(opts ...ClientOption) *ClientConfig {
 	cfg := &ClientConfig{
 		ContextDialer: &net.Dialer{},
+		Network:       monero.NetworkMainnet,
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
 
+	return cfg
+}
+
+func NewClient(ctx context.Context, addr string, opts ...ClientOption) (*Client, error) {
+	cfg := newClientConfig(opts...)
+
+	if _, err := cfg.localNode(); err != nil {
+		return nil, fmt.Errorf("local node: %w", err)
+	}
+
 	conn, err := cfg.ContextDialer.DialContext(ctx, "tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("dial ctx: %w", err)
 	}
 
-	return &Client{
-		conn: conn,
-	}, nil
+	client, err := NewClientFromConn(conn, opts...)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("new client from conn: %w", err)
+	}
+
+	return client, nil
+}
+
+// NewClientFromConn instantiates a client that takes ownership of an already
+// established connection `conn`, starting to read from it right away.
+//
+func NewClientFromConn(conn net.Conn, opts ...ClientOption) (*Client, error) {
+	local, err := newClientConfig(opts...).localNode()
+	if err != nil {
+		return nil, fmt.Errorf("local node: %w", err)
+	}
+
+	client := newClient(conn, local)
+	client.start()
+
+	return client, nil
+}
+
+// localNode fills in the defaults for what we announce about ourselves.
+//
+func (cfg *ClientConfig) localNode() (localNode, error) {
+	local := localNode{
+		NodeData: cfg.NodeData,
+	}
+
+	if local.NodeData.NetworkID == nil {
+		networkID, err := NetworkId(cfg.Network)
+		if err != nil {
+			return local, fmt.Errorf("network id: %w", err)
+		}
+
+		local.NodeData.NetworkID = networkID
+	}
+
+	if local.NodeData.PeerID == 0 {
+		peerID, err := randomPeerID()
+		if err != nil {
+			return local, fmt.Errorf("random peer id: %w", err)
+		}
+
+		local.NodeData.PeerID = peerID
+	}
+
+	if local.NodeData.SupportFlags == 0 {
+		local.NodeData.SupportFlags = SupportFlagFluffyBlocks
+	}
+
+	genesis, err := GenesisHash(cfg.Network)
+	if err != nil {
+		return local, fmt.Errorf("genesis hash: %w", err)
+	}
+
+	local.Genesis = genesis
+
+	if cfg.CoreSyncData != nil {
+		local.CoreSyncData = *cfg.CoreSyncData
+		return local, nil
+	}
+
+	// we present ourselves as a node that only knows about the genesis
+	// block.
+	//
+	local.CoreSyncData = CoreSyncData{
+		CurrentHeight: 1,
+		TopID:         genesis,
+	}
+
+	return local, nil
+}
+
+func newClient(conn net.Conn, local localNode) *Client {
+	c := &Client{
+		conn:     conn,
+		frames:   NewFrameReader(conn),
+		local:    local,
+		pending:  map[uint32][]chan packet{},
+		handlers: map[uint32]NotificationHandler{},
+		expected: map[uint32][]chan packet{},
+		done:     make(chan struct{}),
+	}
+
+	return c
+}
+
+// start starts reading from the connection, dispatching what's read to
+// those waiting for it and to the notification handlers - any handler
+// registered before this is guaranteed to see every notification.
+//
+func (c *Client) start() {
+	go c.readLoop()
 }
 
 func (c *Client) Close() error {
@@ -54,6 +287,8 @@ func (c *Client) Close() error {
 		return nil
 	}
 
+	c.shutdown(ErrClosed)
+
 	if err := c.conn.Close(); err != nil {
 		return fmt.Errorf("close: %w", err)
 	}
@@ -61,84 +296,490 @@ func (c *Client) Close() error {
 	return nil
 }
 
-func (c *Client) Handshake(ctx context.Context) (*Node, error) {
-	payload := (&PortableStorage{
-		Entries: []Entry{
-			{
-				Name: "node_data",
-				Serializable: &Section{
-					Entries: []Entry{
-						{
-							Name:         "network_id",
-							Serializable: BoostString(string(MainnetNetworkId)),
-						},
-					},
-				},
-			},
-		},
-	}).Bytes()
+// Done is closed once the connection is no longer usable, either because it's
+// been closed or because reading from it failed.
+//
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err is the reason why the connection stopped being usable, only set once
+// `Done` is closed.
+//
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+// Handle registers `handler` to be called for every notification of command
+// `command` received from the peer, replacing any previously registered one.
+//
+func (c *Client) Handle(command uint32, handler NotificationHandler) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.handlers[command] = handler
+}
+
+// Invoke sends a request of command `command` to the peer and waits for its
+// response, returning its header and payload.
+//
+func (c *Client) Invoke(ctx context.Context, command uint32, payload []byte) (*Header, []byte, error) {
+	ch := make(chan packet, 1)
 
-	reqHeaderB := NewRequestHeader(CommandHandshake, uint64(len(payload))).Bytes()
+	// registering the waiter and writing the request must happen
+	// atomically, otherwise concurrent callers of the same command could
+	// end up with their responses swapped.
+	//
+	c.writeMu.Lock()
 
-	if _, err := c.conn.Write(reqHeaderB); err != nil {
-		return nil, fmt.Errorf("write header: %w", err)
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		c.writeMu.Unlock()
+
+		return nil, nil, err
 	}
+	c.pending[command] = append(c.pending[command], ch)
+	c.mu.Unlock()
+
+	err := c.writePacket(ctx, NewRequestHeader(command, uint64(len(payload))), payload)
+	c.writeMu.Unlock()
 
-	if _, err := c.conn.Write(payload); err != nil {
-		return nil, fmt.Errorf("write payload: %w", err)
+	if err != nil {
+		return nil, nil, fmt.Errorf("write packet: %w", err)
 	}
 
-again:
-	responseHeaderB := make([]byte, LevinHeaderSizeBytes)
-	if _, err := io.ReadFull(c.conn, responseHeaderB); err != nil {
-		return nil, fmt.Errorf("read full header: %w", err)
+	select {
+	case resp := <-ch:
+		if resp.header.ReturnCode < 0 {
+			return nil, nil, fmt.Errorf("command %d: return code %d",
+				command, resp.header.ReturnCode)
+		}
+
+		return resp.header, resp.payload, nil
+	case <-c.done:
+		return nil, nil, c.Err()
+	case <-ctx.Done():
+		// the waiter is left in place so that the response, once
+		// it arrives, still consumes it.
+		//
+		return nil, nil, ctx.Err()
 	}
+}
+
+// Notify sends notification `n` to the peer.
+//
+func (c *Client) Notify(ctx context.Context, n Notification) error {
+	payload, err := Marshal(n)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := NewNotificationHeader(n.Command(), uint64(len(payload)))
+	if err := c.writePacket(ctx, header, payload); err != nil {
+		return fmt.Errorf("write packet: %w", err)
+	}
+
+	return nil
+}
 
-	respHeader, err := NewHeaderFromBytesBytes(responseHeaderB)
+// Request sends notification `n` to the peer and waits for the next
+// notification of command `response` that it sends back.
+//
+// Differently from `Invoke`, there's nothing in the protocol tying the
+// notifications together: this relies on peers answering notifications
+// like NOTIFY_REQUEST_CHAIN and NOTIFY_REQUEST_GET_OBJECTS in the order
+// they've been received.
+//
+func (c *Client) Request(ctx context.Context, n Notification, response uint32) (Notification, error) {
+	payload, err := Marshal(n)
 	if err != nil {
-		return nil, fmt.Errorf("new header from resp bytes: %w", err)
+		return nil, fmt.Errorf("marshal: %w", err)
 	}
 
-	dest := new(bytes.Buffer)
+	ch := make(chan packet, 1)
 
-	if respHeader.Length != 0 {
-		if _, err := io.CopyN(dest, c.conn, int64(respHeader.Length)); err != nil {
-			return nil, fmt.Errorf("copy payload to stdout: %w", err)
+	c.writeMu.Lock()
+
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		c.writeMu.Unlock()
+
+		return nil, err
+	}
+	c.expected[response] = append(c.expected[response], ch)
+	c.mu.Unlock()
+
+	header := NewNotificationHeader(n.Command(), uint64(len(payload)))
+	err = c.writePacket(ctx, header, payload)
+	c.writeMu.Unlock()
+
+	if err != nil {
+		return nil, fmt.Errorf("write packet: %w", err)
+	}
+
+	select {
+	case resp := <-ch:
+		notification, err := DecodeNotification(response, resp.payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode notification: %w", err)
 		}
+
+		return notification, nil
+	case <-c.done:
+		return nil, c.Err()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// handshakeRequest is the body of a COMMAND_HANDSHAKE request.
+//
+type handshakeRequest struct {
+	NodeData    NodeData     `epee:"node_data"`
+	PayloadData CoreSyncData `epee:"payload_data"`
+}
+
+func (c *Client) Handshake(ctx context.Context) (*Node, error) {
+	req := handshakeRequest{
+		NodeData:    c.local.NodeData,
+		PayloadData: c.local.CoreSyncData,
+	}
+
+	payload, err := Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
 	}
 
-	if respHeader.Command != CommandHandshake {
-		dest.Reset()
-		goto again
+	_, respPayload, err := c.Invoke(ctx, CommandHandshake, payload)
+	if err != nil {
+		return nil, fmt.Errorf("invoke: %w", err)
 	}
 
-	ps, err := NewPortableStorageFromBytes(dest.Bytes())
+	ps, err := NewPortableStorageFromBytes(respPayload)
 	if err != nil {
 		return nil, fmt.Errorf("new portable storage from bytes: %w", err)
 	}
 
-	peerList := NewNodeFromEntries(ps.Entries)
-	return &peerList, nil
+	node, err := NewNodeFromEntries(ps.Entries)
+	if err != nil {
+		return nil, fmt.Errorf("new node from entries: %w", err)
+	}
+
+	if !bytes.Equal(node.NodeData.NetworkID, c.local.NodeData.NetworkID) {
+		return nil, fmt.Errorf("network id mismatch: %x",
+			node.NodeData.NetworkID)
+	}
+
+	node.SupportFlags, err = c.SupportFlags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("support flags: %w", err)
+	}
+
+	return &node, nil
+}
+
+// SupportFlags asks the peer for the features it supports
+// (COMMAND_REQUEST_SUPPORT_FLAGS).
+//
+func (c *Client) SupportFlags(ctx context.Context) (uint32, error) {
+	_, payload, err := c.Invoke(ctx, CommandSupportFlags, nil)
+	if err != nil {
+		return 0, fmt.Errorf("invoke: %w", err)
+	}
+
+	resp := supportFlagsResponse{}
+	if err := Unmarshal(payload, &resp); err != nil {
+		return 0, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return resp.SupportFlags, nil
+}
+
+// pingResponse is the body of a COMMAND_PING response.
+//
+type pingResponse struct {
+	Status string `epee:"status"`
+	PeerID uint64 `epee:"peer_id"`
+}
+
+// timedSyncResponse is the body of a COMMAND_TIMED_SYNC response.
+//
+type timedSyncResponse struct {
+	PayloadData      CoreSyncData    `epee:"payload_data"`
+	LocalPeerlistNew []peerListEntry `epee:"local_peerlist_new"`
+}
+
+// supportFlagsResponse is the body of a COMMAND_REQUEST_SUPPORT_FLAGS
+// response.
+//
+type supportFlagsResponse struct {
+	SupportFlags uint32 `epee:"support_flags"`
 }
 
 func (c *Client) Ping(ctx context.Context) error {
-	reqHeaderB := NewRequestHeader(CommandPing, 0).Bytes()
+	_, payload, err := c.Invoke(ctx, CommandPing, nil)
+	if err != nil {
+		return fmt.Errorf("invoke: %w", err)
+	}
 
-	if _, err := c.conn.Write(reqHeaderB); err != nil {
-		return fmt.Errorf("write: %w", err)
+	resp := pingResponse{}
+	if err := Unmarshal(payload, &resp); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
 	}
 
-	responseHeaderB := make([]byte, LevinHeaderSizeBytes)
-	if _, err := io.ReadFull(c.conn, responseHeaderB); err != nil {
-		return fmt.Errorf("read full header: %w", err)
+	if resp.Status != PingOkStatus {
+		return fmt.Errorf("unexpected status '%s'", resp.Status)
 	}
 
-	respHeader, err := NewHeaderFromBytesBytes(responseHeaderB)
+	return nil
+}
+
+func (c *Client) readLoop() {
+	for {
+		header, payload, err := c.readPacket()
+		if err != nil {
+			c.shutdown(fmt.Errorf("read packet: %w", err))
+			return
+		}
+
+		if err := c.dispatch(header, payload); err != nil {
+			c.shutdown(fmt.Errorf("dispatch: %w", err))
+			c.conn.Close()
+
+			return
+		}
+	}
+}
+
+// dispatch routes a packet received from the peer to whoever should deal
+// with it: the caller waiting for a response, the handler of a notification,
+// or ourselves in the case of requests that we know how to answer.
+//
+func (c *Client) dispatch(header *Header, payload []byte) error {
+	if header.IsResponse() {
+		c.mu.Lock()
+		waiters := c.pending[header.Command]
+		if len(waiters) == 0 {
+			// unsolicited: nobody to hand it to.
+			//
+			c.mu.Unlock()
+			return nil
+		}
+
+		ch := waiters[0]
+		c.pending[header.Command] = waiters[1:]
+
+		// lift the limit before reading the next packet, which may
+		// already be a large one (e.g., a block) right after the
+		// handshake.
+		//
+		if header.Command == CommandHandshake && header.ReturnCode >= 0 {
+			c.handshaked = true
+		}
+		c.mu.Unlock()
+
+		ch <- packet{header: header, payload: payload}
+		return nil
+	}
+
+	if header.ExpectsResponse {
+		return c.respond(header.Command, payload)
+	}
+
+	c.mu.Lock()
+	if waiters := c.expected[header.Command]; len(waiters) > 0 {
+		c.expected[header.Command] = waiters[1:]
+		c.mu.Unlock()
+
+		waiters[0] <- packet{header: header, payload: payload}
+		return nil
+	}
+
+	handler, found := c.handlers[header.Command]
+	c.mu.Unlock()
+
+	if !found {
+		return nil
+	}
+
+	notification, err := DecodeNotification(header.Command, payload)
 	if err != nil {
-		return fmt.Errorf("new header from resp bytes: %w", err)
+		return fmt.Errorf("decode notification: %w", err)
 	}
 
-	fmt.Printf("%+v\n", respHeader)
+	handler(notification)
+	return nil
+}
+
+// respond answers a request made by the peer.
+//
+func (c *Client) respond(command uint32, payload []byte) error {
+	var resp interface{}
+
+	switch command {
+	case CommandHandshake:
+		if !c.local.acceptHandshakes {
+			break
+		}
+
+		req := handshakeRequest{}
+		if err := Unmarshal(payload, &req); err != nil {
+			return fmt.Errorf("unmarshal handshake: %w", err)
+		}
+
+		if !bytes.Equal(req.NodeData.NetworkID, c.local.NodeData.NetworkID) {
+			return fmt.Errorf("network id mismatch: %x",
+				req.NodeData.NetworkID)
+		}
+
+		resp = handshakeResponse{
+			NodeData:         c.local.NodeData,
+			PayloadData:      c.local.CoreSyncData,
+			LocalPeerlistNew: peerListFromPeers(c.local.Peers),
+		}
+	case CommandPing:
+		resp = pingResponse{Status: PingOkStatus, PeerID: c.local.NodeData.PeerID}
+	case CommandTimedSync:
+		resp = timedSyncResponse{
+			PayloadData:      c.local.CoreSyncData,
+			LocalPeerlistNew: peerListFromPeers(c.local.Peers),
+		}
+	case CommandSupportFlags:
+		resp = supportFlagsResponse{SupportFlags: c.local.NodeData.SupportFlags}
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if resp == nil {
+		header := NewResponseHeader(command, 0, LevinErrorConnectionHandlerNotDefined)
+		return c.writePacket(context.Background(), header, nil)
+	}
+
+	respPayload, err := Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	header := NewResponseHeader(command, uint64(len(respPayload)), returnCodeSuccess)
+	if err := c.writePacket(context.Background(), header, respPayload); err != nil {
+		return err
+	}
+
+	if command == CommandHandshake {
+		c.mu.Lock()
+		c.handshaked = true
+		c.mu.Unlock()
+	}
+
+	return nil
+}
+
+// writePacket writes a full levin packet to the connection, giving up once
+// `ctx` is done.
+//
+// As a partially written packet leaves the stream in an unrecoverable state,
+// any failure shuts the connection down.
+//
+// Callers must hold `writeMu`.
+//
+func (c *Client) writePacket(ctx context.Context, header *Header, payload []byte) error {
+	err := c.write(ctx, append(header.Bytes(), payload...))
+	if err != nil {
+		c.shutdown(fmt.Errorf("write: %w", err))
+		c.conn.Close()
+
+		return err
+	}
+
+	return nil
+}
+
+func (c *Client) write(ctx context.Context, b []byte) error {
+	deadline, _ := ctx.Deadline()
+	if err := c.conn.SetWriteDeadline(deadline); err != nil {
+		return fmt.Errorf("set write deadline: %w", err)
+	}
+
+	if ctx.Done() != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				_ = c.conn.SetWriteDeadline(time.Unix(1, 0))
+			case <-stop:
+			}
+		}()
+	}
+
+	if _, err := c.conn.Write(b); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		return fmt.Errorf("write: %w", err)
+	}
 
 	return nil
 }
+
+// readPacket reads a full levin message from the connection (reassembling
+// it if fragmented), making sure that the peer can't get us to allocate more
+// than what's allowed for the current stage of the connection (before or
+// after the handshake).
+//
+func (c *Client) readPacket() (*Header, []byte, error) {
+	c.mu.Lock()
+	maxSize := LevinPacketMaxDefaultSize
+	if !c.handshaked {
+		maxSize = LevinPacketMaxInitialSize
+	}
+	c.mu.Unlock()
+
+	c.frames.MaxSize = maxSize
+
+	header, payload, err := c.frames.ReadMessage()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read message: %w", err)
+	}
+
+	return header, payload, nil
+}
+
+// shutdown marks the client as no longer usable due to `err`, waking up
+// anyone waiting for responses. Only the first call has any effect.
+//
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil {
+		return
+	}
+
+	c.err = err
+	c.pending = map[uint32][]chan packet{}
+	c.expected = map[uint32][]chan packet{}
+	close(c.done)
+}
+
+func randomPeerID() (uint64, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return 0, fmt.Errorf("read: %w", err)
+	}
+
+	return binary.LittleEndian.Uint64(b), nil
+}
@@ -0,0 +1,424 @@
+package levin_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+// fakePeer is the remote end of a connection w/ a levin.Client.
+//
+type fakePeer struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+func (p *fakePeer) read() (*levin.Header, []byte) {
+	header, payload, err := p.tryRead()
+	require.NoError(p.t, err)
+
+	return header, payload
+}
+
+func (p *fakePeer) tryRead() (*levin.Header, []byte, error) {
+	headerB := make([]byte, levin.LevinHeaderSizeBytes)
+	if _, err := io.ReadFull(p.conn, headerB); err != nil {
+		return nil, nil, err
+	}
+
+	header, err := levin.NewHeaderFromBytesBytes(headerB)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	payload := make([]byte, header.Length)
+	if _, err := io.ReadFull(p.conn, payload); err != nil {
+		return nil, nil, err
+	}
+
+	return header, payload, nil
+}
+
+func (p *fakePeer) write(header *levin.Header, payload []byte) {
+	_, err := p.conn.Write(append(header.Bytes(), payload...))
+	require.NoError(p.t, err)
+}
+
+func (p *fakePeer) respond(command uint32, v interface{}) {
+	payload, err := levin.Marshal(v)
+	require.NoError(p.t, err)
+
+	p.write(levin.NewResponseHeader(command, uint64(len(payload)), 1), payload)
+}
+
+// nolint:funlen
+func TestClient(t *testing.T) {
+	spec.Run(t, "Client", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			client *levin.Client
+			peer   *fakePeer
+			ctx    context.Context
+			cancel context.CancelFunc
+		)
+
+		it.Before(func() {
+			local, remote := net.Pipe()
+
+			var err error
+			client, err = levin.NewClientFromConn(local)
+			require.NoError(t, err)
+
+			peer = &fakePeer{t: t, conn: remote}
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+		})
+
+		it.After(func() {
+			cancel()
+			client.Close()
+			peer.conn.Close()
+		})
+
+		type status struct {
+			Status string `epee:"status"`
+		}
+
+		it("correlates responses w/ requests of the same command", func() {
+			type result struct {
+				payload []byte
+				err     error
+			}
+
+			first, second := make(chan result), make(chan result)
+
+			go func() {
+				_, payload, err := client.Invoke(ctx, levin.CommandStat, []byte("first"))
+				first <- result{payload, err}
+			}()
+
+			header, payload := peer.read()
+			assert.Equal(t, levin.CommandStat, header.Command)
+			assert.True(t, header.ExpectsResponse)
+			assert.Equal(t, []byte("first"), payload)
+
+			go func() {
+				_, payload, err := client.Invoke(ctx, levin.CommandPeerID, []byte("second"))
+				second <- result{payload, err}
+			}()
+
+			_, payload = peer.read()
+			assert.Equal(t, []byte("second"), payload)
+
+			// responses to different commands may interleave.
+			//
+			peer.write(levin.NewResponseHeader(levin.CommandPeerID, 1, 1), []byte{0x02})
+			peer.write(levin.NewResponseHeader(levin.CommandStat, 1, 1), []byte{0x01})
+
+			r := <-second
+			require.NoError(t, r.err)
+			assert.Equal(t, []byte{0x02}, r.payload)
+
+			r = <-first
+			require.NoError(t, r.err)
+			assert.Equal(t, []byte{0x01}, r.payload)
+		})
+
+		it("fails w/ negative return code", func() {
+			errC := make(chan error)
+
+			go func() {
+				_, _, err := client.Invoke(ctx, levin.CommandStat, nil)
+				errC <- err
+			}()
+
+			peer.read()
+			peer.write(levin.NewResponseHeader(levin.CommandStat, 0,
+				levin.LevinErrorFormat), nil)
+
+			err := <-errC
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "return code -7")
+		})
+
+		it("gives up on the response once ctx is done", func() {
+			errC := make(chan error)
+			ctx, cancel := context.WithCancel(ctx)
+
+			go func() {
+				_, _, err := client.Invoke(ctx, levin.CommandStat, nil)
+				errC <- err
+			}()
+
+			peer.read()
+			cancel()
+			assert.ErrorIs(t, <-errC, context.Canceled)
+
+			// the late response is consumed w/out getting in the way of
+			// the next request.
+			//
+			go func() {
+				_, _, err := client.Invoke(context.Background(), levin.CommandStat, nil)
+				errC <- err
+			}()
+
+			peer.read()
+			peer.write(levin.NewResponseHeader(levin.CommandStat, 0, 1), nil)
+			peer.write(levin.NewResponseHeader(levin.CommandStat, 0, 1), nil)
+
+			assert.NoError(t, <-errC)
+		})
+
+		it("fails pending requests once the connection drops", func() {
+			errC := make(chan error)
+
+			go func() {
+				_, _, err := client.Invoke(ctx, levin.CommandStat, nil)
+				errC <- err
+			}()
+
+			peer.read()
+			peer.conn.Close()
+
+			assert.Error(t, <-errC)
+
+			<-client.Done()
+			assert.Error(t, client.Err())
+
+			_, _, err := client.Invoke(ctx, levin.CommandStat, nil)
+			assert.Error(t, err)
+		})
+
+		it("answers pings", func() {
+			peer.write(levin.NewRequestHeader(levin.CommandPing, 0), nil)
+
+			header, payload := peer.read()
+			assert.Equal(t, levin.CommandPing, header.Command)
+			assert.True(t, header.IsResponse())
+			assert.False(t, header.ExpectsResponse)
+
+			resp := status{}
+			require.NoError(t, levin.Unmarshal(payload, &resp))
+			assert.Equal(t, levin.PingOkStatus, resp.Status)
+		})
+
+		it("answers timed syncs", func() {
+			peer.write(levin.NewRequestHeader(levin.CommandTimedSync, 0), nil)
+
+			header, payload := peer.read()
+			assert.Equal(t, levin.CommandTimedSync, header.Command)
+			assert.True(t, header.IsResponse())
+
+			ps, err := levin.NewPortableStorageFromBytes(payload)
+			require.NoError(t, err)
+			require.Len(t, ps.Entries, 1)
+			assert.Equal(t, "payload_data", ps.Entries[0].Name)
+		})
+
+		it("refuses requests it can't handle", func() {
+			peer.write(levin.NewRequestHeader(levin.CommandNetworkState, 0), nil)
+
+			header, _ := peer.read()
+			assert.Equal(t, levin.CommandNetworkState, header.Command)
+			assert.Equal(t, levin.LevinErrorConnectionHandlerNotDefined, header.ReturnCode)
+		})
+
+		it("dispatches notifications to handlers", func() {
+			received := make(chan levin.Notification, 1)
+			client.Handle(levin.NotifyNewTransactions, func(n levin.Notification) {
+				received <- n
+			})
+
+			n := &levin.NewTransactions{
+				Txs:              [][]byte{{0x01}},
+				Padding:          []byte{0x00},
+				DandelionppFluff: true,
+			}
+			payload, err := levin.Marshal(n)
+			require.NoError(t, err)
+
+			peer.write(levin.NewNotificationHeader(n.Command(), uint64(len(payload))), payload)
+
+			select {
+			case got := <-received:
+				assert.Equal(t, n, got)
+			case <-ctx.Done():
+				t.Fatal("notification not dispatched")
+			}
+		})
+
+		it("sends notifications", func() {
+			n := &levin.RequestChain{BlockIDs: []levin.Hash{hash(0x01)}}
+
+			errC := make(chan error)
+			go func() {
+				errC <- client.Notify(ctx, n)
+			}()
+
+			header, payload := peer.read()
+			require.NoError(t, <-errC)
+			assert.False(t, header.ExpectsResponse)
+
+			decoded, err := levin.DecodeNotification(header.Command, payload)
+			require.NoError(t, err)
+			assert.Equal(t, n, decoded)
+		})
+
+		it("pings", func() {
+			errC := make(chan error)
+			go func() {
+				errC <- client.Ping(ctx)
+			}()
+
+			peer.read()
+			peer.respond(levin.CommandPing, status{Status: "OK"})
+			assert.NoError(t, <-errC)
+		})
+
+		it("fails ping w/ unexpected status", func() {
+			errC := make(chan error)
+			go func() {
+				errC <- client.Ping(ctx)
+			}()
+
+			peer.read()
+			peer.respond(levin.CommandPing, status{Status: "NOPE"})
+
+			err := <-errC
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "unexpected status")
+		})
+
+		it("rejects oversized packets before the handshake", func() {
+			peer.write(levin.NewNotificationHeader(levin.NotifyNewTransactions,
+				levin.LevinPacketMaxInitialSize+1), nil)
+
+			<-client.Done()
+			assert.ErrorIs(t, client.Err(), levin.ErrPacketTooBig)
+		})
+
+		it("accepts large packets right after the handshake", func() {
+			type handshakeResponse struct {
+				NodeData    levin.NodeData     `epee:"node_data"`
+				PayloadData levin.CoreSyncData `epee:"payload_data"`
+			}
+
+			errC := make(chan error, 1)
+			go func() {
+				_, err := client.Handshake(ctx)
+				errC <- err
+			}()
+
+			header, _ := peer.read()
+			require.Equal(t, levin.CommandHandshake, header.Command)
+
+			peer.respond(levin.CommandHandshake, handshakeResponse{
+				NodeData: levin.NodeData{
+					NetworkID: levin.MainnetNetworkId,
+					PeerID:    1,
+				},
+			})
+
+			// w/ the limit in place, the client would stop reading,
+			// leaving us blocked.
+			//
+			deadline := time.Now().Add(2 * time.Second)
+			require.NoError(t, peer.conn.SetDeadline(deadline))
+
+			size := levin.LevinPacketMaxInitialSize + 1
+			peer.write(levin.NewNotificationHeader(levin.NotifyNewFluffyBlock, size),
+				make([]byte, size))
+
+			header, _ = peer.read()
+			require.Equal(t, levin.CommandSupportFlags, header.Command)
+			peer.respond(levin.CommandSupportFlags, struct {
+				SupportFlags uint32 `epee:"support_flags"`
+			}{levin.SupportFlagFluffyBlocks})
+
+			assert.NoError(t, <-errC)
+			assert.NoError(t, client.Err())
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "Handshake", func(t *testing.T, when spec.G, it spec.S) {
+		type handshake struct {
+			NodeData    levin.NodeData     `epee:"node_data"`
+			PayloadData levin.CoreSyncData `epee:"payload_data"`
+		}
+
+		sentHandshake := func(opts ...levin.ClientOption) handshake {
+			local, remote := net.Pipe()
+			defer remote.Close()
+
+			client, err := levin.NewClientFromConn(local, opts...)
+			require.NoError(t, err)
+			defer client.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			go func() {
+				_, _ = client.Handshake(ctx)
+			}()
+
+			peer := &fakePeer{t: t, conn: remote}
+
+			header, payload := peer.read()
+			require.Equal(t, levin.CommandHandshake, header.Command)
+
+			req := handshake{}
+			require.NoError(t, levin.Unmarshal(payload, &req))
+
+			return req
+		}
+
+		it("announces a fresh node by default", func() {
+			req := sentHandshake(levin.WithNetwork(monero.NetworkTestnet))
+
+			genesis, err := levin.GenesisHash(monero.NetworkTestnet)
+			require.NoError(t, err)
+
+			assert.Equal(t, levin.TestnetNetworkId, req.NodeData.NetworkID)
+			assert.NotZero(t, req.NodeData.PeerID)
+			assert.Equal(t, levin.SupportFlagFluffyBlocks, req.NodeData.SupportFlags)
+			assert.Equal(t, uint64(1), req.PayloadData.CurrentHeight)
+			assert.Equal(t, genesis, req.PayloadData.TopID)
+		})
+
+		it("announces what's been configured", func() {
+			nodeData := levin.NodeData{
+				NetworkID:         levin.StagenetNetworkId,
+				PeerID:            123,
+				MyPort:            38080,
+				RPCPort:           38081,
+				RPCCreditsPerHash: 10,
+				SupportFlags:      levin.SupportFlagFluffyBlocks,
+			}
+
+			coreSyncData := levin.CoreSyncData{
+				CurrentHeight:             1000,
+				CumulativeDifficulty:      2000,
+				CumulativeDifficultyTop64: 1,
+				TopID:                     hash(0x01),
+				TopVersion:                16,
+				PruningSeed:               0x182,
+			}
+
+			req := sentHandshake(
+				levin.WithLocalNodeData(nodeData),
+				levin.WithLocalCoreSyncData(coreSyncData),
+			)
+
+			assert.Equal(t, nodeData, req.NodeData)
+			assert.Equal(t, coreSyncData, req.PayloadData)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -0,0 +1,274 @@
+package levin
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultCrawlerConcurrency = 32
+	DefaultCrawlerPeerTimeout = 10 * time.Second
+)
+
+// CrawlResult is the outcome of trying to handshake with a node found while
+// crawling the network.
+//
+type CrawlResult struct {
+	Address   string `json:"address"`
+	Zone      Zone   `json:"zone"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+
+	PeerID        uint64 `json:"peer_id,omitempty"`
+	CurrentHeight uint64 `json:"current_height,omitempty"`
+	TopVersion    uint8  `json:"top_version,omitempty"`
+	RPCPort       uint16 `json:"rpc_port,omitempty"`
+	SupportFlags  uint32 `json:"support_flags,omitempty"`
+	PruningSeed   uint32 `json:"pruning_seed,omitempty"`
+
+	// Peers is the number of peers in the peer list sent by the node.
+	//
+	Peers int `json:"peers,omitempty"`
+
+	Duration time.Duration `json:"duration"`
+}
+
+// Crawler maps the reachable p2p network by handshaking w/ nodes in a
+// breadth-first manner, starting from a set of seeds and then following the
+// peer lists that they send back.
+//
+type Crawler struct {
+	cfg CrawlerConfig
+}
+
+type CrawlerConfig struct {
+	// Concurrency is the maximum number of nodes being handshaked w/ at
+	// the same time.
+	//
+	Concurrency int
+
+	// PeerTimeout is how long to wait for a node to connect and complete
+	// the handshake before considering it unreachable.
+	//
+	PeerTimeout time.Duration
+
+	// MaxNodes is the maximum number of nodes to try to reach (0 for no
+	// limit).
+	//
+	MaxNodes int
+
+	// Zones are the zones whose addresses should be followed (defaults
+	// to just the public one).
+	//
+	Zones []Zone
+
+	// ClientOptions are the options passed down to every client created
+	// for handshaking w/ the nodes (e.g., for proxying connections).
+	//
+	ClientOptions []ClientOption
+}
+
+type CrawlerOption func(*CrawlerConfig)
+
+func WithConcurrency(v int) func(*CrawlerConfig) {
+	return func(c *CrawlerConfig) {
+		c.Concurrency = v
+	}
+}
+
+func WithPeerTimeout(v time.Duration) func(*CrawlerConfig) {
+	return func(c *CrawlerConfig) {
+		c.PeerTimeout = v
+	}
+}
+
+func WithMaxNodes(v int) func(*CrawlerConfig) {
+	return func(c *CrawlerConfig) {
+		c.MaxNodes = v
+	}
+}
+
+func WithZones(v ...Zone) func(*CrawlerConfig) {
+	return func(c *CrawlerConfig) {
+		c.Zones = v
+	}
+}
+
+func WithClientOptions(v ...ClientOption) func(*CrawlerConfig) {
+	return func(c *CrawlerConfig) {
+		c.ClientOptions = v
+	}
+}
+
+func NewCrawler(opts ...CrawlerOption) *Crawler {
+	cfg := CrawlerConfig{
+		Concurrency: DefaultCrawlerConcurrency,
+		PeerTimeout: DefaultCrawlerPeerTimeout,
+		Zones:       []Zone{ZonePublic},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 1
+	}
+
+	return &Crawler{
+		cfg: cfg,
+	}
+}
+
+// crawlOutcome is what a visit to a node yields: its result and the peers
+// it knows about.
+//
+type crawlOutcome struct {
+	result *CrawlResult
+	peers  map[string]*Peer
+}
+
+// Crawl handshakes w/ the nodes at `seeds` and every node that's transitively
+// found through their peer lists, calling `fn` (never concurrently) w/ the
+// result of each attempt.
+//
+// Nodes are deduplicated by address and, once reached, by peer id: a node
+// that announces a peer id that's already been seen is neither reported nor
+// followed.
+//
+func (c *Crawler) Crawl(ctx context.Context, seeds []string, fn func(*CrawlResult)) error {
+	var (
+		queue    = []string{}
+		visited  = map[string]bool{}
+		peerIDs  = map[uint64]bool{}
+		inflight = 0
+		outcomes = make(chan crawlOutcome)
+		wg       = sync.WaitGroup{}
+	)
+
+	defer wg.Wait()
+
+	enqueue := func(addr string) {
+		if visited[addr] {
+			return
+		}
+
+		if c.cfg.MaxNodes > 0 && len(visited) >= c.cfg.MaxNodes {
+			return
+		}
+
+		visited[addr] = true
+		queue = append(queue, addr)
+	}
+
+	for _, seed := range seeds {
+		enqueue(seed)
+	}
+
+	for len(queue) > 0 || inflight > 0 {
+		for len(queue) > 0 && inflight < c.cfg.Concurrency {
+			addr := queue[0]
+			queue = queue[1:]
+			inflight++
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				outcome := c.visit(ctx, addr)
+				select {
+				case outcomes <- outcome:
+				case <-ctx.Done():
+				}
+			}()
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case outcome := <-outcomes:
+			inflight--
+
+			if outcome.result.Reachable {
+				if peerIDs[outcome.result.PeerID] {
+					continue
+				}
+
+				peerIDs[outcome.result.PeerID] = true
+			}
+
+			fn(outcome.result)
+
+			for addr, peer := range outcome.peers {
+				if c.followZone(peer.Address.Zone()) {
+					enqueue(addr)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *Crawler) followZone(zone Zone) bool {
+	for _, z := range c.cfg.Zones {
+		if z == zone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// visit handshakes w/ the node at `addr`.
+//
+func (c *Crawler) visit(ctx context.Context, addr string) crawlOutcome {
+	ctx, cancel := context.WithTimeout(ctx, c.cfg.PeerTimeout)
+	defer cancel()
+
+	result := &CrawlResult{
+		Address: addr,
+		Zone:    ZonePublic,
+	}
+
+	if networkAddress, err := ParseNetworkAddress(addr); err == nil {
+		result.Zone = networkAddress.Zone()
+	}
+
+	start := time.Now()
+	node, err := c.handshake(ctx, addr)
+	result.Duration = time.Since(start)
+
+	if err != nil {
+		result.Error = err.Error()
+		return crawlOutcome{result: result}
+	}
+
+	result.Reachable = true
+	result.PeerID = node.Id
+	result.CurrentHeight = node.CurrentHeight
+	result.TopVersion = node.TopVersion
+	result.RPCPort = node.RPCPort
+	result.SupportFlags = node.SupportFlags
+	result.PruningSeed = node.CoreSyncData.PruningSeed
+	result.Peers = len(node.Peers)
+
+	return crawlOutcome{result: result, peers: node.Peers}
+}
+
+func (c *Crawler) handshake(ctx context.Context, addr string) (*Node, error) {
+	client, err := NewClient(ctx, addr, c.cfg.ClientOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	defer client.Close()
+
+	node, err := client.Handshake(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("handshake: %w", err)
+	}
+
+	return node, nil
+}
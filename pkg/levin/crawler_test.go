@@ -0,0 +1,144 @@
+package levin_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+// nolint:funlen
+func TestCrawler(t *testing.T) {
+	spec.Run(t, "Crawler", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			wg     sync.WaitGroup
+		)
+
+		listen := func() net.Listener {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			return listener
+		}
+
+		peer := func(listener net.Listener) *levin.Peer {
+			addr, err := levin.ParseNetworkAddress(listener.Addr().String())
+			require.NoError(t, err)
+
+			return &levin.Peer{Address: addr}
+		}
+
+		serve := func(listener net.Listener, peerID uint64, height uint64, peers ...*levin.Peer) {
+			server, err := levin.NewServer(
+				levin.WithNodeData(levin.NodeData{
+					NetworkID: levin.MainnetNetworkId,
+					PeerID:    peerID,
+				}),
+				levin.WithCoreSyncData(levin.CoreSyncData{
+					CurrentHeight: height,
+					TopVersion:    16,
+				}),
+				levin.WithPeers(peers),
+			)
+			require.NoError(t, err)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = server.Serve(ctx, listener)
+			}()
+		}
+
+		crawl := func(seed string, opts ...levin.CrawlerOption) map[string]*levin.CrawlResult {
+			results := map[string]*levin.CrawlResult{}
+
+			err := levin.NewCrawler(opts...).Crawl(ctx, []string{seed}, func(r *levin.CrawlResult) {
+				results[r.Address] = r
+			})
+			require.NoError(t, err)
+
+			return results
+		}
+
+		it.Before(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		})
+
+		it.After(func() {
+			cancel()
+			wg.Wait()
+		})
+
+		it("follows peer lists, deduplicating by peer id", func() {
+			a, b, c, d := listen(), listen(), listen(), listen()
+
+			// `d` is never served.
+			//
+			unreachable := peer(d)
+			d.Close()
+
+			serve(a, 1, 100, peer(b), peer(c), unreachable)
+			serve(b, 2, 101, peer(a))
+			serve(c, 2, 101)
+
+			results := crawl(a.Addr().String(),
+				levin.WithConcurrency(1),
+				levin.WithPeerTimeout(2*time.Second),
+			)
+
+			require.Contains(t, results, a.Addr().String())
+			assert.True(t, results[a.Addr().String()].Reachable)
+			assert.Equal(t, uint64(1), results[a.Addr().String()].PeerID)
+			assert.Equal(t, uint64(100), results[a.Addr().String()].CurrentHeight)
+			assert.Equal(t, uint8(16), results[a.Addr().String()].TopVersion)
+			assert.Equal(t, 3, results[a.Addr().String()].Peers)
+
+			require.Contains(t, results, unreachable.Addr())
+			assert.False(t, results[unreachable.Addr()].Reachable)
+			assert.NotEmpty(t, results[unreachable.Addr()].Error)
+
+			// `b` and `c` share the same peer id: only the first one
+			// reached gets reported.
+			//
+			reported := 0
+			for _, addr := range []string{b.Addr().String(), c.Addr().String()} {
+				if _, found := results[addr]; found {
+					reported++
+				}
+			}
+
+			assert.Equal(t, 1, reported)
+		})
+
+		it("stops after max nodes", func() {
+			a, b := listen(), listen()
+
+			serve(a, 1, 100, peer(b))
+			serve(b, 2, 100, peer(a))
+
+			results := crawl(a.Addr().String(), levin.WithMaxNodes(1))
+			assert.Len(t, results, 1)
+		})
+
+		it("skips zones not being followed", func() {
+			a := listen()
+
+			serve(a, 1, 100, &levin.Peer{
+				Address: levin.TorAddress{Host: "foo.onion", Port: 18083},
+			})
+
+			results := crawl(a.Addr().String())
+			assert.Len(t, results, 1)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
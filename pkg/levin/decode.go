@@ -0,0 +1,231 @@
+package levin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DecodedMessage is a levin message laid out for inspection, w/ its payload
+// decoded into a generic portable storage tree.
+//
+type DecodedMessage struct {
+	// Time is when the message has been fully received, if known.
+	//
+	Time *time.Time `json:"time,omitempty"`
+
+	// Source and Destination are the addresses of the sender and the
+	// receiver of the message, if known.
+	//
+	Source      string `json:"source,omitempty"`
+	Destination string `json:"destination,omitempty"`
+
+	Header *DecodedHeader `json:"header,omitempty"`
+
+	// Storage is the portable storage tree carried by the message, w/
+	// objects as maps, arrays as slices, binary strings as DecodedBlobs,
+	// and doubles that aren't finite (which JSON can't carry) as strings.
+	//
+	Storage map[string]interface{} `json:"storage,omitempty"`
+
+	// Error describes why the message (or the stream it'd be part of)
+	// couldn't be decoded.
+	//
+	Error string `json:"error,omitempty"`
+}
+
+// DecodedBlob is a string of the portable storage tree that isn't printable
+// text (e.g., a hash), tagged so that it can't be mistaken for text.
+//
+type DecodedBlob struct {
+	Hex string `json:"hex"`
+}
+
+type DecodedHeader struct {
+	Length          uint64 `json:"length"`
+	ExpectsResponse bool   `json:"expects_response"`
+	Command         uint32 `json:"command"`
+	CommandName     string `json:"command_name,omitempty"`
+	ReturnCode      int32  `json:"return_code"`
+	Flags           uint32 `json:"flags"`
+	Version         uint32 `json:"version"`
+}
+
+// DecodeMessage decodes the message w/ header `header` and payload
+// `payload`. Failing to decode the payload is reported in the message itself.
+//
+func DecodeMessage(header *Header, payload []byte) *DecodedMessage {
+	msg := &DecodedMessage{
+		Header: &DecodedHeader{
+			Length:          header.Length,
+			ExpectsResponse: header.ExpectsResponse,
+			Command:         header.Command,
+			CommandName:     CommandName(header.Command),
+			ReturnCode:      header.ReturnCode,
+			Flags:           header.Flags,
+			Version:         header.Version,
+		},
+	}
+
+	if len(payload) == 0 {
+		return msg
+	}
+
+	storage, err := newPortableStorageFromBytes(payload, &decoder{typed: true})
+	if err != nil {
+		msg.Error = fmt.Sprintf("new portable storage from bytes: %v", err)
+		return msg
+	}
+
+	msg.Storage = storageObject(storage.Entries)
+
+	return msg
+}
+
+// DecodeStream decodes the levin messages carried by a stream of bytes sent
+// from one end of a connection (e.g., a raw dump of one direction of a TCP
+// connection), handing each one to `fn`.
+//
+// Bytes preceding the first levin signature are skipped so that streams
+// captured midway through can still be decoded.
+//
+func DecodeStream(r io.Reader, fn func(*DecodedMessage) error) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read all: %w", err)
+	}
+
+	for _, msg := range decodeStream(data, nil) {
+		if err := fn(msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeStream decodes the messages in `data`, using `timeAt` (if set) to
+// figure out the time at which the byte at a given offset was received.
+//
+func decodeStream(data []byte, timeAt func(offset int) time.Time) []*DecodedMessage {
+	var (
+		messages = []*DecodedMessage{}
+		skipped  = 0
+	)
+
+	signature := (&Header{Signature: LevinSignature}).Bytes()[:8]
+	if idx := bytes.Index(data, signature); idx > 0 {
+		skipped = idx
+	} else if idx < 0 {
+		skipped = len(data)
+	}
+
+	reader := &countingReader{r: bytes.NewReader(data[skipped:])}
+	frames := NewFrameReader(reader)
+
+	for {
+		header, payload, err := frames.ReadMessage()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var msg *DecodedMessage
+		if err != nil {
+			msg = &DecodedMessage{
+				Error: fmt.Sprintf("read message: %v", err),
+			}
+		} else {
+			msg = DecodeMessage(header, payload)
+		}
+
+		if timeAt != nil {
+			t := timeAt(skipped + reader.n - 1)
+			msg.Time = &t
+		}
+
+		messages = append(messages, msg)
+
+		// there's no telling where the next message starts after a
+		// failure to read one.
+		//
+		if err != nil {
+			break
+		}
+	}
+
+	return messages
+}
+
+func storageObject(entries Entries) map[string]interface{} {
+	obj := make(map[string]interface{}, len(entries))
+
+	for _, entry := range entries {
+		obj[entry.Name] = storageValue(entry)
+	}
+
+	return obj
+}
+
+func storageValue(entry Entry) interface{} {
+	switch value := entry.Value.(type) {
+	case Entries:
+		// objects and arrays are both decoded into entries, thus, only
+		// the type marker tells them apart.
+		//
+		if entry.Type == BoostSerializeTypeObject {
+			return storageObject(value)
+		}
+
+		arr := make([]interface{}, len(value))
+		for idx, element := range value {
+			arr[idx] = storageValue(element)
+		}
+
+		return arr
+	case string:
+		if isPrintable(value) {
+			return value
+		}
+
+		return DecodedBlob{Hex: hex.EncodeToString([]byte(value))}
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return strconv.FormatFloat(value, 'g', -1, 64)
+		}
+	}
+
+	return entry.Value
+}
+
+func isPrintable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+
+	for _, r := range s {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += n
+
+	return n, err
+}
@@ -0,0 +1,310 @@
+package levin
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// see https://github.com/monero-project/monero/blob/e45619e61e4831eea70a43fe6985f4d57ea02e9e/src/net/levin_base.cpp
+//
+// A message can be split into fragments that are sent as a sequence of
+// packets of command CommandDummy: the first w/ the B flag set, the last
+// one w/ the E flag, and those in between w/ neither. Once concatenated,
+// the payloads of the fragments carry the full message (header and
+// payload) followed by zeroed padding.
+//
+// A single packet of command CommandDummy w/ both B and E flags set is a
+// dummy one, whose only purpose is making noise.
+
+// ErrUnexpectedFragment indicates that a fragment has been received out of
+// the sequence it should've been in.
+//
+var ErrUnexpectedFragment = errors.New("unexpected fragment")
+
+// FrameReader reads levin messages from a stream, reassembling those that
+// have been fragmented and discarding dummy packets.
+//
+type FrameReader struct {
+	r io.Reader
+
+	// MaxSize is the maximum size of a message payload, whether it
+	// comes in a single packet or spread across fragments.
+	//
+	MaxSize uint64
+
+	// PacketHandler, if set, is called w/ the header of every packet of
+	// command CommandDummy read (noise and fragments alike), as those
+	// never come out of ReadMessage on their own.
+	//
+	PacketHandler func(header *Header)
+}
+
+func NewFrameReader(r io.Reader) *FrameReader {
+	return &FrameReader{
+		r:       r,
+		MaxSize: LevinPacketMaxDefaultSize,
+	}
+}
+
+// ReadMessage reads the next message from the stream, returning its header
+// and payload.
+//
+func (f *FrameReader) ReadMessage() (*Header, []byte, error) {
+	var fragments []byte
+
+	for {
+		header, payload, err := f.readPacket(uint64(len(fragments)))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if header.Command == CommandDummy && f.PacketHandler != nil {
+			f.PacketHandler(header)
+		}
+
+		var (
+			begin = header.Flags&LevinPacketBegin != 0
+			end   = header.Flags&LevinPacketEnd != 0
+		)
+
+		if header.Command != CommandDummy {
+			if fragments != nil || begin || end {
+				return nil, nil, fmt.Errorf("command %d w/ fragmentation in progress: %w",
+					header.Command, ErrUnexpectedFragment)
+			}
+
+			return header, payload, nil
+		}
+
+		switch {
+		case begin && end:
+			if fragments != nil {
+				return nil, nil, fmt.Errorf("dummy w/ fragmentation in progress: %w",
+					ErrUnexpectedFragment)
+			}
+
+			continue
+		case begin:
+			if fragments != nil {
+				return nil, nil, fmt.Errorf("begin w/ fragmentation in progress: %w",
+					ErrUnexpectedFragment)
+			}
+
+			fragments = append([]byte{}, payload...)
+			continue
+		case fragments == nil:
+			return nil, nil, fmt.Errorf("fragment w/out begin: %w",
+				ErrUnexpectedFragment)
+		}
+
+		fragments = append(fragments, payload...)
+		if end {
+			return f.defragment(fragments)
+		}
+	}
+}
+
+// readPacket reads a single packet, making sure that its payload, added to
+// the `buffered` bytes of fragments already read, doesn't go over the max
+// size.
+//
+func (f *FrameReader) readPacket(buffered uint64) (*Header, []byte, error) {
+	headerB := make([]byte, LevinHeaderSizeBytes)
+	if _, err := io.ReadFull(f.r, headerB); err != nil {
+		return nil, nil, fmt.Errorf("read full header: %w", err)
+	}
+
+	header, err := NewHeaderFromBytesBytes(headerB)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new header from bytes: %w", err)
+	}
+
+	// fragments carry the header of the message too, which must be
+	// accounted for.
+	//
+	maxSize := f.MaxSize
+	if header.Command == CommandDummy {
+		maxSize += LevinHeaderSizeBytes
+	}
+
+	if header.Length > maxSize || buffered+header.Length > maxSize {
+		return nil, nil, fmt.Errorf("length %d over max of %d: %w",
+			buffered+header.Length, maxSize, ErrPacketTooBig)
+	}
+
+	payload := make([]byte, header.Length)
+	if _, err := io.ReadFull(f.r, payload); err != nil {
+		return nil, nil, fmt.Errorf("read full payload: %w", err)
+	}
+
+	return header, payload, nil
+}
+
+// defragment extracts the message carried by the concatenated payloads of
+// a sequence of fragments.
+//
+func (f *FrameReader) defragment(fragments []byte) (*Header, []byte, error) {
+	if len(fragments) < LevinHeaderSizeBytes {
+		return nil, nil, fmt.Errorf("fragments w/ %d bytes can't fit a header: %w",
+			len(fragments), ErrTruncated)
+	}
+
+	header, err := NewHeaderFromBytesBytes(fragments[:LevinHeaderSizeBytes])
+	if err != nil {
+		return nil, nil, fmt.Errorf("new header from bytes: %w", err)
+	}
+
+	if header.Command == CommandDummy {
+		return nil, nil, fmt.Errorf("fragmented dummy: %w", ErrUnexpectedFragment)
+	}
+
+	payload := fragments[LevinHeaderSizeBytes:]
+	if header.Length > uint64(len(payload)) {
+		return nil, nil, fmt.Errorf("message of %d bytes in %d bytes of fragments: %w",
+			header.Length, len(payload), ErrTruncated)
+	}
+
+	return header, payload[:header.Length], nil
+}
+
+// FrameWriter writes levin messages to a stream, optionally splitting them
+// into fragments.
+//
+type FrameWriter struct {
+	w io.Writer
+
+	// FragmentSize is the size (including the header) of the packets
+	// that messages not expecting a response are padded or fragmented
+	// into. Zero disables fragmentation.
+	//
+	FragmentSize uint64
+}
+
+func NewFrameWriter(w io.Writer) *FrameWriter {
+	return &FrameWriter{
+		w: w,
+	}
+}
+
+// WriteMessage writes a message w/ `header` and `payload`, padding or
+// fragmenting it according to `FragmentSize` in case it's a notification.
+//
+func (f *FrameWriter) WriteMessage(header *Header, payload []byte) error {
+	b, err := f.frame(header, payload)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.w.Write(b); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDummy writes a dummy packet of `size` bytes (header included).
+//
+func (f *FrameWriter) WriteDummy(size uint64) error {
+	b, err := NewDummyPacket(size)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.w.Write(b); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+func (f *FrameWriter) frame(header *Header, payload []byte) ([]byte, error) {
+	if f.FragmentSize == 0 || header.ExpectsResponse || header.IsResponse() {
+		h := *header
+		h.Length = uint64(len(payload))
+
+		return append(h.Bytes(), payload...), nil
+	}
+
+	return Fragment(header, payload, f.FragmentSize)
+}
+
+// NewDummyPacket creates a dummy packet of `size` bytes (header included)
+// that receivers discard.
+//
+func NewDummyPacket(size uint64) ([]byte, error) {
+	if size < LevinHeaderSizeBytes {
+		return nil, fmt.Errorf("size %d can't fit a header", size)
+	}
+
+	header := newFragmentHeader(size-LevinHeaderSizeBytes, LevinPacketBegin|LevinPacketEnd)
+	return append(header.Bytes(), make([]byte, header.Length)...), nil
+}
+
+// Fragment lays out a message w/ `header` and `payload` as a sequence of
+// packets of `size` bytes each (header included).
+//
+// Messages that fit in a single packet are sent as is, but padded w/ zeros
+// up to `size`.
+//
+func Fragment(header *Header, payload []byte, size uint64) ([]byte, error) {
+	if size < 2*LevinHeaderSizeBytes {
+		return nil, fmt.Errorf("size %d can't fit a fragment", size)
+	}
+
+	chunk := size - LevinHeaderSizeBytes
+
+	if uint64(len(payload)) <= chunk {
+		h := *header
+		h.Length = chunk
+
+		padded := make([]byte, chunk)
+		copy(padded, payload)
+
+		return append(h.Bytes(), padded...), nil
+	}
+
+	h := *header
+	h.Length = uint64(len(payload))
+
+	message := append(h.Bytes(), payload...)
+
+	// pad the message so that it spans a whole number of fragments.
+	//
+	if rem := uint64(len(message)) % chunk; rem != 0 {
+		message = append(message, make([]byte, chunk-rem)...)
+	}
+
+	var (
+		fragments = uint64(len(message)) / chunk
+		b         = make([]byte, 0, fragments*size)
+	)
+
+	for idx := uint64(0); idx < fragments; idx++ {
+		var flags uint32
+
+		switch idx {
+		case 0:
+			flags = LevinPacketBegin
+		case fragments - 1:
+			flags = LevinPacketEnd
+		}
+
+		b = append(b, newFragmentHeader(chunk, flags).Bytes()...)
+		b = append(b, message[idx*chunk:(idx+1)*chunk]...)
+	}
+
+	return b, nil
+}
+
+func newFragmentHeader(length uint64, flags uint32) *Header {
+	return &Header{
+		Signature:       LevinSignature,
+		Length:          length,
+		ExpectsResponse: false,
+		Command:         CommandDummy,
+		ReturnCode:      0,
+		Flags:           flags,
+		Version:         LevinProtocolVersion,
+	}
+}
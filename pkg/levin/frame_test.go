@@ -0,0 +1,271 @@
+package levin_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+// fragment builds a packet of command levin.CommandDummy carrying `payload`
+// w/ `flags` set.
+//
+func fragment(flags uint32, payload []byte) []byte {
+	header := &levin.Header{
+		Signature: levin.LevinSignature,
+		Length:    uint64(len(payload)),
+		Command:   levin.CommandDummy,
+		Flags:     flags,
+		Version:   levin.LevinProtocolVersion,
+	}
+
+	return append(header.Bytes(), payload...)
+}
+
+func message(command uint32, payload []byte) []byte {
+	return append(levin.NewNotificationHeader(command, uint64(len(payload))).Bytes(), payload...)
+}
+
+func TestFrame(t *testing.T) {
+	spec.Run(t, "Frame", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			buf    *bytes.Buffer
+			reader *levin.FrameReader
+		)
+
+		it.Before(func() {
+			buf = &bytes.Buffer{}
+			reader = levin.NewFrameReader(buf)
+		})
+
+		when("reading a single packet", func() {
+			it("returns it as is", func() {
+				buf.Write(message(levin.NotifyNewTransactions, []byte{0xaa, 0xbb}))
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Equal(t, []byte{0xaa, 0xbb}, payload)
+			})
+
+			it("fails if it goes over the max size", func() {
+				reader.MaxSize = 1
+				buf.Write(message(levin.NotifyNewTransactions, []byte{0xaa, 0xbb}))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrPacketTooBig)
+			})
+		})
+
+		when("reading dummy packets", func() {
+			it("discards them", func() {
+				buf.Write(fragment(levin.LevinPacketBegin|levin.LevinPacketEnd, make([]byte, 10)))
+				buf.Write(fragment(levin.LevinPacketBegin|levin.LevinPacketEnd, nil))
+				buf.Write(message(levin.NotifyNewTransactions, []byte{0xaa}))
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Equal(t, []byte{0xaa}, payload)
+			})
+
+			it("hands them to the packet handler", func() {
+				var headers []*levin.Header
+				reader.PacketHandler = func(h *levin.Header) {
+					headers = append(headers, h)
+				}
+
+				buf.Write(fragment(levin.LevinPacketBegin|levin.LevinPacketEnd, make([]byte, 10)))
+				buf.Write(message(levin.NotifyNewTransactions, []byte{0xaa}))
+
+				_, _, err := reader.ReadMessage()
+				require.NoError(t, err)
+
+				require.Len(t, headers, 1)
+				assert.Equal(t, levin.CommandDummy, headers[0].Command)
+				assert.Equal(t, uint64(10), headers[0].Length)
+			})
+
+			it("fails w/ EOF if there's nothing else", func() {
+				buf.Write(fragment(levin.LevinPacketBegin|levin.LevinPacketEnd, make([]byte, 10)))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, io.EOF)
+			})
+		})
+
+		when("reading fragments", func() {
+			var msg []byte
+
+			it.Before(func() {
+				msg = message(levin.NotifyNewTransactions, []byte{0x01, 0x02, 0x03, 0x04, 0x05})
+				msg = append(msg, 0x00, 0x00, 0x00) // padding
+			})
+
+			it("reassembles the message, dropping the padding", func() {
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(fragment(0, msg[20:30]))
+				buf.Write(fragment(0, msg[30:35]))
+				buf.Write(fragment(levin.LevinPacketEnd, msg[35:]))
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Equal(t, uint64(5), header.Length)
+				assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x04, 0x05}, payload)
+			})
+
+			it("hands each one to the packet handler", func() {
+				var flags []uint32
+				reader.PacketHandler = func(h *levin.Header) {
+					flags = append(flags, h.Flags)
+				}
+
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(fragment(0, msg[20:30]))
+				buf.Write(fragment(levin.LevinPacketEnd, msg[30:]))
+
+				_, _, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, []uint32{levin.LevinPacketBegin, 0, levin.LevinPacketEnd}, flags)
+			})
+
+			it("reads the messages that follow", func() {
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(fragment(levin.LevinPacketEnd, msg[20:]))
+				buf.Write(message(levin.NotifyNewFluffyBlock, []byte{0xff}))
+
+				_, _, err := reader.ReadMessage()
+				require.NoError(t, err)
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewFluffyBlock, header.Command)
+				assert.Equal(t, []byte{0xff}, payload)
+			})
+
+			it("enforces the max size across fragments", func() {
+				reader.MaxSize = 4
+
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:30]))
+				buf.Write(fragment(levin.LevinPacketEnd, msg[30:]))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrPacketTooBig)
+			})
+
+			it("fails if the fragments don't carry the whole message", func() {
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(fragment(levin.LevinPacketEnd, msg[20:36]))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrTruncated)
+			})
+
+			it("fails on a fragment w/out a beginning", func() {
+				buf.Write(fragment(0, msg[20:30]))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrUnexpectedFragment)
+			})
+
+			it("fails on a new beginning mid-sequence", func() {
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(fragment(levin.LevinPacketBegin, msg[20:]))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrUnexpectedFragment)
+			})
+
+			it("fails on a regular message mid-sequence", func() {
+				buf.Write(fragment(levin.LevinPacketBegin, msg[:20]))
+				buf.Write(message(levin.NotifyNewFluffyBlock, []byte{0xff}))
+
+				_, _, err := reader.ReadMessage()
+				assert.ErrorIs(t, err, levin.ErrUnexpectedFragment)
+			})
+		})
+
+		when("writing", func() {
+			var writer *levin.FrameWriter
+
+			it.Before(func() {
+				writer = levin.NewFrameWriter(buf)
+			})
+
+			it("doesn't touch messages w/out a fragment size", func() {
+				payload := make([]byte, 100)
+
+				err := writer.WriteMessage(levin.NewNotificationHeader(levin.NotifyNewTransactions, 0), payload)
+				require.NoError(t, err)
+				assert.Equal(t, levin.LevinHeaderSizeBytes+100, buf.Len())
+			})
+
+			it("doesn't fragment requests expecting a response", func() {
+				writer.FragmentSize = 50
+
+				err := writer.WriteMessage(levin.NewRequestHeader(levin.CommandPing, 0), make([]byte, 100))
+				require.NoError(t, err)
+				assert.Equal(t, levin.LevinHeaderSizeBytes+100, buf.Len())
+			})
+
+			it("pads messages that fit in a single packet", func() {
+				writer.FragmentSize = 128
+
+				err := writer.WriteMessage(levin.NewNotificationHeader(levin.NotifyNewTransactions, 0), []byte{0xaa})
+				require.NoError(t, err)
+				assert.Equal(t, 128, buf.Len())
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Len(t, payload, 128-levin.LevinHeaderSizeBytes)
+				assert.Equal(t, byte(0xaa), payload[0])
+			})
+
+			it("fragments bigger messages in packets of the same size", func() {
+				writer.FragmentSize = 128
+
+				payload := make([]byte, 200)
+				for idx := range payload {
+					payload[idx] = byte(idx)
+				}
+
+				err := writer.WriteMessage(levin.NewNotificationHeader(levin.NotifyNewTransactions, 0), payload)
+				require.NoError(t, err)
+				assert.Zero(t, buf.Len()%128)
+
+				header, actual, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Equal(t, payload, actual)
+				assert.Zero(t, buf.Len())
+			})
+
+			it("writes dummies that get discarded", func() {
+				require.NoError(t, writer.WriteDummy(64))
+				assert.Equal(t, 64, buf.Len())
+
+				require.NoError(t, writer.WriteMessage(levin.NewNotificationHeader(levin.NotifyNewTransactions, 0), []byte{0xaa}))
+
+				header, payload, err := reader.ReadMessage()
+				require.NoError(t, err)
+				assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+				assert.Equal(t, []byte{0xaa}, payload)
+			})
+
+			it("fails w/ fragment sizes that can't fit a fragment", func() {
+				writer.FragmentSize = levin.LevinHeaderSizeBytes
+
+				err := writer.WriteMessage(levin.NewNotificationHeader(levin.NotifyNewTransactions, 0), []byte{0xaa})
+				assert.Error(t, err)
+			})
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -0,0 +1,83 @@
+package levin_test
+
+import (
+	"testing"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+// portableStorageSeed is the same `node_data`/`payload_data` vector used by
+// the portable storage tests.
+//
+var portableStorageSeed = []byte{
+	0x01, 0x11, 0x01, 0x01, // sig a
+	0x01, 0x01, 0x02, 0x01, // sig b
+	0x01, // format ver
+	0x08, // var_in(len(entries))
+
+	0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x64, 0x61, 0x74, 0x61, // "node_data"
+	0x0c, 0x04, // object w/ 1 entry
+	0x03, 0x66, 0x6f, 0x6f, // "foo"
+	0x0a, 0x0c, 0x62, 0x61, 0x72, // string "bar"
+
+	0x0c, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x64, 0x61, 0x74, 0x61, // "payload_data"
+	0x0c, 0x04, // object w/ 1 entry
+	0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, // "number"
+	0x06, 0x01, 0x00, 0x00, 0x00, // uint32(1)
+}
+
+func FuzzNewPortableStorageFromBytes(f *testing.F) {
+	f.Add(portableStorageSeed)
+	f.Add(portableStorageSeed[:9])
+	f.Add(portableStorageSeed[:20])
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_, _ = levin.NewPortableStorageFromBytes(b)
+	})
+}
+
+func FuzzNewHeaderFromBytes(f *testing.F) {
+	f.Add(levin.NewRequestHeader(levin.CommandHandshake, 1).Bytes())
+	f.Add(levin.NewRequestHeader(levin.NotifyNewTransactions, 0).Bytes())
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		_, _ = levin.NewHeaderFromBytesBytes(b)
+	})
+}
+
+func FuzzDecodeNotification(f *testing.F) {
+	notifications := []levin.Notification{
+		&levin.NewBlock{
+			Block: levin.BlockCompleteEntry{
+				Block: []byte{0x01},
+				Txs:   []levin.TxBlobEntry{{Blob: []byte{0xaa}}},
+			},
+		},
+		&levin.NewFluffyBlock{
+			Block: levin.BlockCompleteEntry{
+				Pruned: true,
+				Block:  []byte{0x01},
+				Txs:    []levin.TxBlobEntry{{Blob: []byte{0xaa}}},
+			},
+		},
+		&levin.NewTransactions{Txs: [][]byte{{0x01}}},
+		&levin.RequestGetObjects{Blocks: []levin.Hash{{0x01}}},
+		&levin.ResponseChainEntry{BlockWeights: []uint64{1}},
+		&levin.RequestFluffyMissingTx{MissingTxIndices: []uint64{0}},
+	}
+
+	for _, n := range notifications {
+		payload, err := levin.Marshal(n)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		f.Add(n.Command(), payload)
+	}
+
+	f.Add(levin.NotifyGetTxPoolComplement, portableStorageSeed)
+
+	f.Fuzz(func(t *testing.T, command uint32, payload []byte) {
+		_, _ = levin.DecodeNotification(command, payload)
+	})
+}
@@ -6,7 +6,11 @@ package levin
 
 import (
 	"encoding/binary"
+	"encoding/hex"
+	"errors"
 	"fmt"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
 )
 
 const (
@@ -16,12 +20,19 @@ const (
 
 	LevinPacketRequest        uint32 = 0x00000001 // Q flag
 	LevinPacketReponse        uint32 = 0x00000002 // S flag
+	LevinPacketBegin          uint32 = 0x00000004 // B flag
+	LevinPacketEnd            uint32 = 0x00000008 // E flag
 	LevinPacketMaxDefaultSize uint64 = 100000000  // 100MB _after_ handshake
 	LevinPacketMaxInitialSize uint64 = 256 * 1024 // 256KiB _before_ handshake
 
 	LevinHeaderSizeBytes = 33
 )
 
+// ErrPacketTooBig indicates that a peer announced a packet bigger than what's
+// allowed at that stage of the connection.
+//
+var ErrPacketTooBig = errors.New("packet too big")
+
 const (
 	// Return Codes.
 	LevinOk                               int32 = 0
@@ -39,6 +50,13 @@ func IsValidReturnCode(c int32) bool {
 	return c >= LevinErrorFormat
 }
 
+const (
+	// CommandDummy is the command of dummy (noise) packets and of each of
+	// the fragments of a fragmented message.
+	//
+	CommandDummy uint32 = 0
+)
+
 const (
 	// p2p admin commands.
 	CommandHandshake    uint32 = 1001
@@ -50,6 +68,19 @@ const (
 	CommandSupportFlags uint32 = 1007
 )
 
+const (
+	// cryptonote protocol notifications.
+	NotifyNewBlock               uint32 = 2001
+	NotifyNewTransactions        uint32 = 2002
+	NotifyRequestGetObjects      uint32 = 2003
+	NotifyResponseGetObjects     uint32 = 2004
+	NotifyRequestChain           uint32 = 2006
+	NotifyResponseChainEntry     uint32 = 2007
+	NotifyNewFluffyBlock         uint32 = 2008
+	NotifyRequestFluffyMissingTx uint32 = 2009
+	NotifyGetTxPoolComplement    uint32 = 2010
+)
+
 var (
 	MainnetNetworkId = []byte{
 		0x12, 0x30, 0xf1, 0x71,
@@ -58,11 +89,117 @@ var (
 		0x16, 0xa1, 0xa1, 0x10,
 	}
 
-	MainnetGenesisTx = "418015bb9ae982a1975da7d79277c2705727a56894ba0fb246adaabb1f4632e3"
+	TestnetNetworkId = []byte{
+		0x12, 0x30, 0xf1, 0x71,
+		0x61, 0x04, 0x41, 0x61,
+		0x17, 0x31, 0x00, 0x82,
+		0x16, 0xa1, 0xa1, 0x11,
+	}
+
+	StagenetNetworkId = []byte{
+		0x12, 0x30, 0xf1, 0x71,
+		0x61, 0x04, 0x41, 0x61,
+		0x17, 0x31, 0x00, 0x82,
+		0x16, 0xa1, 0xa1, 0x12,
+	}
+
+	MainnetGenesisTx  = "418015bb9ae982a1975da7d79277c2705727a56894ba0fb246adaabb1f4632e3"
+	TestnetGenesisTx  = "48ca7cd3c8de5b6a4d53d2861fbdaedca141553559f9be9520068053cda8430b"
+	StagenetGenesisTx = "76ee3cc98646292206cd3e86f74d88b4dcc1d937088645e9b0cbca84b7ce74eb"
 )
 
+// NetworkId retrieves the id that nodes of network `network` identify
+// themselves with during handshakes.
+//
+func NetworkId(network monero.Network) ([]byte, error) {
+	switch network {
+	case monero.NetworkMainnet, monero.NetworkFakechain:
+		return MainnetNetworkId, nil
+	case monero.NetworkTestnet:
+		return TestnetNetworkId, nil
+	case monero.NetworkStagenet:
+		return StagenetNetworkId, nil
+	}
+
+	return nil, fmt.Errorf("unknown network '%s'", network)
+}
+
+// GenesisHash retrieves the hash of the genesis block of network `network`.
+//
+func GenesisHash(network monero.Network) (Hash, error) {
+	var genesis string
+
+	switch network {
+	case monero.NetworkMainnet, monero.NetworkFakechain:
+		genesis = MainnetGenesisTx
+	case monero.NetworkTestnet:
+		genesis = TestnetGenesisTx
+	case monero.NetworkStagenet:
+		genesis = StagenetGenesisTx
+	default:
+		return Hash{}, fmt.Errorf("unknown network '%s'", network)
+	}
+
+	hash := Hash{}
+	if _, err := hex.Decode(hash[:], []byte(genesis)); err != nil {
+		return Hash{}, fmt.Errorf("decode '%s': %w", genesis, err)
+	}
+
+	return hash, nil
+}
+
 func IsValidCommand(c uint32) bool {
-	return (c >= CommandHandshake && c <= CommandSupportFlags)
+	if c == CommandDummy {
+		return true
+	}
+
+	if c >= CommandHandshake && c <= CommandSupportFlags {
+		return true
+	}
+
+	switch c {
+	case NotifyNewBlock,
+		NotifyNewTransactions,
+		NotifyRequestGetObjects,
+		NotifyResponseGetObjects,
+		NotifyRequestChain,
+		NotifyResponseChainEntry,
+		NotifyNewFluffyBlock,
+		NotifyRequestFluffyMissingTx,
+		NotifyGetTxPoolComplement:
+		return true
+	}
+
+	return false
+}
+
+// commandNames maps commands to the names they're known by in monerod.
+//
+var commandNames = map[uint32]string{
+	CommandDummy:                 "DUMMY",
+	CommandHandshake:             "COMMAND_HANDSHAKE",
+	CommandTimedSync:             "COMMAND_TIMED_SYNC",
+	CommandPing:                  "COMMAND_PING",
+	CommandStat:                  "COMMAND_REQUEST_STAT_INFO",
+	CommandNetworkState:          "COMMAND_REQUEST_NETWORK_STATE",
+	CommandPeerID:                "COMMAND_REQUEST_PEER_ID",
+	CommandSupportFlags:          "COMMAND_REQUEST_SUPPORT_FLAGS",
+	NotifyNewBlock:               "NOTIFY_NEW_BLOCK",
+	NotifyNewTransactions:        "NOTIFY_NEW_TRANSACTIONS",
+	NotifyRequestGetObjects:      "NOTIFY_REQUEST_GET_OBJECTS",
+	NotifyResponseGetObjects:     "NOTIFY_RESPONSE_GET_OBJECTS",
+	NotifyRequestChain:           "NOTIFY_REQUEST_CHAIN",
+	NotifyResponseChainEntry:     "NOTIFY_RESPONSE_CHAIN_ENTRY",
+	NotifyNewFluffyBlock:         "NOTIFY_NEW_FLUFFY_BLOCK",
+	NotifyRequestFluffyMissingTx: "NOTIFY_REQUEST_FLUFFY_MISSING_TX",
+	NotifyGetTxPoolComplement:    "NOTIFY_GET_TXPOOL_COMPLEMENT",
+}
+
+// CommandName retrieves the name of command `c`, or an empty string if not
+// known.
+//
+func CommandName(c uint32) string {
+	return commandNames[c]
 }
 
 //
@@ -155,6 +292,37 @@ func NewRequestHeader(command uint32, length uint64) *Header {
 	}
 }
 
+// NewResponseHeader instantiates the header of a response to a request of
+// command `command`.
+//
+func NewResponseHeader(command uint32, length uint64, returnCode int32) *Header {
+	return &Header{
+		Signature:       LevinSignature,
+		Length:          length,
+		ExpectsResponse: false,
+		Command:         command,
+		ReturnCode:      returnCode,
+		Flags:           LevinPacketReponse,
+		Version:         LevinProtocolVersion,
+	}
+}
+
+// NewNotificationHeader instantiates the header of a request that doesn't
+// expect a response.
+//
+func NewNotificationHeader(command uint32, length uint64) *Header {
+	header := NewRequestHeader(command, length)
+	header.ExpectsResponse = false
+
+	return header
+}
+
+// IsResponse indicates whether the packet is a response to a request.
+//
+func (h *Header) IsResponse() bool {
+	return h.Flags&LevinPacketReponse != 0
+}
+
 func NewHeaderFromBytesBytes(bytes []byte) (*Header, error) {
 	if len(bytes) != LevinHeaderSizeBytes {
 		return nil, fmt.Errorf("invalid header size: expected %d, has %d",
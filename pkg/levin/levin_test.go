@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
 )
 
 func TestLevin(t *testing.T) {
@@ -148,4 +149,38 @@ func TestLevin(t *testing.T) {
 			})
 		})
 	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "Network", func(t *testing.T, when spec.G, it spec.S) {
+		it("has distinct ids for each network", func() {
+			mainnet, err := levin.NetworkId(monero.NetworkMainnet)
+			assert.NoError(t, err)
+			assert.Equal(t, levin.MainnetNetworkId, mainnet)
+
+			testnet, err := levin.NetworkId(monero.NetworkTestnet)
+			assert.NoError(t, err)
+			assert.Equal(t, levin.TestnetNetworkId, testnet)
+
+			stagenet, err := levin.NetworkId(monero.NetworkStagenet)
+			assert.NoError(t, err)
+			assert.Equal(t, levin.StagenetNetworkId, stagenet)
+
+			assert.NotEqual(t, mainnet, testnet)
+			assert.NotEqual(t, mainnet, stagenet)
+			assert.NotEqual(t, testnet, stagenet)
+		})
+
+		it("decodes the genesis hash", func() {
+			hash, err := levin.GenesisHash(monero.NetworkTestnet)
+			assert.NoError(t, err)
+			assert.Equal(t, levin.TestnetGenesisTx, hash.String())
+		})
+
+		it("fails w/ unknown network", func() {
+			_, err := levin.NetworkId(monero.Network("foo"))
+			assert.Error(t, err)
+
+			_, err = levin.GenesisHash(monero.Network("foo"))
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
 }
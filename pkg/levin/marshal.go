@@ -0,0 +1,689 @@
+package levin
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Marshaler is implemented by types that know how to encode themselves into
+// a portable storage value, bypassing the struct tag based encoding.
+//
+type Marshaler interface {
+	MarshalEpee() (Serializable, error)
+}
+
+// Unmarshaler is implemented by types that know how to decode themselves from
+// a portable storage value (as found in `Entry.Value`), bypassing the struct
+// tag based decoding.
+//
+type Unmarshaler interface {
+	UnmarshalEpee(value interface{}) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// Marshal encodes a struct into its portable storage binary representation.
+//
+// Fields are named after their `epee` struct tag (falling back to the name of
+// the field), which supports the following options after the name:
+//
+//	omitempty	don't encode the field if it holds its zero value
+//			(epee's KV_SERIALIZE_OPT).
+//
+//	blob		encode a fixed-size value (or a slice of them) as a
+//			single string with the raw little-endian bytes
+//			concatenated (epee's KV_SERIALIZE_*_POD_AS_BLOB).
+//
+// A field tagged with `epee:"-"` is skipped, and the fields of embedded
+// structs without a tag are encoded as if they belonged to the outer one.
+// Just like epee, empty slices are never encoded.
+//
+func Marshal(v interface{}) ([]byte, error) {
+	ps, err := MarshalPortableStorage(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return ps.Bytes(), nil
+}
+
+// MarshalPortableStorage encodes a struct into a portable storage tree - see
+// `Marshal`.
+//
+func MarshalPortableStorage(v interface{}) (*PortableStorage, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil pointer")
+		}
+
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %s", rv.Type())
+	}
+
+	entries, err := marshalStruct(rv)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PortableStorage{Entries: entries}, nil
+}
+
+// Unmarshal decodes the portable storage binary representation in `b` into
+// the struct pointed to by `v` - see `Marshal` for the supported struct tags.
+//
+// Entries without a corresponding field are ignored, and integers are
+// converted between sizes as long as the value fits the destination.
+//
+func Unmarshal(b []byte, v interface{}) error {
+	ps, err := NewPortableStorageFromBytes(b)
+	if err != nil {
+		return fmt.Errorf("new portable storage from bytes: %w", err)
+	}
+
+	return UnmarshalPortableStorage(ps, v)
+}
+
+// UnmarshalPortableStorage decodes a portable storage tree into the struct
+// pointed to by `v` - see `Unmarshal`.
+//
+func UnmarshalPortableStorage(ps *PortableStorage, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected non-nil pointer, got %T", v)
+	}
+
+	return unmarshalValue(ps.Entries, rv.Elem(), false)
+}
+
+type fieldTag struct {
+	name      string
+	omitEmpty bool
+	blob      bool
+}
+
+func parseFieldTag(field reflect.StructField) (fieldTag, bool) {
+	tag := fieldTag{name: field.Name}
+
+	value, ok := field.Tag.Lookup("epee")
+	if !ok {
+		return tag, true
+	}
+
+	if value == "-" {
+		return tag, false
+	}
+
+	parts := strings.Split(value, ",")
+	if parts[0] != "" {
+		tag.name = parts[0]
+	}
+
+	for _, opt := range parts[1:] {
+		switch opt {
+		case "omitempty":
+			tag.omitEmpty = true
+		case "blob":
+			tag.blob = true
+		}
+	}
+
+	return tag, true
+}
+
+// inlined tells whether the fields of `field` should be encoded as if they
+// were part of the struct that holds it, which is the case for embedded
+// structs without an `epee` tag.
+//
+func inlined(field reflect.StructField) bool {
+	_, tagged := field.Tag.Lookup("epee")
+
+	return field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct
+}
+
+func marshalStruct(rv reflect.Value) (Entries, error) {
+	entries := Entries{}
+	rt := rv.Type()
+
+	for idx := 0; idx < rt.NumField(); idx++ {
+		field := rt.Field(idx)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+
+		if inlined(field) {
+			inner, err := marshalStruct(rv.Field(idx))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", field.Name, err)
+			}
+
+			entries = append(entries, inner...)
+			continue
+		}
+
+		if len(tag.name) > 255 {
+			return nil, fmt.Errorf("field name '%s' too long", tag.name)
+		}
+
+		fv := rv.Field(idx)
+		if tag.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		serializable, err := marshalValue(fv, tag.blob)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", tag.name, err)
+		}
+
+		if serializable == nil {
+			continue
+		}
+
+		entries = append(entries, Entry{
+			Name:         tag.name,
+			Serializable: serializable,
+		})
+	}
+
+	return entries, nil
+}
+
+// marshalValue encodes a single value, returning a nil serializable if there's
+// nothing to be encoded (nil pointers and empty slices).
+//
+// nolint:cyclop
+func marshalValue(rv reflect.Value, blob bool) (Serializable, error) {
+	if rv.Type().Implements(marshalerType) {
+		if rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return nil, nil
+		}
+
+		return rv.Interface().(Marshaler).MarshalEpee()
+	}
+
+	if rv.CanAddr() && rv.Addr().Type().Implements(marshalerType) {
+		return rv.Addr().Interface().(Marshaler).MarshalEpee()
+	}
+
+	if blob {
+		return marshalBlob(rv)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return nil, nil
+		}
+
+		return marshalValue(rv.Elem(), blob)
+	case reflect.Bool:
+		return BoostBool(rv.Bool()), nil
+	case reflect.Int8:
+		return BoostInt8(rv.Int()), nil
+	case reflect.Int16:
+		return BoostInt16(rv.Int()), nil
+	case reflect.Int32:
+		return BoostInt32(rv.Int()), nil
+	case reflect.Int64, reflect.Int:
+		return BoostInt64(rv.Int()), nil
+	case reflect.Uint8:
+		return BoostByte(rv.Uint()), nil
+	case reflect.Uint16:
+		return BoostUint16(rv.Uint()), nil
+	case reflect.Uint32:
+		return BoostUint32(rv.Uint()), nil
+	case reflect.Uint64, reflect.Uint:
+		return BoostUint64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return BoostDouble(rv.Float()), nil
+	case reflect.String:
+		return marshalString(rv.String())
+	case reflect.Struct:
+		entries, err := marshalStruct(rv)
+		if err != nil {
+			return nil, err
+		}
+
+		return Section{Entries: entries}, nil
+	case reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("unsupported type %s", rv.Type())
+		}
+
+		return marshalBlob(rv)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return marshalString(string(rv.Bytes()))
+		}
+
+		return marshalSlice(rv)
+	}
+
+	return nil, fmt.Errorf("unsupported type %s", rv.Type())
+}
+
+func marshalString(s string) (Serializable, error) {
+	if _, err := VarIn(len(s)); err != nil {
+		return nil, fmt.Errorf("varin: %w", err)
+	}
+
+	return BoostString(s), nil
+}
+
+func marshalSlice(rv reflect.Value) (Serializable, error) {
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+
+	if _, err := VarIn(rv.Len()); err != nil {
+		return nil, fmt.Errorf("varin: %w", err)
+	}
+
+	array := BoostArray{
+		Values: make([]Serializable, rv.Len()),
+	}
+
+	for idx := 0; idx < rv.Len(); idx++ {
+		value, err := marshalValue(rv.Index(idx), false)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", idx, err)
+		}
+
+		if value == nil {
+			return nil, fmt.Errorf("element %d: nil", idx)
+		}
+
+		// arrays of arrays don't have a single element type as each
+		// inner array carries its own.
+		//
+		ttype := serializableType(value)
+		if ttype&BoostSerializeFlagArray != 0 {
+			ttype = BoostSerializeTypeArray
+		}
+
+		if idx == 0 {
+			array.Type = ttype
+		} else if ttype != array.Type {
+			return nil, fmt.Errorf("element %d: type %x doesn't "+
+				"match array type %x", idx, ttype, array.Type)
+		}
+
+		array.Values[idx] = value
+	}
+
+	return array, nil
+}
+
+// marshalBlob encodes a fixed-size value, or a slice of them, as a string made
+// of the concatenation of their little-endian representation.
+//
+func marshalBlob(rv reflect.Value) (Serializable, error) {
+	if rv.Kind() != reflect.Slice {
+		b, err := appendPod(nil, rv)
+		if err != nil {
+			return nil, err
+		}
+
+		return marshalString(string(b))
+	}
+
+	if rv.Len() == 0 {
+		return nil, nil
+	}
+
+	b := make([]byte, 0, rv.Len()*int(rv.Type().Elem().Size()))
+	for idx := 0; idx < rv.Len(); idx++ {
+		var err error
+
+		b, err = appendPod(b, rv.Index(idx))
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", idx, err)
+		}
+	}
+
+	return marshalString(string(b))
+}
+
+func appendPod(b []byte, rv reflect.Value) ([]byte, error) {
+	buf := make([]byte, 8) // biggest type
+
+	switch rv.Kind() {
+	case reflect.Uint8:
+		return append(b, byte(rv.Uint())), nil
+	case reflect.Uint16:
+		binary.LittleEndian.PutUint16(buf, uint16(rv.Uint()))
+		return append(b, buf[:2]...), nil
+	case reflect.Uint32:
+		binary.LittleEndian.PutUint32(buf, uint32(rv.Uint()))
+		return append(b, buf[:4]...), nil
+	case reflect.Uint64:
+		binary.LittleEndian.PutUint64(buf, rv.Uint())
+		return append(b, buf...), nil
+	case reflect.Int8:
+		return append(b, byte(rv.Int())), nil
+	case reflect.Int16:
+		binary.LittleEndian.PutUint16(buf, uint16(rv.Int()))
+		return append(b, buf[:2]...), nil
+	case reflect.Int32:
+		binary.LittleEndian.PutUint32(buf, uint32(rv.Int()))
+		return append(b, buf[:4]...), nil
+	case reflect.Int64:
+		binary.LittleEndian.PutUint64(buf, uint64(rv.Int()))
+		return append(b, buf...), nil
+	case reflect.Float64:
+		binary.LittleEndian.PutUint64(buf, math.Float64bits(rv.Float()))
+		return append(b, buf...), nil
+	case reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return nil, fmt.Errorf("unsupported pod type %s", rv.Type())
+		}
+
+		for idx := 0; idx < rv.Len(); idx++ {
+			b = append(b, byte(rv.Index(idx).Uint()))
+		}
+
+		return b, nil
+	}
+
+	return nil, fmt.Errorf("unsupported pod type %s", rv.Type())
+}
+
+// serializableType determines the type marker that a serializable is encoded
+// with.
+//
+func serializableType(s Serializable) byte {
+	switch v := s.(type) {
+	case BoostArray:
+		return v.Type | BoostSerializeFlagArray
+	case Section, *Section:
+		return BoostSerializeTypeObject
+	}
+
+	return s.Bytes()[0]
+}
+
+// unmarshalValue decodes a portable storage value (as in `Entry.Value`) into
+// `rv`.
+//
+// nolint:cyclop
+func unmarshalValue(value interface{}, rv reflect.Value, blob bool) error {
+	if rv.CanAddr() && rv.Addr().Type().Implements(unmarshalerType) {
+		return rv.Addr().Interface().(Unmarshaler).UnmarshalEpee(value)
+	}
+
+	if blob {
+		return unmarshalBlob(value, rv)
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+
+		return unmarshalValue(value, rv.Elem(), blob)
+	case reflect.Bool:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected bool, got %T", value)
+		}
+
+		rv.SetBool(v)
+		return nil
+	case reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Int:
+		return unmarshalInt(value, rv)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uint:
+		return unmarshalUint(value, rv)
+	case reflect.Float32, reflect.Float64:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected double, got %T", value)
+		}
+
+		rv.SetFloat(v)
+		return nil
+	case reflect.String:
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected string, got %T", value)
+		}
+
+		rv.SetString(v)
+		return nil
+	case reflect.Struct:
+		entries, ok := value.(Entries)
+		if !ok {
+			return fmt.Errorf("expected object, got %T", value)
+		}
+
+		return unmarshalStruct(entries, rv)
+	case reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported type %s", rv.Type())
+		}
+
+		return unmarshalBlob(value, rv)
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			v, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("expected string, got %T", value)
+			}
+
+			rv.SetBytes([]byte(v))
+			return nil
+		}
+
+		return unmarshalSlice(value, rv)
+	}
+
+	return fmt.Errorf("unsupported type %s", rv.Type())
+}
+
+func unmarshalStruct(entries Entries, rv reflect.Value) error {
+	rt := rv.Type()
+
+	for idx := 0; idx < rt.NumField(); idx++ {
+		field := rt.Field(idx)
+		if field.PkgPath != "" { // unexported
+			continue
+		}
+
+		tag, ok := parseFieldTag(field)
+		if !ok {
+			continue
+		}
+
+		if inlined(field) {
+			err := unmarshalStruct(entries, rv.Field(idx))
+			if err != nil {
+				return fmt.Errorf("%s: %w", field.Name, err)
+			}
+
+			continue
+		}
+
+		for _, entry := range entries {
+			if entry.Name != tag.name {
+				continue
+			}
+
+			err := unmarshalValue(entry.Value, rv.Field(idx), tag.blob)
+			if err != nil {
+				return fmt.Errorf("%s: %w", tag.name, err)
+			}
+
+			break
+		}
+	}
+
+	return nil
+}
+
+func unmarshalSlice(value interface{}, rv reflect.Value) error {
+	elements, ok := value.(Entries)
+	if !ok {
+		return fmt.Errorf("expected array, got %T", value)
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), len(elements), len(elements))
+	for idx, element := range elements {
+		err := unmarshalValue(element.Value, slice.Index(idx), false)
+		if err != nil {
+			return fmt.Errorf("element %d: %w", idx, err)
+		}
+	}
+
+	rv.Set(slice)
+	return nil
+}
+
+func unmarshalUint(value interface{}, rv reflect.Value) error {
+	v, negative, err := integerValue(value)
+	if err != nil {
+		return err
+	}
+
+	if negative {
+		return fmt.Errorf("negative value %d for %s", int64(v), rv.Type())
+	}
+
+	if rv.OverflowUint(v) {
+		return fmt.Errorf("value %d overflows %s", v, rv.Type())
+	}
+
+	rv.SetUint(v)
+	return nil
+}
+
+func unmarshalInt(value interface{}, rv reflect.Value) error {
+	v, negative, err := integerValue(value)
+	if err != nil {
+		return err
+	}
+
+	if !negative && v > math.MaxInt64 {
+		return fmt.Errorf("value %d overflows %s", v, rv.Type())
+	}
+
+	if rv.OverflowInt(int64(v)) {
+		return fmt.Errorf("value %d overflows %s", int64(v), rv.Type())
+	}
+
+	rv.SetInt(int64(v))
+	return nil
+}
+
+// integerValue extracts the integer out of any of the integer types that
+// portable storage values can be decoded to, indicating whether it's negative
+// (in which case the returned value should be interpreted as an int64).
+//
+func integerValue(value interface{}) (uint64, bool, error) {
+	switch n := value.(type) {
+	case uint8:
+		return uint64(n), false, nil
+	case uint16:
+		return uint64(n), false, nil
+	case uint32:
+		return uint64(n), false, nil
+	case uint64:
+		return n, false, nil
+	case int8:
+		return uint64(n), n < 0, nil
+	case int16:
+		return uint64(n), n < 0, nil
+	case int32:
+		return uint64(n), n < 0, nil
+	case int64:
+		return uint64(n), n < 0, nil
+	}
+
+	return 0, false, fmt.Errorf("expected integer, got %T", value)
+}
+
+// unmarshalBlob decodes a string made of the concatenation of fixed-size
+// values into either a single one of them or a slice.
+//
+func unmarshalBlob(value interface{}, rv reflect.Value) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected string, got %T", value)
+	}
+
+	b := []byte(s)
+
+	if rv.Kind() != reflect.Slice {
+		if uintptr(len(b)) != rv.Type().Size() {
+			return fmt.Errorf("expected %d bytes, got %d",
+				rv.Type().Size(), len(b))
+		}
+
+		return readPod(b, rv)
+	}
+
+	size := int(rv.Type().Elem().Size())
+	if size == 0 || len(b)%size != 0 {
+		return fmt.Errorf("blob size %d not a multiple of %d",
+			len(b), size)
+	}
+
+	slice := reflect.MakeSlice(rv.Type(), len(b)/size, len(b)/size)
+	for idx := 0; idx < slice.Len(); idx++ {
+		err := readPod(b[idx*size:(idx+1)*size], slice.Index(idx))
+		if err != nil {
+			return fmt.Errorf("element %d: %w", idx, err)
+		}
+	}
+
+	rv.Set(slice)
+	return nil
+}
+
+func readPod(b []byte, rv reflect.Value) error {
+	switch rv.Kind() {
+	case reflect.Uint8:
+		rv.SetUint(uint64(b[0]))
+	case reflect.Uint16:
+		rv.SetUint(uint64(binary.LittleEndian.Uint16(b)))
+	case reflect.Uint32:
+		rv.SetUint(uint64(binary.LittleEndian.Uint32(b)))
+	case reflect.Uint64:
+		rv.SetUint(binary.LittleEndian.Uint64(b))
+	case reflect.Int8:
+		rv.SetInt(int64(int8(b[0])))
+	case reflect.Int16:
+		rv.SetInt(int64(int16(binary.LittleEndian.Uint16(b))))
+	case reflect.Int32:
+		rv.SetInt(int64(int32(binary.LittleEndian.Uint32(b))))
+	case reflect.Int64:
+		rv.SetInt(int64(binary.LittleEndian.Uint64(b)))
+	case reflect.Float64:
+		rv.SetFloat(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+	case reflect.Array:
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("unsupported pod type %s", rv.Type())
+		}
+
+		reflect.Copy(rv, reflect.ValueOf(b))
+	default:
+		return fmt.Errorf("unsupported pod type %s", rv.Type())
+	}
+
+	return nil
+}
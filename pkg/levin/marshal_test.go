@@ -0,0 +1,287 @@
+package levin_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+// nolint:funlen
+func TestMarshal(t *testing.T) {
+	spec.Run(t, "Marshal", func(t *testing.T, when spec.G, it spec.S) {
+		it("matches hand-built portable storage", func() {
+			type request struct {
+				NodeData struct {
+					Foo string `epee:"foo"`
+				} `epee:"node_data"`
+				PayloadData struct {
+					Number uint32 `epee:"number"`
+				} `epee:"payload_data"`
+			}
+
+			req := request{}
+			req.NodeData.Foo = "bar"
+			req.PayloadData.Number = 1
+
+			b, err := levin.Marshal(req)
+			require.NoError(t, err)
+
+			expected := (&levin.PortableStorage{
+				Entries: []levin.Entry{
+					{
+						Name: "node_data",
+						Serializable: &levin.Section{
+							Entries: []levin.Entry{
+								{
+									Name:         "foo",
+									Serializable: levin.BoostString("bar"),
+								},
+							},
+						},
+					},
+					{
+						Name: "payload_data",
+						Serializable: &levin.Section{
+							Entries: []levin.Entry{
+								{
+									Name:         "number",
+									Serializable: levin.BoostUint32(1),
+								},
+							},
+						},
+					},
+				},
+			}).Bytes()
+
+			assert.Equal(t, expected, b)
+		})
+
+		it("skips omitempty zero values, empty slices and '-'", func() {
+			type v struct {
+				A uint64   `epee:"a,omitempty"`
+				B []string `epee:"b"`
+				C string   `epee:"-"`
+				D *uint64  `epee:"d"`
+			}
+
+			ps, err := levin.MarshalPortableStorage(&v{C: "c"})
+			require.NoError(t, err)
+			assert.Empty(t, ps.Entries)
+		})
+
+		it("falls back to the field name", func() {
+			type v struct {
+				Name string
+			}
+
+			ps, err := levin.MarshalPortableStorage(v{Name: "foo"})
+			require.NoError(t, err)
+			require.Len(t, ps.Entries, 1)
+			assert.Equal(t, "Name", ps.Entries[0].Name)
+		})
+
+		it("inlines untagged embedded structs", func() {
+			type Footer struct {
+				Status string `epee:"status"`
+			}
+
+			type v struct {
+				Height uint64 `epee:"height"`
+				Footer
+			}
+
+			ps, err := levin.MarshalPortableStorage(v{Height: 1, Footer: Footer{Status: "OK"}})
+			require.NoError(t, err)
+			require.Len(t, ps.Entries, 2)
+			assert.Equal(t, "height", ps.Entries[0].Name)
+			assert.Equal(t, "status", ps.Entries[1].Name)
+
+			out := v{}
+			require.NoError(t, levin.Unmarshal(ps.Bytes(), &out))
+			assert.Equal(t, "OK", out.Status)
+			assert.Equal(t, uint64(1), out.Height)
+		})
+
+		it("fails w/ non-struct", func() {
+			_, err := levin.Marshal(123)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected struct")
+		})
+
+		it("fails w/ unsupported type", func() {
+			type v struct {
+				Fn func() `epee:"fn"`
+			}
+
+			_, err := levin.Marshal(v{Fn: func() {}})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "fn: unsupported type")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "Unmarshal", func(t *testing.T, when spec.G, it spec.S) {
+		type inner struct {
+			Value uint64 `epee:"value"`
+		}
+
+		type v struct {
+			Int8     int8      `epee:"int8"`
+			Int16    int16     `epee:"int16"`
+			Int32    int32     `epee:"int32"`
+			Int64    int64     `epee:"int64"`
+			Uint16   uint16    `epee:"uint16"`
+			Double   float64   `epee:"double"`
+			Matrix   [][]int32 `epee:"matrix"`
+			Int64s   []int64   `epee:"int64s,blob"`
+			Doubles  []float64 `epee:"doubles,blob"`
+			Bool     bool      `epee:"bool"`
+			Uint8    uint8     `epee:"uint8"`
+			Uint32   uint32    `epee:"uint32"`
+			Uint64   uint64    `epee:"uint64"`
+			String   string    `epee:"string"`
+			Bytes    []byte    `epee:"bytes"`
+			Array    [4]byte   `epee:"array"`
+			Strings  []string  `epee:"strings"`
+			Inner    inner     `epee:"inner"`
+			Inners   []inner   `epee:"inners"`
+			Pointer  *inner    `epee:"pointer"`
+			Hashes   [][2]byte `epee:"hashes,blob"`
+			Uint64s  []uint64  `epee:"uint64s,blob"`
+			PodValue uint32    `epee:"pod_value,blob"`
+		}
+
+		it("roundtrips", func() {
+			in := v{
+				Int8:     -1,
+				Int16:    -2,
+				Int32:    -3,
+				Int64:    -4,
+				Uint16:   5,
+				Double:   6.5,
+				Matrix:   [][]int32{{1, 2}, {3}},
+				Int64s:   []int64{-7, 7},
+				Doubles:  []float64{0.5, -0.5},
+				Bool:     true,
+				Uint8:    1,
+				Uint32:   2,
+				Uint64:   3,
+				String:   "foo",
+				Bytes:    []byte{0x00, 0xff},
+				Array:    [4]byte{1, 2, 3, 4},
+				Strings:  []string{"a", "b"},
+				Inner:    inner{Value: 4},
+				Inners:   []inner{{Value: 5}, {Value: 6}},
+				Pointer:  &inner{Value: 7},
+				Hashes:   [][2]byte{{1, 2}, {3, 4}},
+				Uint64s:  []uint64{8, 9},
+				PodValue: 10,
+			}
+
+			b, err := levin.Marshal(in)
+			require.NoError(t, err)
+
+			out := v{}
+			require.NoError(t, levin.Unmarshal(b, &out))
+			assert.Equal(t, in, out)
+		})
+
+		it("converts between integer sizes", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "uint64", Value: uint8(1)},
+					{Name: "uint8", Value: uint64(2)},
+				},
+			}
+
+			out := v{}
+			require.NoError(t, levin.UnmarshalPortableStorage(ps, &out))
+			assert.Equal(t, uint64(1), out.Uint64)
+			assert.Equal(t, uint8(2), out.Uint8)
+		})
+
+		it("converts between signed and unsigned", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "int8", Value: uint64(127)},
+					{Name: "uint16", Value: int32(1000)},
+				},
+			}
+
+			out := v{}
+			require.NoError(t, levin.UnmarshalPortableStorage(ps, &out))
+			assert.Equal(t, int8(127), out.Int8)
+			assert.Equal(t, uint16(1000), out.Uint16)
+		})
+
+		it("fails w/ negative value into unsigned", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "uint64", Value: int64(-1)},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "negative value -1")
+		})
+
+		it("fails on signed overflow", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "int8", Value: int16(-129)},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "int8: value -129 overflows")
+		})
+
+		it("fails on overflow", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "uint8", Value: uint64(256)},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "uint8: value 256 overflows")
+		})
+
+		it("fails on type mismatch", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "inner", Value: "not an object"},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "inner: expected object")
+		})
+
+		it("fails w/ wrong fixed-size blob length", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "array", Value: "abc"},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected 4 bytes, got 3")
+		})
+
+		it("fails w/ non-pointer", func() {
+			err := levin.UnmarshalPortableStorage(&levin.PortableStorage{}, v{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "expected non-nil pointer")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
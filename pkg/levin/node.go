@@ -1,8 +1,10 @@
 package levin
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net"
+	"reflect"
 )
 
 type Node struct {
@@ -13,115 +15,174 @@ type Node struct {
 
 	CurrentHeight uint64
 	TopVersion    uint8
+
+	// NodeData is what the node announced about itself as `node_data`.
+	//
+	NodeData NodeData
+
+	// CoreSyncData is the state of the blockchain of the node as
+	// announced in `payload_data`.
+	//
+	CoreSyncData CoreSyncData
+
+	// SupportFlags are the features supported by the node as replied to
+	// COMMAND_REQUEST_SUPPORT_FLAGS.
+	//
+	SupportFlags uint32
 }
 
 func (l *Node) GetPeers() map[string]*Peer {
 	return l.Peers
 }
 
+// Peer is an entry of a node's peer list.
+//
 type Peer struct {
-	Ip   string
-	Port uint16
+	Address NetworkAddress
+
+	// ID is the peer id that the peer announced itself with.
+	//
+	ID uint64
+
+	// LastSeen is the unix timestamp of the last time that the node
+	// has seen the peer (not sent by recent nodes).
+	//
+	LastSeen int64
+
+	PruningSeed       uint32
+	RPCPort           uint16
+	RPCCreditsPerHash uint32
 }
 
 func (p Peer) Addr() string {
-	return fmt.Sprintf("%s:%d", p.Ip, p.Port)
+	return p.Address.String()
 }
 
 func (p Peer) String() string {
 	return p.Addr()
 }
 
-func ParsePeerList(entry Entry) map[string]*Peer {
-	peers := map[string]*Peer{}
-
-	peerList := entry.Entries()
-
-	for _, peer := range peerList {
-		peerListAdr := peer.Entries()
+// NodeData describes a node to its peers, exchanged as `node_data` during
+// handshakes.
+//
+type NodeData struct {
+	NetworkID []byte `epee:"network_id"`
+	PeerID    uint64 `epee:"peer_id"`
+
+	// MyPort is the port that the node accepts p2p connections on, 0 if
+	// not reachable.
+	//
+	MyPort uint32 `epee:"my_port"`
+
+	// RPCPort is the port of the node's restricted RPC server, 0 if not
+	// advertised.
+	//
+	RPCPort uint16 `epee:"rpc_port"`
+
+	// RPCCreditsPerHash is the amount of credits given per hash by the
+	// node's RPC server when payments are enabled.
+	//
+	RPCCreditsPerHash uint32 `epee:"rpc_credits_per_hash"`
+
+	SupportFlags uint32 `epee:"support_flags"`
+}
 
-		for _, adr := range peerListAdr {
-			if adr.Name != "adr" {
-				continue
-			}
+// peerListEntry is an entry of the peer list carried in handshake and timed
+// sync responses.
+//
+type peerListEntry struct {
+	Adr               netAddress `epee:"adr"`
+	ID                uint64     `epee:"id"`
+	LastSeen          int64      `epee:"last_seen,omitempty"`
+	PruningSeed       uint32     `epee:"pruning_seed,omitempty"`
+	RPCPort           uint16     `epee:"rpc_port,omitempty"`
+	RPCCreditsPerHash uint32     `epee:"rpc_credits_per_hash,omitempty"`
+}
 
-			addr := adr.Entries()
+func ParsePeerList(entry Entry) (map[string]*Peer, error) {
+	peerList := []peerListEntry{}
 
-			for _, addrField := range addr {
-				if addrField.Name != "addr" {
-					continue
-				}
+	err := unmarshalValue(entry.Value, reflect.ValueOf(&peerList).Elem(), false)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
 
-				fields := addrField.Entries()
+	return peersFromPeerList(peerList), nil
+}
 
-				var ip string
-				var port uint16
+// peersFromPeerList converts entries of a peer list into peers keyed by
+// their addresses, skipping those whose address is of an unknown type.
+//
+func peersFromPeerList(peerList []peerListEntry) map[string]*Peer {
+	peers := map[string]*Peer{}
 
-				for _, field := range fields {
-					if field.Name == "m_ip" {
-						ip = ipzify(field.Uint32())
-					}
+	for _, entry := range peerList {
+		addr := entry.Adr.networkAddress()
+		if addr == nil {
+			continue
+		}
 
-					if field.Name == "m_port" {
-						port = field.Uint16()
-					}
+		peer := &Peer{
+			Address:           addr,
+			ID:                entry.ID,
+			LastSeen:          entry.LastSeen,
+			PruningSeed:       entry.PruningSeed,
+			RPCPort:           entry.RPCPort,
+			RPCCreditsPerHash: entry.RPCCreditsPerHash,
+		}
 
-					if field.Name == "addr" {
-						ip = net.IP([]byte(field.String())).String()
-					}
-				}
+		peers[peer.Addr()] = peer
+	}
 
-				if ip != "" && port != 0 {
-					peer := &Peer{
-						Ip:   ip,
-						Port: port,
-					}
+	return peers
+}
 
-					peers[peer.Addr()] = peer
-				}
-			}
+func peerListFromPeers(peers []*Peer) []peerListEntry {
+	peerList := make([]peerListEntry, len(peers))
+
+	for idx, peer := range peers {
+		peerList[idx] = peerListEntry{
+			Adr:               newNetAddress(peer.Address),
+			ID:                peer.ID,
+			LastSeen:          peer.LastSeen,
+			PruningSeed:       peer.PruningSeed,
+			RPCPort:           peer.RPCPort,
+			RPCCreditsPerHash: peer.RPCCreditsPerHash,
 		}
 	}
 
-	return peers
+	return peerList
 }
 
-// TODO less panic'ing.
-func NewNodeFromEntries(entries Entries) Node {
-	lpl := Node{}
-
-	for _, entry := range entries {
-		if entry.Name == "node_data" {
-			for _, field := range entry.Entries() {
-				switch field.Name {
-				case "rpc_port":
-					lpl.RPCPort = field.Uint16()
-				case "peer_id":
-					lpl.Id = field.Uint64()
-				}
-			}
-		}
+// handshakeResponse is the body of a COMMAND_HANDSHAKE response.
+//
+type handshakeResponse struct {
+	NodeData         NodeData        `epee:"node_data"`
+	PayloadData      CoreSyncData    `epee:"payload_data"`
+	LocalPeerlistNew []peerListEntry `epee:"local_peerlist_new"`
+}
 
-		if entry.Name == "payload_data" {
-			for _, field := range entry.Entries() {
-				switch field.Name {
-				case "current_height":
-					lpl.CurrentHeight = field.Uint64()
-				case "top_version":
-					lpl.TopVersion = field.Uint8()
-				}
-			}
-		}
+func NewNodeFromEntries(entries Entries) (Node, error) {
+	lpl := Node{}
+	resp := handshakeResponse{}
 
-		if entry.Name == "local_peerlist_new" {
-			lpl.Peers = ParsePeerList(entry)
-		}
+	err := UnmarshalPortableStorage(&PortableStorage{Entries: entries}, &resp)
+	if err != nil {
+		return lpl, fmt.Errorf("unmarshal: %w", err)
 	}
 
-	return lpl
+	lpl.RPCPort = resp.NodeData.RPCPort
+	lpl.Id = resp.NodeData.PeerID
+	lpl.CurrentHeight = resp.PayloadData.CurrentHeight
+	lpl.TopVersion = resp.PayloadData.TopVersion
+	lpl.Peers = peersFromPeerList(resp.LocalPeerlistNew)
+	lpl.NodeData = resp.NodeData
+	lpl.CoreSyncData = resp.PayloadData
+
+	return lpl, nil
 }
 
-func ipzify(ip uint32) string {
+func ipzify(ip uint32) net.IP {
 	result := make(net.IP, 4)
 
 	result[0] = byte(ip)
@@ -129,5 +190,14 @@ func ipzify(ip uint32) string {
 	result[2] = byte(ip >> 16)
 	result[3] = byte(ip >> 24)
 
-	return result.String()
+	return result
+}
+
+func unipzify(ip net.IP) uint32 {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return 0
+	}
+
+	return binary.LittleEndian.Uint32(ip4)
 }
@@ -0,0 +1,675 @@
+package levin
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+const (
+	DefaultObserverPeers        = 16
+	DefaultObserverPeerTimeout  = 10 * time.Second
+	DefaultObserverRetryBackoff = time.Minute
+	DefaultObserverObjectTTL    = 10 * time.Minute
+)
+
+// observerMaxDelays is the maximum number of delays (of each kind of
+// object) kept for the objects that are no longer tracked.
+//
+const observerMaxDelays = 10000
+
+// ObjectKind is the kind of object announced to an Observer.
+//
+type ObjectKind string
+
+const (
+	ObjectTransaction ObjectKind = "tx"
+	ObjectBlock       ObjectKind = "block"
+)
+
+// Announcement is a transaction or block announced by a peer to an Observer.
+//
+type Announcement struct {
+	Kind ObjectKind `json:"kind"`
+	Hash string     `json:"hash"`
+	Peer string     `json:"peer"`
+	Time time.Time  `json:"time"`
+
+	// First indicates whether this is the first time the object has
+	// been seen.
+	//
+	First bool `json:"first"`
+
+	// Delay is how long after the first announcement of the object this
+	// one came.
+	//
+	Delay time.Duration `json:"delay"`
+
+	// Fluff indicates, for transactions, whether they've been relayed
+	// in the fluff phase of dandelion++ (as opposed to the stem one).
+	//
+	Fluff bool `json:"fluff,omitempty"`
+
+	// Height is, for blocks, the height of the chain of the peer that
+	// announced it.
+	//
+	Height uint64 `json:"height,omitempty"`
+}
+
+// ObserverStats summarizes the announcements seen by an Observer.
+//
+type ObserverStats struct {
+	Connected    int          `json:"connected"`
+	Transactions LatencyStats `json:"transactions"`
+	Blocks       LatencyStats `json:"blocks"`
+	Peers        []PeerStats  `json:"peers"`
+}
+
+// LatencyStats describes the distribution of the delays between the first
+// announcement of objects and the announcements of the same objects by
+// other peers.
+//
+type LatencyStats struct {
+	Objects       int           `json:"objects"`
+	Announcements int           `json:"announcements"`
+	P50           time.Duration `json:"p50"`
+	P90           time.Duration `json:"p90"`
+	P99           time.Duration `json:"p99"`
+	Max           time.Duration `json:"max"`
+}
+
+func (s *LatencyStats) setDelays(delays []time.Duration) {
+	if len(delays) == 0 {
+		return
+	}
+
+	sort.Slice(delays, func(i, j int) bool {
+		return delays[i] < delays[j]
+	})
+
+	percentile := func(p int) time.Duration {
+		return delays[(len(delays)-1)*p/100]
+	}
+
+	s.P50 = percentile(50)
+	s.P90 = percentile(90)
+	s.P99 = percentile(99)
+	s.Max = delays[len(delays)-1]
+}
+
+// expiredObjects summarizes the objects of a kind that are no longer
+// tracked.
+//
+type expiredObjects struct {
+	objects       int
+	announcements int
+	max           time.Duration
+
+	// seen is the number of delays that have been sampled.
+	//
+	seen int
+
+	// delays is a uniform sample (see reservoir sampling) of up to
+	// observerMaxDelays of the delays.
+	//
+	delays []time.Duration
+}
+
+func (e *expiredObjects) add(object *observedObject) {
+	e.objects++
+	e.announcements += len(object.delays) + 1
+
+	for _, delay := range object.delays {
+		if delay > e.max {
+			e.max = delay
+		}
+
+		e.seen++
+		if len(e.delays) < observerMaxDelays {
+			e.delays = append(e.delays, delay)
+			continue
+		}
+
+		// nolint:gosec
+		if idx := rand.Intn(e.seen); idx < len(e.delays) {
+			e.delays[idx] = delay
+		}
+	}
+}
+
+// PeerStats describes how quickly a peer announces objects.
+//
+type PeerStats struct {
+	Address       string        `json:"address"`
+	Announcements int           `json:"announcements"`
+	First         int           `json:"first"`
+	MeanDelay     time.Duration `json:"mean_delay"`
+}
+
+// Observer keeps connections to a number of peers recording when (and by
+// whom) transactions and blocks are first announced, so that their
+// propagation through the network can be studied.
+//
+type Observer struct {
+	cfg ObserverConfig
+
+	mu      sync.Mutex
+	objects map[string]*observedObject
+	peers   map[string]*PeerStats
+
+	// order holds the keys of the objects being tracked in the order
+	// they've been first seen, so that they can be expired.
+	//
+	order []string
+
+	// expired summarizes, for each kind, the objects no longer tracked.
+	//
+	expired map[ObjectKind]*expiredObjects
+
+	// peerDelays holds, for each peer, the sum of the delays of its
+	// announcements.
+	//
+	peerDelays map[string]time.Duration
+
+	// connected maps the addresses of the peers currently connected to
+	// their ids.
+	//
+	connected map[string]uint64
+}
+
+type observedObject struct {
+	kind       ObjectKind
+	firstSeen  time.Time
+	announcers map[string]bool
+
+	// delays holds the delays of all announcements but the first.
+	//
+	delays []time.Duration
+}
+
+type ObserverConfig struct {
+	// Peers is the number of peers to keep connections to.
+	//
+	Peers int
+
+	// PeerTimeout is how long to wait for a peer to connect and complete
+	// the handshake.
+	//
+	PeerTimeout time.Duration
+
+	// RetryBackoff is how long to wait before connecting again to a peer
+	// whose connection failed or ended.
+	//
+	RetryBackoff time.Duration
+
+	// ObjectTTL is for how long objects are tracked after first seen:
+	// afterwards, they're only accounted for in the stats, and announcing
+	// them again makes them count as new ones.
+	//
+	ObjectTTL time.Duration
+
+	// Zones are the zones whose addresses should be connected to
+	// (defaults to just the public one).
+	//
+	Zones []Zone
+
+	// ClientOptions are the options passed down to every client created
+	// for connecting to the peers (e.g., for proxying connections).
+	//
+	ClientOptions []ClientOption
+}
+
+type ObserverOption func(*ObserverConfig)
+
+func WithObservedPeers(v int) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.Peers = v
+	}
+}
+
+func WithObserverPeerTimeout(v time.Duration) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.PeerTimeout = v
+	}
+}
+
+func WithObserverRetryBackoff(v time.Duration) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.RetryBackoff = v
+	}
+}
+
+func WithObserverObjectTTL(v time.Duration) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.ObjectTTL = v
+	}
+}
+
+func WithObserverZones(v ...Zone) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.Zones = v
+	}
+}
+
+func WithObserverClientOptions(v ...ClientOption) func(*ObserverConfig) {
+	return func(c *ObserverConfig) {
+		c.ClientOptions = v
+	}
+}
+
+func NewObserver(opts ...ObserverOption) *Observer {
+	cfg := ObserverConfig{
+		Peers:        DefaultObserverPeers,
+		PeerTimeout:  DefaultObserverPeerTimeout,
+		RetryBackoff: DefaultObserverRetryBackoff,
+		ObjectTTL:    DefaultObserverObjectTTL,
+		Zones:        []Zone{ZonePublic},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.Peers < 1 {
+		cfg.Peers = 1
+	}
+
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultObserverRetryBackoff
+	}
+
+	if cfg.ObjectTTL <= 0 {
+		cfg.ObjectTTL = DefaultObserverObjectTTL
+	}
+
+	return &Observer{
+		cfg:     cfg,
+		objects: map[string]*observedObject{},
+		peers:   map[string]*PeerStats{},
+		expired: map[ObjectKind]*expiredObjects{
+			ObjectTransaction: {},
+			ObjectBlock:       {},
+		},
+		peerDelays: map[string]time.Duration{},
+		connected:  map[string]uint64{},
+	}
+}
+
+// observerEvent is what a connection to a peer yields: the peers it knows
+// about once connected, and then its end.
+//
+type observerEvent struct {
+	addr  string
+	peers map[string]*Peer
+	done  bool
+}
+
+// Observe connects to the peers at `seeds` and to those found through their
+// peer lists (keeping connections to up to `Peers` of them at a time),
+// calling `fn` (never concurrently) w/ every announcement they make until
+// `ctx` is done.
+//
+// Connections are kept alive by answering the peers' timed syncs, and peers
+// whose connections failed or ended are connected to again after
+// `RetryBackoff`, unless none could ever be connected to.
+//
+// nolint:funlen,cyclop
+func (o *Observer) Observe(ctx context.Context, seeds []string, fn func(*Announcement)) error {
+	var (
+		queue   = []string{}
+		active  = 0
+		reached = false
+		events  = make(chan observerEvent)
+		wg      = sync.WaitGroup{}
+
+		// known holds the addresses either queued or being connected
+		// to, and retired, those whose connections ended, w/ the time
+		// from which they can be connected to again.
+		//
+		known   = map[string]bool{}
+		retired = map[string]time.Time{}
+	)
+
+	defer wg.Wait()
+
+	enqueue := func(addr string) {
+		if known[addr] {
+			return
+		}
+
+		if until, found := retired[addr]; found {
+			if time.Now().Before(until) {
+				return
+			}
+
+			delete(retired, addr)
+		}
+
+		known[addr] = true
+		queue = append(queue, addr)
+	}
+
+	for _, seed := range seeds {
+		enqueue(seed)
+	}
+
+	ticker := time.NewTicker(o.cfg.RetryBackoff)
+	defer ticker.Stop()
+
+	for {
+		for len(queue) > 0 && active < o.cfg.Peers {
+			addr := queue[0]
+			queue = queue[1:]
+			active++
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				o.connect(ctx, addr, events, fn)
+			}()
+		}
+
+		if active == 0 && !reached {
+			return fmt.Errorf("no peers left to connect to")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for addr := range retired {
+				enqueue(addr)
+			}
+		case event := <-events:
+			if event.done {
+				active--
+
+				delete(known, event.addr)
+				retired[event.addr] = time.Now().Add(o.cfg.RetryBackoff)
+
+				continue
+			}
+
+			reached = true
+
+			for addr, peer := range event.peers {
+				if o.followZone(peer.Address.Zone()) {
+					enqueue(addr)
+				}
+			}
+		}
+	}
+}
+
+// Stats summarizes the announcements seen so far.
+//
+func (o *Observer) Stats() *ObserverStats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.expire(time.Now())
+
+	stats := &ObserverStats{
+		Connected: len(o.connected),
+		Peers:     []PeerStats{},
+	}
+
+	delays := map[ObjectKind][]time.Duration{}
+	for kind, expired := range o.expired {
+		latency := &stats.Transactions
+		if kind == ObjectBlock {
+			latency = &stats.Blocks
+		}
+
+		latency.Objects += expired.objects
+		latency.Announcements += expired.announcements
+
+		delays[kind] = append(delays[kind], expired.delays...)
+	}
+
+	for _, object := range o.objects {
+		latency := &stats.Transactions
+		if object.kind == ObjectBlock {
+			latency = &stats.Blocks
+		}
+
+		latency.Objects++
+		latency.Announcements += len(object.delays) + 1
+
+		delays[object.kind] = append(delays[object.kind], object.delays...)
+	}
+
+	stats.Transactions.setDelays(delays[ObjectTransaction])
+	stats.Blocks.setDelays(delays[ObjectBlock])
+
+	// the maximum of the delays of expired objects might not have made it
+	// to the sample.
+	//
+	if longest := o.expired[ObjectTransaction].max; longest > stats.Transactions.Max {
+		stats.Transactions.Max = longest
+	}
+
+	if longest := o.expired[ObjectBlock].max; longest > stats.Blocks.Max {
+		stats.Blocks.Max = longest
+	}
+
+	for _, peer := range o.peers {
+		stats.Peers = append(stats.Peers, *peer)
+	}
+
+	sort.Slice(stats.Peers, func(i, j int) bool {
+		return stats.Peers[i].Address < stats.Peers[j].Address
+	})
+
+	return stats
+}
+
+func (o *Observer) followZone(zone Zone) bool {
+	for _, z := range o.cfg.Zones {
+		if z == zone {
+			return true
+		}
+	}
+
+	return false
+}
+
+// connect connects to the peer at `addr`, recording its announcements until
+// either side closes the connection.
+//
+func (o *Observer) connect(ctx context.Context, addr string, events chan<- observerEvent, fn func(*Announcement)) {
+	defer func() {
+		select {
+		case events <- observerEvent{addr: addr, done: true}:
+		case <-ctx.Done():
+		}
+	}()
+
+	client, node, err := o.handshake(ctx, addr, fn)
+	if err != nil {
+		return
+	}
+
+	defer client.Close()
+
+	if !o.register(addr, node.Id) {
+		return
+	}
+
+	defer o.unregister(addr)
+
+	select {
+	case events <- observerEvent{addr: addr, peers: node.Peers}:
+	case <-ctx.Done():
+		return
+	}
+
+	select {
+	case <-client.Done():
+	case <-ctx.Done():
+	}
+}
+
+func (o *Observer) handshake(ctx context.Context, addr string, fn func(*Announcement)) (*Client, *Node, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.cfg.PeerTimeout)
+	defer cancel()
+
+	client, err := NewClient(ctx, addr, o.cfg.ClientOptions...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("new client: %w", err)
+	}
+
+	client.Handle(NotifyNewTransactions, func(n Notification) {
+		txs := n.(*NewTransactions)
+
+		for _, blob := range txs.Txs {
+			hash, err := monero.TransactionHash(blob)
+			if err != nil {
+				continue
+			}
+
+			o.announce(addr, &Announcement{
+				Kind:  ObjectTransaction,
+				Hash:  hex.EncodeToString(hash),
+				Fluff: txs.DandelionppFluff,
+			}, fn)
+		}
+	})
+
+	onBlock := func(block *BlockCompleteEntry, height uint64) {
+		hash, err := monero.BlockHash(block.Block)
+		if err != nil {
+			return
+		}
+
+		o.announce(addr, &Announcement{
+			Kind:   ObjectBlock,
+			Hash:   hex.EncodeToString(hash),
+			Height: height,
+		}, fn)
+	}
+
+	client.Handle(NotifyNewFluffyBlock, func(n Notification) {
+		block := n.(*NewFluffyBlock)
+		onBlock(&block.Block, block.CurrentBlockchainHeight)
+	})
+
+	client.Handle(NotifyNewBlock, func(n Notification) {
+		block := n.(*NewBlock)
+		onBlock(&block.Block, block.CurrentBlockchainHeight)
+	})
+
+	node, err := client.Handshake(ctx)
+	if err != nil {
+		client.Close()
+		return nil, nil, fmt.Errorf("handshake: %w", err)
+	}
+
+	return client, node, nil
+}
+
+// register marks the peer at `addr` as connected, unless we're already
+// connected to a peer w/ the same id.
+//
+func (o *Observer) register(addr string, peerID uint64) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, id := range o.connected {
+		if id == peerID {
+			return false
+		}
+	}
+
+	o.connected[addr] = peerID
+
+	return true
+}
+
+func (o *Observer) unregister(addr string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.connected, addr)
+}
+
+// announce records the announcement `a` made by the peer at `addr`, handing
+// it to `fn` unless the peer had already announced the same object.
+//
+func (o *Observer) announce(addr string, a *Announcement, fn func(*Announcement)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	a.Peer = addr
+	a.Time = time.Now()
+
+	o.expire(a.Time)
+
+	key := string(a.Kind) + ":" + a.Hash
+
+	object, found := o.objects[key]
+	if !found {
+		object = &observedObject{
+			kind:       a.Kind,
+			firstSeen:  a.Time,
+			announcers: map[string]bool{},
+		}
+
+		o.objects[key] = object
+		o.order = append(o.order, key)
+		a.First = true
+	}
+
+	if object.announcers[addr] {
+		return
+	}
+
+	object.announcers[addr] = true
+	a.Delay = a.Time.Sub(object.firstSeen)
+
+	if !a.First {
+		object.delays = append(object.delays, a.Delay)
+	}
+
+	peer, found := o.peers[addr]
+	if !found {
+		peer = &PeerStats{Address: addr}
+		o.peers[addr] = peer
+	}
+
+	peer.Announcements++
+	if a.First {
+		peer.First++
+	}
+
+	o.peerDelays[addr] += a.Delay
+	peer.MeanDelay = o.peerDelays[addr] / time.Duration(peer.Announcements)
+
+	fn(a)
+}
+
+// expire stops tracking the objects first seen longer than `ObjectTTL`
+// before `now`, accounting for them in the stats of expired objects.
+//
+// Callers must hold `mu`.
+//
+func (o *Observer) expire(now time.Time) {
+	for len(o.order) > 0 {
+		key := o.order[0]
+
+		object := o.objects[key]
+		if now.Sub(object.firstSeen) < o.cfg.ObjectTTL {
+			return
+		}
+
+		o.order = o.order[1:]
+		delete(o.objects, key)
+
+		o.expired[object.kind].add(object)
+	}
+}
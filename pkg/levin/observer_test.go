@@ -0,0 +1,275 @@
+package levin_test
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+const (
+	// miner transaction of the mainnet genesis block.
+	//
+	genesisTx     = "013c01ff0001ffffffffffff03029b2e4c0281c0b02e7c53291a94d1d0cbff8883f8024f5142ee494ffbbd08807121017767aafcde9be00dcfd098715ebcf7f410daebc582fda69d24a28e9d0bc890d1"
+	genesisTxHash = "c88ce9783b4f11190d7b9c17a69c1c52200f9faaee8e98dd07e6811175177139"
+)
+
+func genesisBlockBlob(t *testing.T) []byte {
+	tx, err := hex.DecodeString(genesisTx)
+	require.NoError(t, err)
+
+	blob := []byte{0x01, 0x00, 0x00}
+	blob = append(blob, make([]byte, 32)...)
+	blob = append(blob, 0x10, 0x27, 0x00, 0x00)
+	blob = append(blob, tx...)
+
+	return append(blob, 0x00)
+}
+
+// nolint:funlen
+func TestObserver(t *testing.T) {
+	spec.Run(t, "Observer", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			wg     sync.WaitGroup
+		)
+
+		listen := func() net.Listener {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			return listener
+		}
+
+		// serve serves a node w/ `peerID` at `listener`, sending back the
+		// clients that connect to it.
+		//
+		serve := func(listener net.Listener, peerID uint64, peers ...*levin.Peer) <-chan *levin.Client {
+			clients := make(chan *levin.Client, 10)
+
+			server, err := levin.NewServer(
+				levin.WithNodeData(levin.NodeData{
+					NetworkID: levin.MainnetNetworkId,
+					PeerID:    peerID,
+				}),
+				levin.WithPeers(peers),
+				levin.WithClientHandler(func(c *levin.Client) {
+					clients <- c
+				}),
+			)
+			require.NoError(t, err)
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_ = server.Serve(ctx, listener)
+			}()
+
+			return clients
+		}
+
+		it.Before(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+		})
+
+		it.After(func() {
+			cancel()
+			wg.Wait()
+		})
+
+		it("records first-seen times and who announced what", func() {
+			a, b := listen(), listen()
+
+			bAddr, err := levin.ParseNetworkAddress(b.Addr().String())
+			require.NoError(t, err)
+
+			aClients := serve(a, 1, &levin.Peer{Address: bAddr})
+			bClients := serve(b, 2)
+
+			var (
+				observer      = levin.NewObserver(levin.WithObservedPeers(2))
+				announcements = make(chan *levin.Announcement, 10)
+				observed      = make(chan error, 1)
+			)
+
+			go func() {
+				observed <- observer.Observe(ctx, []string{a.Addr().String()}, func(a *levin.Announcement) {
+					announcements <- a
+				})
+			}()
+
+			aClient, bClient := <-aClients, <-bClients
+
+			require.Eventually(t, func() bool {
+				return observer.Stats().Connected == 2
+			}, 5*time.Second, 10*time.Millisecond)
+
+			tx, err := hex.DecodeString(genesisTx)
+			require.NoError(t, err)
+
+			txs := &levin.NewTransactions{
+				Txs:              [][]byte{tx},
+				Padding:          []byte{0x00},
+				DandelionppFluff: true,
+			}
+
+			require.NoError(t, aClient.Notify(ctx, txs))
+			first := <-announcements
+
+			assert.Equal(t, levin.ObjectTransaction, first.Kind)
+			assert.Equal(t, genesisTxHash, first.Hash)
+			assert.Equal(t, a.Addr().String(), first.Peer)
+			assert.True(t, first.First)
+			assert.True(t, first.Fluff)
+			assert.Zero(t, first.Delay)
+
+			// announcing it again doesn't count.
+			//
+			require.NoError(t, aClient.Notify(ctx, txs))
+			require.NoError(t, bClient.Notify(ctx, txs))
+			second := <-announcements
+
+			assert.Equal(t, genesisTxHash, second.Hash)
+			assert.Equal(t, b.Addr().String(), second.Peer)
+			assert.False(t, second.First)
+			assert.Equal(t, second.Time.Sub(first.Time), second.Delay)
+
+			require.NoError(t, bClient.Notify(ctx, &levin.NewFluffyBlock{
+				Block:                   levin.BlockCompleteEntry{Block: genesisBlockBlob(t)},
+				CurrentBlockchainHeight: 1,
+			}))
+			block := <-announcements
+
+			assert.Equal(t, levin.ObjectBlock, block.Kind)
+			assert.Equal(t, levin.MainnetGenesisTx, block.Hash)
+			assert.Equal(t, uint64(1), block.Height)
+			assert.True(t, block.First)
+
+			stats := observer.Stats()
+			assert.Equal(t, 1, stats.Transactions.Objects)
+			assert.Equal(t, 2, stats.Transactions.Announcements)
+			assert.Equal(t, second.Delay, stats.Transactions.Max)
+			assert.Equal(t, 1, stats.Blocks.Objects)
+			assert.Equal(t, 1, stats.Blocks.Announcements)
+
+			require.Len(t, stats.Peers, 2)
+			for _, peer := range stats.Peers {
+				switch peer.Address {
+				case a.Addr().String():
+					assert.Equal(t, 1, peer.Announcements)
+					assert.Equal(t, 1, peer.First)
+				case b.Addr().String():
+					assert.Equal(t, 2, peer.Announcements)
+					assert.Equal(t, 1, peer.First)
+					assert.Equal(t, second.Delay/2, peer.MeanDelay)
+				}
+			}
+
+			cancel()
+			assert.ErrorIs(t, <-observed, context.Canceled)
+		})
+
+		it("keeps accounting for objects no longer tracked", func() {
+			a, b := listen(), listen()
+
+			bAddr, err := levin.ParseNetworkAddress(b.Addr().String())
+			require.NoError(t, err)
+
+			aClients := serve(a, 1, &levin.Peer{Address: bAddr})
+			bClients := serve(b, 2)
+
+			var (
+				observer = levin.NewObserver(
+					levin.WithObservedPeers(2),
+					levin.WithObserverObjectTTL(50*time.Millisecond),
+				)
+				announcements = make(chan *levin.Announcement, 10)
+			)
+
+			go func() {
+				_ = observer.Observe(ctx, []string{a.Addr().String()}, func(a *levin.Announcement) {
+					announcements <- a
+				})
+			}()
+
+			aClient, bClient := <-aClients, <-bClients
+
+			require.Eventually(t, func() bool {
+				return observer.Stats().Connected == 2
+			}, 5*time.Second, 10*time.Millisecond)
+
+			tx, err := hex.DecodeString(genesisTx)
+			require.NoError(t, err)
+
+			txs := &levin.NewTransactions{Txs: [][]byte{tx}, Padding: []byte{}}
+
+			require.NoError(t, aClient.Notify(ctx, txs))
+			assert.True(t, (<-announcements).First)
+
+			require.NoError(t, bClient.Notify(ctx, txs))
+			assert.False(t, (<-announcements).First)
+
+			time.Sleep(100 * time.Millisecond)
+
+			// once expired, it's as if it had never been seen.
+			//
+			require.NoError(t, bClient.Notify(ctx, txs))
+			assert.True(t, (<-announcements).First)
+
+			stats := observer.Stats()
+			assert.Equal(t, 2, stats.Transactions.Objects)
+			assert.Equal(t, 3, stats.Transactions.Announcements)
+			assert.NotZero(t, stats.Transactions.Max)
+		})
+
+		it("connects again to peers after a backoff", func() {
+			a := listen()
+			aClients := serve(a, 1)
+
+			observer := levin.NewObserver(
+				levin.WithObserverRetryBackoff(50 * time.Millisecond))
+
+			go func() {
+				_ = observer.Observe(ctx, []string{a.Addr().String()},
+					func(*levin.Announcement) {})
+			}()
+
+			aClient := <-aClients
+
+			require.Eventually(t, func() bool {
+				return observer.Stats().Connected == 1
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.NoError(t, aClient.Close())
+
+			select {
+			case <-aClients:
+			case <-ctx.Done():
+				t.Fatal("not connected again")
+			}
+
+			require.Eventually(t, func() bool {
+				return observer.Stats().Connected == 1
+			}, 5*time.Second, 10*time.Millisecond)
+		})
+
+		it("fails once there are no peers left to connect to", func() {
+			unreachable := listen()
+			unreachable.Close()
+
+			err := levin.NewObserver(levin.WithObserverPeerTimeout(time.Second)).
+				Observe(ctx, []string{unreachable.Addr().String()}, func(*levin.Announcement) {})
+			assert.Error(t, err)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -2,7 +2,9 @@ package levin
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"math"
 )
 
 const (
@@ -17,64 +19,133 @@ const (
 	PortableRawSizeMarkInt64 uint64 = 0x03
 )
 
+const (
+	// PortableStorageMaxDepth is the maximum level of nesting of objects
+	// and arrays that the decoder accepts.
+	//
+	PortableStorageMaxDepth = 100
+
+	// PortableStorageMaxObjects is the maximum number of objects that the
+	// decoder accepts in a single portable storage.
+	//
+	PortableStorageMaxObjects = 65536
+
+	// PortableStorageMaxFields is the maximum number of fields (from all
+	// objects combined) that the decoder accepts in a single portable
+	// storage.
+	//
+	PortableStorageMaxFields = 65536
+
+	// PortableStorageMaxElements is the maximum number of array elements
+	// and strings (from the whole tree combined) that the decoder accepts
+	// in a single portable storage, bounding how much it allocates for
+	// packets full of tiny elements.
+	//
+	PortableStorageMaxElements = 1 << 20
+)
+
+var (
+	// ErrTruncated indicates that the input ended before a value could be
+	// fully read.
+	//
+	ErrTruncated = errors.New("truncated")
+
+	// ErrVarIntTooBig indicates that a varint holds a value that can't
+	// possibly be a valid length.
+	//
+	ErrVarIntTooBig = errors.New("varint too big")
+
+	// ErrLengthTooBig indicates that a string or array claims to be
+	// longer than the remaining input.
+	//
+	ErrLengthTooBig = errors.New("length exceeds remaining bytes")
+
+	// ErrMaxDepth indicates that objects and arrays are nested deeper than
+	// PortableStorageMaxDepth.
+	//
+	ErrMaxDepth = errors.New("max depth exceeded")
+
+	// ErrTooManyObjects indicates that the input has more objects or
+	// fields than allowed by PortableStorageMaxObjects or
+	// PortableStorageMaxFields.
+	//
+	ErrTooManyObjects = errors.New("too many objects")
+
+	// ErrTooManyElements indicates that the input has more array elements
+	// and strings than allowed by PortableStorageMaxElements.
+	//
+	ErrTooManyElements = errors.New("too many elements")
+
+	// ErrUnknownType indicates that a value is marked with a type that is
+	// not known.
+	//
+	ErrUnknownType = errors.New("unknown type")
+)
+
 type Entry struct {
 	Name         string
 	Serializable Serializable `json:"-,omitempty"`
 	Value        interface{}
+
+	// Type is the type marker that the value has been read with, telling
+	// apart, e.g., empty objects and arrays. Only set when decoding for
+	// inspection (see `DecodeMessage`).
+	//
+	Type byte `json:"-"`
 }
 
-func (e Entry) String() string {
+func (e Entry) String() (string, error) {
 	v, ok := e.Value.(string)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to string"))
+		return "", fmt.Errorf("expected string, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
-func (e Entry) Uint8() uint8 {
+func (e Entry) Uint8() (uint8, error) {
 	v, ok := e.Value.(uint8)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to uint8"))
+		return 0, fmt.Errorf("expected uint8, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
-func (e Entry) Uint16() uint16 {
+func (e Entry) Uint16() (uint16, error) {
 	v, ok := e.Value.(uint16)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to uint16"))
+		return 0, fmt.Errorf("expected uint16, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
-func (e Entry) Uint32() uint32 {
+func (e Entry) Uint32() (uint32, error) {
 	v, ok := e.Value.(uint32)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to uint32"))
+		return 0, fmt.Errorf("expected uint32, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
-func (e Entry) Uint64() uint64 {
+func (e Entry) Uint64() (uint64, error) {
 	v, ok := e.Value.(uint64)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to uint64"))
+		return 0, fmt.Errorf("expected uint64, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
-func (e Entry) Entries() Entries {
+func (e Entry) Entries() (Entries, error) {
 	v, ok := e.Value.(Entries)
 	if !ok {
-		panic(fmt.Errorf("interface couldnt be casted to levin.Entries"))
+		return nil, fmt.Errorf("expected levin.Entries, got %T", e.Value)
 	}
 
-	return v
+	return v, nil
 }
 
 func (e Entry) Bytes() []byte {
@@ -92,6 +163,10 @@ type PortableStorage struct {
 }
 
 func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
+	return newPortableStorageFromBytes(bytes, &decoder{})
+}
+
+func newPortableStorageFromBytes(bytes []byte, d *decoder) (*PortableStorage, error) {
 	var (
 		size = 0
 		idx  = 0
@@ -114,6 +189,11 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 
 	{ // sig-b
 		size = 4
+
+		if len(bytes[idx:]) < size {
+			return nil, fmt.Errorf("sig-b out of bounds")
+		}
+
 		sig := binary.LittleEndian.Uint32(bytes[idx : idx+size])
 		idx += size
 
@@ -124,6 +204,11 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 
 	{ // format ver
 		size = 1
+
+		if len(bytes[idx:]) < size {
+			return nil, fmt.Errorf("format ver out of bounds")
+		}
+
 		version := bytes[idx]
 		idx += size
 
@@ -134,167 +219,376 @@ func NewPortableStorageFromBytes(bytes []byte) (*PortableStorage, error) {
 
 	ps := &PortableStorage{}
 
-	_, ps.Entries = ReadObject(bytes[idx:])
+	_, entries, err := d.readObject(bytes[idx:])
+	if err != nil {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+
+	ps.Entries = entries
 
 	return ps, nil
 }
 
-func ReadString(bytes []byte) (int, string) {
+// decoder keeps track of the state necessary to enforce limits across the
+// whole tree being decoded.
+//
+type decoder struct {
+	depth    int
+	objects  int
+	fields   int
+	elements int
+
+	// typed indicates whether entries should carry the type marker that
+	// their values have been read with.
+	//
+	typed bool
+}
+
+func ReadString(bytes []byte) (int, string, error) {
+	return (&decoder{}).readString(bytes)
+}
+
+func ReadObject(bytes []byte) (int, Entries, error) {
+	return (&decoder{}).readObject(bytes)
+}
+
+func ReadArray(ttype byte, bytes []byte) (int, Entries, error) {
+	return (&decoder{}).readArray(ttype, bytes)
+}
+
+func ReadAny(bytes []byte, ttype byte) (int, interface{}, error) {
+	return (&decoder{}).readAny(bytes, ttype)
+}
+
+func (d *decoder) readString(bytes []byte) (int, string, error) {
 	idx := 0
 
-	n, strLen := ReadVarInt(bytes)
+	d.elements++
+	if d.elements > PortableStorageMaxElements {
+		return -1, "", ErrTooManyElements
+	}
+
+	n, strLen, err := ReadVarInt(bytes)
+	if err != nil {
+		return -1, "", fmt.Errorf("string length: %w", err)
+	}
+
 	idx += n
 
-	return idx + strLen, string(bytes[idx : idx+strLen])
+	if strLen > len(bytes[idx:]) {
+		return -1, "", fmt.Errorf("string of length %d: %w",
+			strLen, ErrLengthTooBig)
+	}
+
+	return idx + strLen, string(bytes[idx : idx+strLen]), nil
 }
 
-func ReadObject(bytes []byte) (int, Entries) {
+func (d *decoder) readObject(bytes []byte) (int, Entries, error) {
 	idx := 0
 
-	n, i := ReadVarInt(bytes[idx:])
+	if err := d.enter(); err != nil {
+		return -1, nil, err
+	}
+	defer d.leave()
+
+	d.objects++
+	if d.objects > PortableStorageMaxObjects {
+		return -1, nil, ErrTooManyObjects
+	}
+
+	n, i, err := ReadVarInt(bytes[idx:])
+	if err != nil {
+		return -1, nil, fmt.Errorf("number of entries: %w", err)
+	}
+
 	idx += n
 
+	// every entry takes at least 2 bytes (name length and type).
+	//
+	if i > len(bytes[idx:])/2 {
+		return -1, nil, fmt.Errorf("object w/ %d entries: %w",
+			i, ErrLengthTooBig)
+	}
+
+	d.fields += i
+	if d.fields > PortableStorageMaxFields {
+		return -1, nil, ErrTooManyObjects
+	}
+
 	entries := make(Entries, i)
 
 	for iter := 0; iter < i; iter++ {
 		entries[iter] = Entry{}
 		entry := &entries[iter]
 
+		if len(bytes[idx:]) < 1 {
+			return -1, nil, fmt.Errorf("entry %d name length: %w",
+				iter, ErrTruncated)
+		}
+
 		lenName := int(bytes[idx])
 		idx += 1
 
+		if len(bytes[idx:]) < lenName+1 {
+			return -1, nil, fmt.Errorf("entry %d name and type: %w",
+				iter, ErrTruncated)
+		}
+
 		entry.Name = string(bytes[idx : idx+lenName])
 		idx += lenName
 
 		ttype := bytes[idx]
 		idx += 1
 
-		n, obj := ReadAny(bytes[idx:], ttype)
+		n, obj, err := d.readAny(bytes[idx:], ttype)
+		if err != nil {
+			return -1, nil, fmt.Errorf("entry '%s': %w", entry.Name, err)
+		}
+
 		idx += n
 
 		entry.Value = obj
+		if d.typed {
+			entry.Type = ttype
+		}
 	}
 
-	return idx, entries
+	return idx, entries, nil
 }
 
-func ReadArray(ttype byte, bytes []byte) (int, Entries) {
+func (d *decoder) readArray(ttype byte, bytes []byte) (int, Entries, error) {
 	var (
 		idx = 0
 		n   = 0
 	)
 
-	n, i := ReadVarInt(bytes[idx:])
+	if err := d.enter(); err != nil {
+		return -1, nil, err
+	}
+	defer d.leave()
+
+	n, i, err := ReadVarInt(bytes[idx:])
+	if err != nil {
+		return -1, nil, fmt.Errorf("number of elements: %w", err)
+	}
+
 	idx += n
 
+	// every element takes at least 1 byte, or, for scalars, exactly the
+	// size of the type.
+	//
+	size := 1
+	if scalarSize, ok := scalarSizes[ttype]; ok {
+		size = scalarSize
+	}
+
+	if i > len(bytes[idx:])/size {
+		return -1, nil, fmt.Errorf("array w/ %d elements: %w",
+			i, ErrLengthTooBig)
+	}
+
+	d.elements += i
+	if d.elements > PortableStorageMaxElements {
+		return -1, nil, ErrTooManyElements
+	}
+
 	entries := make(Entries, i)
 
 	for iter := 0; iter < i; iter++ {
-		n, obj := ReadAny(bytes[idx:], ttype)
+		n, obj, err := d.readAny(bytes[idx:], ttype)
+		if err != nil {
+			return -1, nil, fmt.Errorf("element %d: %w", iter, err)
+		}
+
 		idx += n
 
 		entries[iter] = Entry{
 			Value: obj,
 		}
+		if d.typed {
+			entries[iter].Type = ttype
+		}
 	}
 
-	return idx, entries
+	return idx, entries, nil
 }
 
-func ReadAny(bytes []byte, ttype byte) (int, interface{}) {
-	var (
-		idx = 0
-		n   = 0
-	)
+func (d *decoder) enter() error {
+	d.depth++
+	if d.depth > PortableStorageMaxDepth {
+		return ErrMaxDepth
+	}
+
+	return nil
+}
+
+func (d *decoder) leave() {
+	d.depth--
+}
+
+// scalarSizes holds the number of bytes that each fixed-size type takes.
+//
+var scalarSizes = map[byte]int{
+	BoostSerializeTypeInt64:  8,
+	BoostSerializeTypeInt32:  4,
+	BoostSerializeTypeInt16:  2,
+	BoostSerializeTypeInt8:   1,
+	BoostSerializeTypeUint64: 8,
+	BoostSerializeTypeUint32: 4,
+	BoostSerializeTypeUint16: 2,
+	BoostSerializeTypeUint8:  1,
+	BoostSerializeTypeDouble: 8,
+	BoostSerializeTypeBool:   1,
+}
+
+// nolint:cyclop
+func (d *decoder) readAny(bytes []byte, ttype byte) (int, interface{}, error) {
+	idx := 0
 
 	if ttype&BoostSerializeFlagArray != 0 {
 		internalType := ttype &^ BoostSerializeFlagArray
-		n, obj := ReadArray(internalType, bytes[idx:])
+		n, obj, err := d.readArray(internalType, bytes[idx:])
+		if err != nil {
+			return -1, nil, err
+		}
+
 		idx += n
 
-		return idx, obj
+		return idx, obj, nil
 	}
 
-	if ttype == BoostSerializeTypeObject {
-		n, obj := ReadObject(bytes[idx:])
-		idx += n
+	// elements of an array of arrays carry their own type marker.
+	//
+	if ttype == BoostSerializeTypeArray {
+		if len(bytes) < 1 {
+			return -1, nil, fmt.Errorf("array type: %w", ErrTruncated)
+		}
 
-		return idx, obj
-	}
+		internalType := bytes[idx]
+		idx += 1
 
-	if ttype == BoostSerializeTypeUint8 {
-		obj := uint8(bytes[idx])
-		n += 1
-		idx += n
+		if internalType&BoostSerializeFlagArray == 0 {
+			return -1, nil, fmt.Errorf("array element of non-array "+
+				"type %x: %w", internalType, ErrUnknownType)
+		}
 
-		return idx, obj
-	}
+		n, obj, err := d.readAny(bytes[idx:], internalType)
+		if err != nil {
+			return -1, nil, err
+		}
 
-	if ttype == BoostSerializeTypeUint16 {
-		obj := binary.LittleEndian.Uint16(bytes[idx:])
-		n += 2
 		idx += n
 
-		return idx, obj
+		return idx, obj, nil
 	}
 
-	if ttype == BoostSerializeTypeUint32 {
-		obj := binary.LittleEndian.Uint32(bytes[idx:])
-		n += 4
+	if ttype == BoostSerializeTypeObject {
+		n, obj, err := d.readObject(bytes[idx:])
+		if err != nil {
+			return -1, nil, err
+		}
+
 		idx += n
 
-		return idx, obj
+		return idx, obj, nil
 	}
 
-	if ttype == BoostSerializeTypeUint64 {
-		obj := binary.LittleEndian.Uint64(bytes[idx:])
-		n += 8
+	if ttype == BoostSerializeTypeString {
+		n, obj, err := d.readString(bytes[idx:])
+		if err != nil {
+			return -1, nil, err
+		}
+
 		idx += n
 
-		return idx, obj
+		return idx, obj, nil
 	}
 
-	if ttype == BoostSerializeTypeInt64 {
-		obj := binary.LittleEndian.Uint64(bytes[idx:])
-		n += 8
-		idx += n
-
-		return idx, int64(obj)
+	size, ok := scalarSizes[ttype]
+	if !ok {
+		return -1, nil, fmt.Errorf("type %x: %w", ttype, ErrUnknownType)
 	}
 
-	if ttype == BoostSerializeTypeString {
-		n, obj := ReadString(bytes[idx:])
-		idx += n
+	if len(bytes) < size {
+		return -1, nil, fmt.Errorf("type %x w/ %d bytes: %w",
+			ttype, size, ErrTruncated)
+	}
 
-		return idx, obj
+	b := bytes[idx : idx+size]
+	idx += size
+
+	switch ttype {
+	case BoostSerializeTypeUint8:
+		return idx, b[0], nil
+	case BoostSerializeTypeUint16:
+		return idx, binary.LittleEndian.Uint16(b), nil
+	case BoostSerializeTypeUint32:
+		return idx, binary.LittleEndian.Uint32(b), nil
+	case BoostSerializeTypeUint64:
+		return idx, binary.LittleEndian.Uint64(b), nil
+	case BoostSerializeTypeInt8:
+		return idx, int8(b[0]), nil
+	case BoostSerializeTypeInt16:
+		return idx, int16(binary.LittleEndian.Uint16(b)), nil
+	case BoostSerializeTypeInt32:
+		return idx, int32(binary.LittleEndian.Uint32(b)), nil
+	case BoostSerializeTypeInt64:
+		return idx, int64(binary.LittleEndian.Uint64(b)), nil
+	case BoostSerializeTypeDouble:
+		return idx, math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case BoostSerializeTypeBool:
+		return idx, b[0] != 0, nil
 	}
 
-	panic(fmt.Errorf("unknown ttype %x", ttype))
-	return -1, nil
+	return -1, nil, fmt.Errorf("type %x: %w", ttype, ErrUnknownType)
 }
 
 // reads var int, returning number of bytes read and the integer in that byte
 // sequence.
 //
-func ReadVarInt(b []byte) (int, int) {
+func ReadVarInt(b []byte) (int, int, error) {
+	if len(b) < 1 {
+		return -1, -1, fmt.Errorf("varint: %w", ErrTruncated)
+	}
+
 	sizeMask := b[0] & PortableRawSizeMarkMask
 
-	switch uint32(sizeMask) {
-	case uint32(PortableRawSizeMarkByte):
-		return 1, int(b[0] >> 2)
-	case uint32(PortableRawSizeMarkWord):
-		return 2, int((binary.LittleEndian.Uint16(b[0:2])) >> 2)
-	case PortableRawSizeMarkDword:
-		return 4, int((binary.LittleEndian.Uint32(b[0:4])) >> 2)
-	case uint32(PortableRawSizeMarkInt64):
-		panic("int64 not supported") // TODO
-		// return int((binary.LittleEndian.Uint64(b[0:8])) >> 2)
-		//         '-> bad
-	default:
-		panic(fmt.Errorf("malformed sizemask: %+v", sizeMask))
-	}
-
-	return -1, -1
+	var size int
+
+	switch uint64(sizeMask) {
+	case uint64(PortableRawSizeMarkByte):
+		size = 1
+	case uint64(PortableRawSizeMarkWord):
+		size = 2
+	case uint64(PortableRawSizeMarkDword):
+		size = 4
+	case PortableRawSizeMarkInt64:
+		size = 8
+	}
+
+	if len(b) < size {
+		return -1, -1, fmt.Errorf("varint of %d bytes: %w",
+			size, ErrTruncated)
+	}
+
+	switch size {
+	case 1:
+		return 1, int(b[0] >> 2), nil
+	case 2:
+		return 2, int((binary.LittleEndian.Uint16(b[0:2])) >> 2), nil
+	case 4:
+		return 4, int((binary.LittleEndian.Uint32(b[0:4])) >> 2), nil
+	}
+
+	// anything that doesn't fit in 32 bits is way past the biggest
+	// levin packet allowed.
+	//
+	v := binary.LittleEndian.Uint64(b[0:8]) >> 2
+	if v > math.MaxInt32 {
+		return -1, -1, fmt.Errorf("varint %d: %w", v, ErrVarIntTooBig)
+	}
+
+	return 8, int(v), nil
 }
 
 func (s *PortableStorage) Bytes() []byte {
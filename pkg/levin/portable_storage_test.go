@@ -6,6 +6,7 @@ import (
 	"github.com/sclevine/spec"
 	"github.com/sclevine/spec/report"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cirocosta/go-monero/pkg/levin"
 )
@@ -104,7 +105,8 @@ func TestPortableStorage(t *testing.T) {
 	spec.Run(t, "ReadVarIn", func(t *testing.T, when spec.G, it spec.S) {
 		it("i <= 63", func() {
 			b := []byte{0x08}
-			n, v := levin.ReadVarInt(b)
+			n, v, err := levin.ReadVarInt(b)
+			assert.NoError(t, err)
 
 			assert.Equal(t, n, 1)
 			assert.Equal(t, v, 2)
@@ -112,17 +114,43 @@ func TestPortableStorage(t *testing.T) {
 
 		it("64 <= i <= 16383", func() {
 			b := []byte{0x01, 0x02}
-			n, v := levin.ReadVarInt(b)
+			n, v, err := levin.ReadVarInt(b)
+			assert.NoError(t, err)
 			assert.Equal(t, n, 2)
 			assert.Equal(t, v, 128)
 		})
 
 		it("16384 <= i <= 1073741823", func() {
 			b := []byte{0x02, 0x00, 0x01, 0x00}
-			n, v := levin.ReadVarInt(b)
+			n, v, err := levin.ReadVarInt(b)
+			assert.NoError(t, err)
 			assert.Equal(t, n, 4)
 			assert.Equal(t, v, 16384)
 		})
+
+		it("1073741824 <= i", func() {
+			b := []byte{0x03, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00}
+			n, v, err := levin.ReadVarInt(b)
+			assert.NoError(t, err)
+			assert.Equal(t, n, 8)
+			assert.Equal(t, v, 1<<30)
+		})
+
+		it("fails w/ empty input", func() {
+			_, _, err := levin.ReadVarInt([]byte{})
+			assert.ErrorIs(t, err, levin.ErrTruncated)
+		})
+
+		it("fails w/ truncated input", func() {
+			_, _, err := levin.ReadVarInt([]byte{0x02, 0x00})
+			assert.ErrorIs(t, err, levin.ErrTruncated)
+		})
+
+		it("fails w/ oversized value", func() {
+			b := []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+			_, _, err := levin.ReadVarInt(b)
+			assert.ErrorIs(t, err, levin.ErrVarIntTooBig)
+		})
 	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
 
 	spec.Run(t, "VarrIn", func(t *testing.T, when spec.G, it spec.S) {
@@ -222,4 +250,219 @@ func TestPortableStorage(t *testing.T) {
 			}, ps.Bytes())
 		})
 	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "RoundTrip", func(t *testing.T, when spec.G, it spec.S) {
+		roundtrip := func(serializable levin.Serializable) interface{} {
+			ps := &levin.PortableStorage{
+				Entries: []levin.Entry{
+					{
+						Name:         "v",
+						Serializable: serializable,
+					},
+				},
+			}
+
+			decoded, err := levin.NewPortableStorageFromBytes(ps.Bytes())
+			require.NoError(t, err)
+			require.Len(t, decoded.Entries, 1)
+			assert.Equal(t, "v", decoded.Entries[0].Name)
+
+			return decoded.Entries[0].Value
+		}
+
+		for _, tc := range []struct {
+			name         string
+			serializable levin.Serializable
+			expected     interface{}
+		}{
+			{"int8", levin.BoostInt8(-8), int8(-8)},
+			{"int16", levin.BoostInt16(-1600), int16(-1600)},
+			{"int32", levin.BoostInt32(-320000), int32(-320000)},
+			{"int64", levin.BoostInt64(-1 << 40), int64(-1 << 40)},
+			{"uint8", levin.BoostByte(8), uint8(8)},
+			{"uint16", levin.BoostUint16(1600), uint16(1600)},
+			{"uint32", levin.BoostUint32(320000), uint32(320000)},
+			{"uint64", levin.BoostUint64(1 << 40), uint64(1 << 40)},
+			{"double", levin.BoostDouble(3.14), float64(3.14)},
+			{"string", levin.BoostString("foo"), "foo"},
+			{"bool true", levin.BoostBool(true), true},
+			{"bool false", levin.BoostBool(false), false},
+			{
+				"object",
+				levin.Section{
+					Entries: []levin.Entry{
+						{Name: "a", Serializable: levin.BoostBool(true)},
+					},
+				},
+				levin.Entries{{Name: "a", Value: true}},
+			},
+		} {
+			tc := tc
+
+			it("roundtrips "+tc.name, func() {
+				assert.Equal(t, tc.expected, roundtrip(tc.serializable))
+			})
+
+			it("roundtrips array of "+tc.name, func() {
+				array := levin.BoostArray{
+					Type:   tc.serializable.Bytes()[0],
+					Values: []levin.Serializable{tc.serializable, tc.serializable},
+				}
+
+				assert.Equal(t, levin.Entries{
+					{Value: tc.expected},
+					{Value: tc.expected},
+				}, roundtrip(array))
+			})
+		}
+
+		it("roundtrips empty array", func() {
+			array := levin.BoostArray{Type: levin.BoostSerializeTypeUint64}
+
+			assert.Equal(t, levin.Entries{}, roundtrip(array))
+		})
+
+		it("roundtrips array of arrays", func() {
+			array := levin.BoostArray{
+				Type: levin.BoostSerializeTypeArray,
+				Values: []levin.Serializable{
+					levin.BoostArray{
+						Type:   levin.BoostSerializeTypeUint8,
+						Values: []levin.Serializable{levin.BoostByte(1)},
+					},
+					levin.BoostArray{
+						Type:   levin.BoostSerializeTypeString,
+						Values: []levin.Serializable{levin.BoostString("a")},
+					},
+				},
+			}
+
+			assert.Equal(t, levin.Entries{
+				{Value: levin.Entries{{Value: uint8(1)}}},
+				{Value: levin.Entries{{Value: "a"}}},
+			}, roundtrip(array))
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "HostileInput", func(t *testing.T, when spec.G, it spec.S) {
+		header := []byte{
+			0x01, 0x11, 0x01, 0x01, // sig a
+			0x01, 0x01, 0x02, 0x01, // sig b
+			0x01, // format ver
+		}
+
+		decode := func(body ...byte) error {
+			_, err := levin.NewPortableStorageFromBytes(
+				append(append([]byte{}, header...), body...))
+			return err
+		}
+
+		it("fails w/ truncated signature", func() {
+			_, err := levin.NewPortableStorageFromBytes(header[:6])
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "sig-b out of bounds")
+		})
+
+		it("fails w/ missing format ver", func() {
+			_, err := levin.NewPortableStorageFromBytes(header[:8])
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "format ver out of bounds")
+		})
+
+		it("fails w/ missing entries", func() {
+			assert.ErrorIs(t, decode(), levin.ErrTruncated)
+		})
+
+		it("fails w/ more entries than bytes", func() {
+			assert.ErrorIs(t, decode(0x10, 0x01, 0x61), levin.ErrLengthTooBig)
+		})
+
+		it("fails w/ truncated name", func() {
+			assert.ErrorIs(t, decode(0x04, 0x05, 0x61), levin.ErrTruncated)
+		})
+
+		it("fails w/ truncated scalar", func() {
+			assert.ErrorIs(t, decode(0x04, 0x01, 0x61,
+				levin.BoostSerializeTypeUint64, 0x01), levin.ErrTruncated)
+		})
+
+		it("fails w/ string longer than input", func() {
+			assert.ErrorIs(t, decode(0x04, 0x01, 0x61,
+				levin.BoostSerializeTypeString, 0x40, 0x61), levin.ErrLengthTooBig)
+		})
+
+		it("fails w/ array longer than input", func() {
+			assert.ErrorIs(t, decode(0x04, 0x01, 0x61,
+				levin.BoostSerializeTypeUint8|levin.BoostSerializeFlagArray,
+				0x02, 0x00, 0x01, 0x00), levin.ErrLengthTooBig)
+		})
+
+		it("fails w/ array of scalars longer than input", func() {
+			assert.ErrorIs(t, decode(0x04, 0x01, 0x61,
+				levin.BoostSerializeTypeUint64|levin.BoostSerializeFlagArray,
+				0x08, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00),
+				levin.ErrLengthTooBig)
+		})
+
+		// array w/ `n` uint8 elements named `name`.
+		//
+		bytesArray := func(name byte, n int) []byte {
+			count, err := levin.VarIn(n)
+			require.NoError(t, err)
+
+			b := []byte{0x01, name, levin.BoostSerializeTypeUint8 | levin.BoostSerializeFlagArray}
+			b = append(b, count...)
+
+			return append(b, make([]byte, n)...)
+		}
+
+		it("fails w/ more array elements than allowed", func() {
+			body := append([]byte{0x04}, bytesArray('a', levin.PortableStorageMaxElements+1)...)
+
+			assert.ErrorIs(t, decode(body...), levin.ErrTooManyElements)
+		})
+
+		it("fails w/ more array elements than allowed across arrays", func() {
+			body := []byte{0x08}
+			body = append(body, bytesArray('a', levin.PortableStorageMaxElements/2+1)...)
+			body = append(body, bytesArray('b', levin.PortableStorageMaxElements/2)...)
+
+			assert.ErrorIs(t, decode(body...), levin.ErrTooManyElements)
+		})
+
+		it("fails w/ more strings than allowed", func() {
+			count, err := levin.VarIn(levin.PortableStorageMaxElements/2 + 1)
+			require.NoError(t, err)
+
+			// every string is counted, as well as every element.
+			//
+			body := []byte{0x04, 0x01, 0x61,
+				levin.BoostSerializeTypeString | levin.BoostSerializeFlagArray}
+			body = append(body, count...)
+			body = append(body, make([]byte, levin.PortableStorageMaxElements/2+1)...)
+
+			assert.ErrorIs(t, decode(body...), levin.ErrTooManyElements)
+		})
+
+		it("accepts as many array elements as allowed", func() {
+			body := append([]byte{0x04}, bytesArray('a', levin.PortableStorageMaxElements)...)
+
+			assert.NoError(t, decode(body...))
+		})
+
+		it("fails w/ unknown type", func() {
+			assert.ErrorIs(t, decode(0x04, 0x01, 0x61, 0x7f), levin.ErrUnknownType)
+		})
+
+		it("fails w/ excessive nesting", func() {
+			body := []byte{}
+			for i := 0; i <= levin.PortableStorageMaxDepth; i++ {
+				body = append(body, 0x04, 0x01, 0x61, levin.BoostSerializeTypeObject)
+			}
+
+			body = append(body, 0x00)
+
+			assert.ErrorIs(t, decode(body...), levin.ErrMaxDepth)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
 }
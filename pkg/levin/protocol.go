@@ -0,0 +1,339 @@
+package levin
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+)
+
+// see https://github.com/monero-project/monero/blob/e45619e61e4831eea70a43fe6985f4d57ea02e9e/src/cryptonote_protocol/cryptonote_protocol_defs.h
+
+const HashSize = 32
+
+// Hash is a 32-byte identifier of either a block or a transaction.
+//
+type Hash [HashSize]byte
+
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+// Notification is a message from the cryptonote protocol that can be carried
+// as the payload of a levin packet, encoded via `Marshal`.
+//
+type Notification interface {
+	// Command is the levin command under which the notification is
+	// transmitted.
+	//
+	Command() uint32
+}
+
+// NewNotification instantiates an empty notification of the type
+// corresponding to the command `command`.
+//
+func NewNotification(command uint32) (Notification, error) {
+	switch command {
+	case NotifyNewBlock:
+		return &NewBlock{}, nil
+	case NotifyNewTransactions:
+		return &NewTransactions{}, nil
+	case NotifyRequestGetObjects:
+		return &RequestGetObjects{}, nil
+	case NotifyResponseGetObjects:
+		return &ResponseGetObjects{}, nil
+	case NotifyRequestChain:
+		return &RequestChain{}, nil
+	case NotifyResponseChainEntry:
+		return &ResponseChainEntry{}, nil
+	case NotifyNewFluffyBlock:
+		return &NewFluffyBlock{}, nil
+	case NotifyRequestFluffyMissingTx:
+		return &RequestFluffyMissingTx{}, nil
+	case NotifyGetTxPoolComplement:
+		return &GetTxPoolComplement{}, nil
+	}
+
+	return nil, fmt.Errorf("command %d is not a notification", command)
+}
+
+// DecodeNotification decodes the payload of a levin packet carrying a
+// notification of command `command`.
+//
+func DecodeNotification(command uint32, payload []byte) (Notification, error) {
+	notification, err := NewNotification(command)
+	if err != nil {
+		return nil, fmt.Errorf("new notification: %w", err)
+	}
+
+	if err := Unmarshal(payload, notification); err != nil {
+		return nil, fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return notification, nil
+}
+
+// CoreSyncData is the state of the blockchain of a node, exchanged as
+// `payload_data` during handshakes and timed syncs.
+//
+type CoreSyncData struct {
+	CurrentHeight             uint64 `epee:"current_height"`
+	CumulativeDifficulty      uint64 `epee:"cumulative_difficulty"`
+	CumulativeDifficultyTop64 uint64 `epee:"cumulative_difficulty_top64"`
+	TopID                     Hash   `epee:"top_id"`
+	TopVersion                uint8  `epee:"top_version"`
+	PruningSeed               uint32 `epee:"pruning_seed"`
+}
+
+// TxBlobEntry is a transaction as carried by a block complete entry.
+//
+type TxBlobEntry struct {
+	Blob []byte `epee:"blob"`
+
+	// PrunableHash is the hash of the prunable part of the transaction,
+	// only set when the entry it belongs to is pruned.
+	//
+	PrunableHash Hash `epee:"prunable_hash"`
+}
+
+// BlockCompleteEntry is a block blob along with the blobs of the
+// transactions it includes.
+//
+type BlockCompleteEntry struct {
+	Pruned      bool
+	Block       []byte
+	BlockWeight uint64
+	Txs         []TxBlobEntry
+}
+
+// blockCompleteEntryFields are the fields of a block complete entry whose
+// encoding doesn't depend on whether the entry is pruned or not.
+//
+type blockCompleteEntryFields struct {
+	Pruned      bool   `epee:"pruned,omitempty"`
+	Block       []byte `epee:"block"`
+	BlockWeight uint64 `epee:"block_weight,omitempty"`
+}
+
+func (e *BlockCompleteEntry) MarshalEpee() (Serializable, error) {
+	entries, err := marshalStruct(reflect.ValueOf(blockCompleteEntryFields{
+		Pruned:      e.Pruned,
+		Block:       e.Block,
+		BlockWeight: e.BlockWeight,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	// non-pruned entries carry the transactions as plain blobs, while
+	// pruned ones wrap them in objects along with the prunable hash.
+	//
+	var txs interface{} = e.Txs
+	if !e.Pruned {
+		blobs := make([][]byte, len(e.Txs))
+		for idx, tx := range e.Txs {
+			blobs[idx] = tx.Blob
+		}
+
+		txs = blobs
+	}
+
+	serializable, err := marshalValue(reflect.ValueOf(txs), false)
+	if err != nil {
+		return nil, fmt.Errorf("txs: %w", err)
+	}
+
+	if serializable != nil {
+		entries = append(entries, Entry{
+			Name:         "txs",
+			Serializable: serializable,
+		})
+	}
+
+	return Section{Entries: entries}, nil
+}
+
+func (e *BlockCompleteEntry) UnmarshalEpee(value interface{}) error {
+	entries, ok := value.(Entries)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+
+	fields := blockCompleteEntryFields{}
+	if err := unmarshalStruct(entries, reflect.ValueOf(&fields).Elem()); err != nil {
+		return err
+	}
+
+	e.Pruned, e.Block, e.BlockWeight = fields.Pruned, fields.Block, fields.BlockWeight
+
+	for _, entry := range entries {
+		if entry.Name != "txs" {
+			continue
+		}
+
+		elements, ok := entry.Value.(Entries)
+		if !ok {
+			return fmt.Errorf("txs: expected array, got %T", entry.Value)
+		}
+
+		e.Txs = make([]TxBlobEntry, len(elements))
+		for idx, element := range elements {
+			// non-pruned: plain blob.
+			//
+			if blob, ok := element.Value.(string); ok {
+				e.Txs[idx].Blob = []byte(blob)
+				continue
+			}
+
+			// pruned: object w/ blob and prunable hash.
+			//
+			err := unmarshalValue(element.Value,
+				reflect.ValueOf(&e.Txs[idx]).Elem(), false)
+			if err != nil {
+				return fmt.Errorf("txs: element %d: %w", idx, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewBlock (NOTIFY_NEW_BLOCK) announces a new block along with all of its
+// transactions.
+//
+type NewBlock struct {
+	Block                   BlockCompleteEntry `epee:"b"`
+	CurrentBlockchainHeight uint64             `epee:"current_blockchain_height"`
+}
+
+func (n *NewBlock) Command() uint32 {
+	return NotifyNewBlock
+}
+
+// NewFluffyBlock (NOTIFY_NEW_FLUFFY_BLOCK) announces a new block carrying only
+// the transactions that the receiver is not expected to have in its pool.
+//
+type NewFluffyBlock struct {
+	Block                   BlockCompleteEntry `epee:"b"`
+	CurrentBlockchainHeight uint64             `epee:"current_blockchain_height"`
+}
+
+func (n *NewFluffyBlock) Command() uint32 {
+	return NotifyNewFluffyBlock
+}
+
+// NewTransactions (NOTIFY_NEW_TRANSACTIONS) relays transactions to a peer.
+//
+type NewTransactions struct {
+	Txs [][]byte `epee:"txs"`
+
+	// Padding is filled with random bytes to obscure the size of the
+	// message.
+	//
+	Padding []byte `epee:"_"`
+
+	// DandelionppFluff is false when the transactions are being relayed
+	// in the stem phase of dandelion++.
+	//
+	DandelionppFluff bool `epee:"dandelionpp_fluff"`
+}
+
+func (n *NewTransactions) Command() uint32 {
+	return NotifyNewTransactions
+}
+
+func (n *NewTransactions) UnmarshalEpee(value interface{}) error {
+	entries, ok := value.(Entries)
+	if !ok {
+		return fmt.Errorf("expected object, got %T", value)
+	}
+
+	// absence of the flag means that the sender is fluffing.
+	//
+	type alias NewTransactions
+	a := alias{DandelionppFluff: true}
+
+	if err := unmarshalStruct(entries, reflect.ValueOf(&a).Elem()); err != nil {
+		return err
+	}
+
+	*n = NewTransactions(a)
+	return nil
+}
+
+// RequestGetObjects (NOTIFY_REQUEST_GET_OBJECTS) requests blocks by their ids.
+//
+type RequestGetObjects struct {
+	Blocks []Hash `epee:"blocks,blob"`
+	Prune  bool   `epee:"prune"`
+}
+
+func (n *RequestGetObjects) Command() uint32 {
+	return NotifyRequestGetObjects
+}
+
+// ResponseGetObjects (NOTIFY_RESPONSE_GET_OBJECTS) carries the blocks
+// requested via RequestGetObjects.
+//
+type ResponseGetObjects struct {
+	Blocks                  []BlockCompleteEntry `epee:"blocks"`
+	MissedIDs               []Hash               `epee:"missed_ids,blob"`
+	CurrentBlockchainHeight uint64               `epee:"current_blockchain_height"`
+}
+
+func (n *ResponseGetObjects) Command() uint32 {
+	return NotifyResponseGetObjects
+}
+
+// RequestChain (NOTIFY_REQUEST_CHAIN) asks a peer for the ids of the blocks
+// that follow the sparse chain history in `BlockIDs`.
+//
+type RequestChain struct {
+	BlockIDs []Hash `epee:"block_ids,blob"`
+	Prune    bool   `epee:"prune"`
+}
+
+func (n *RequestChain) Command() uint32 {
+	return NotifyRequestChain
+}
+
+// ResponseChainEntry (NOTIFY_RESPONSE_CHAIN_ENTRY) is the answer to a
+// RequestChain.
+//
+type ResponseChainEntry struct {
+	StartHeight               uint64   `epee:"start_height"`
+	TotalHeight               uint64   `epee:"total_height"`
+	CumulativeDifficulty      uint64   `epee:"cumulative_difficulty"`
+	CumulativeDifficultyTop64 uint64   `epee:"cumulative_difficulty_top64"`
+	BlockIDs                  []Hash   `epee:"m_block_ids,blob"`
+	BlockWeights              []uint64 `epee:"m_block_weights,blob"`
+	FirstBlock                []byte   `epee:"first_block"`
+}
+
+func (n *ResponseChainEntry) Command() uint32 {
+	return NotifyResponseChainEntry
+}
+
+// RequestFluffyMissingTx (NOTIFY_REQUEST_FLUFFY_MISSING_TX) asks the peer that
+// announced a fluffy block for the transactions that couldn't be found
+// locally.
+//
+type RequestFluffyMissingTx struct {
+	BlockHash               Hash     `epee:"block_hash"`
+	CurrentBlockchainHeight uint64   `epee:"current_blockchain_height"`
+	MissingTxIndices        []uint64 `epee:"missing_tx_indices,blob"`
+}
+
+func (n *RequestFluffyMissingTx) Command() uint32 {
+	return NotifyRequestFluffyMissingTx
+}
+
+// GetTxPoolComplement (NOTIFY_GET_TXPOOL_COMPLEMENT) asks a peer for the
+// transactions in its pool that are not in `Hashes`.
+//
+type GetTxPoolComplement struct {
+	Hashes []Hash `epee:"hashes,blob"`
+}
+
+func (n *GetTxPoolComplement) Command() uint32 {
+	return NotifyGetTxPoolComplement
+}
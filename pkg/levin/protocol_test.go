@@ -0,0 +1,175 @@
+package levin_test
+
+import (
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+func hash(b byte) levin.Hash {
+	h := levin.Hash{}
+	for idx := range h {
+		h[idx] = b
+	}
+
+	return h
+}
+
+// nolint:funlen
+func TestProtocol(t *testing.T) {
+	spec.Run(t, "Notification", func(t *testing.T, when spec.G, it spec.S) {
+		roundtrip := func(n levin.Notification) levin.Notification {
+			payload, err := levin.Marshal(n)
+			require.NoError(t, err)
+
+			decoded, err := levin.DecodeNotification(n.Command(), payload)
+			require.NoError(t, err)
+
+			return decoded
+		}
+
+		it("fails w/ non-notification command", func() {
+			_, err := levin.NewNotification(levin.CommandPing)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "not a notification")
+		})
+
+		it("roundtrips new block", func() {
+			n := &levin.NewBlock{
+				Block: levin.BlockCompleteEntry{
+					Block: []byte{0x01, 0x02, 0x03},
+					Txs: []levin.TxBlobEntry{
+						{Blob: []byte{0xaa}},
+						{Blob: []byte{0xbb, 0xcc}},
+					},
+				},
+				CurrentBlockchainHeight: 123,
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips new fluffy block w/ pruned txs", func() {
+			n := &levin.NewFluffyBlock{
+				Block: levin.BlockCompleteEntry{
+					Pruned:      true,
+					Block:       []byte{0x01},
+					BlockWeight: 300000,
+					Txs: []levin.TxBlobEntry{
+						{Blob: []byte{0xaa}, PrunableHash: hash(0x01)},
+					},
+				},
+				CurrentBlockchainHeight: 2,
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips new transactions", func() {
+			n := &levin.NewTransactions{
+				Txs:              [][]byte{{0x01}, {0x02, 0x03}},
+				Padding:          []byte{0x00, 0x00},
+				DandelionppFluff: false,
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("defaults dandelionpp_fluff to true", func() {
+			n := &levin.NewTransactions{}
+
+			err := levin.UnmarshalPortableStorage(&levin.PortableStorage{}, n)
+			assert.NoError(t, err)
+			assert.True(t, n.DandelionppFluff)
+		})
+
+		it("roundtrips request get objects", func() {
+			n := &levin.RequestGetObjects{
+				Blocks: []levin.Hash{hash(0x01), hash(0x02)},
+				Prune:  true,
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips response get objects", func() {
+			n := &levin.ResponseGetObjects{
+				Blocks: []levin.BlockCompleteEntry{
+					{Block: []byte{0x01}},
+					{Block: []byte{0x02}, Txs: []levin.TxBlobEntry{{Blob: []byte{0x03}}}},
+				},
+				MissedIDs:               []levin.Hash{hash(0xff)},
+				CurrentBlockchainHeight: 10,
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips request chain", func() {
+			n := &levin.RequestChain{
+				BlockIDs: []levin.Hash{hash(0x0a), hash(0x0b), hash(0x0c)},
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips response chain entry", func() {
+			n := &levin.ResponseChainEntry{
+				StartHeight:               1,
+				TotalHeight:               100,
+				CumulativeDifficulty:      1 << 40,
+				CumulativeDifficultyTop64: 1,
+				BlockIDs:                  []levin.Hash{hash(0x01), hash(0x02)},
+				BlockWeights:              []uint64{1, 1 << 33},
+				FirstBlock:                []byte{0xde, 0xad},
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips request fluffy missing tx", func() {
+			n := &levin.RequestFluffyMissingTx{
+				BlockHash:               hash(0x42),
+				CurrentBlockchainHeight: 7,
+				MissingTxIndices:        []uint64{0, 3},
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("roundtrips get txpool complement", func() {
+			n := &levin.GetTxPoolComplement{
+				Hashes: []levin.Hash{hash(0x01)},
+			}
+
+			assert.Equal(t, n, roundtrip(n))
+		})
+
+		it("fails w/ malformed pod blob", func() {
+			ps := &levin.PortableStorage{
+				Entries: levin.Entries{
+					{Name: "hashes", Value: "abc"},
+				},
+			}
+
+			err := levin.UnmarshalPortableStorage(ps, &levin.GetTxPoolComplement{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "not a multiple of 32")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+
+	spec.Run(t, "NewHeaderFromBytes", func(t *testing.T, when spec.G, it spec.S) {
+		it("accepts notification commands", func() {
+			bytes := levin.NewRequestHeader(levin.NotifyNewTransactions, 0).Bytes()
+
+			header, err := levin.NewHeaderFromBytesBytes(bytes)
+			assert.NoError(t, err)
+			assert.Equal(t, levin.NotifyNewTransactions, header.Command)
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
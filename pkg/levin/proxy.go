@@ -0,0 +1,281 @@
+package levin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Direction is the direction that a message relayed by a Proxy went.
+//
+type Direction string
+
+const (
+	// DirectionUpstream is the direction of messages sent by the node
+	// that connected to the proxy to the upstream peer.
+	//
+	DirectionUpstream Direction = "upstream"
+
+	// DirectionDownstream is the direction of messages sent by the
+	// upstream peer to the node that connected to the proxy.
+	//
+	DirectionDownstream Direction = "downstream"
+)
+
+// ProxiedMessage is a message relayed by a Proxy.
+//
+type ProxiedMessage struct {
+	// Connection identifies the proxied connection that the message has
+	// been relayed through.
+	//
+	Connection uint64 `json:"connection"`
+
+	Direction Direction `json:"direction,omitempty"`
+
+	// Size is the number of bytes that carried the message, headers
+	// (and those of the fragments, if fragmented) included.
+	//
+	Size int `json:"size"`
+
+	*DecodedMessage
+}
+
+// Proxy relays the levin traffic between nodes that connect to it and an
+// upstream peer, decoding every message that goes through it without
+// interfering w/ the connection.
+//
+type Proxy struct {
+	upstream string
+	cfg      ProxyConfig
+
+	// handlerMu serializes the calls to the message handler.
+	//
+	handlerMu sync.Mutex
+
+	connections uint64
+}
+
+type ProxyConfig struct {
+	// ContextDialer is used for connecting to the upstream peer.
+	//
+	ContextDialer ContextDialer
+
+	// MessageHandler is called w/ every message relayed (or with
+	// failures to relay them), one at a time.
+	//
+	MessageHandler func(m *ProxiedMessage)
+}
+
+type ProxyOption func(*ProxyConfig)
+
+func WithUpstreamDialer(v ContextDialer) func(*ProxyConfig) {
+	return func(c *ProxyConfig) {
+		c.ContextDialer = v
+	}
+}
+
+func WithMessageHandler(v func(m *ProxiedMessage)) func(*ProxyConfig) {
+	return func(c *ProxyConfig) {
+		c.MessageHandler = v
+	}
+}
+
+// NewProxy instantiates a proxy to the peer at `upstream`.
+//
+func NewProxy(upstream string, opts ...ProxyOption) *Proxy {
+	cfg := ProxyConfig{
+		ContextDialer:  &net.Dialer{},
+		MessageHandler: func(*ProxiedMessage) {},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return &Proxy{
+		upstream: upstream,
+		cfg:      cfg,
+	}
+}
+
+// ListenAndServe listens on the TCP address `addr` relaying the connections
+// made to it until `ctx` is done.
+//
+func (p *Proxy) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	return p.Serve(ctx, listener)
+}
+
+// Serve accepts connections from `listener` until `ctx` is done, at which
+// point the listener and all of the connections being relayed are closed.
+//
+func (p *Proxy) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.serve(ctx, conn)
+		}()
+	}
+}
+
+func (p *Proxy) serve(ctx context.Context, downstream net.Conn) {
+	id := atomic.AddUint64(&p.connections, 1)
+
+	upstream, err := p.cfg.ContextDialer.DialContext(ctx, "tcp", p.upstream)
+	if err != nil {
+		downstream.Close()
+
+		p.handle(&ProxiedMessage{
+			Connection: id,
+			DecodedMessage: &DecodedMessage{
+				Source:      downstream.RemoteAddr().String(),
+				Destination: p.upstream,
+				Error:       fmt.Sprintf("dial upstream: %v", err),
+			},
+		})
+
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		downstream.Close()
+		upstream.Close()
+	}()
+
+	var wg sync.WaitGroup
+
+	relay := func(direction Direction, src, dst net.Conn, srcAddr, dstAddr string) {
+		defer wg.Done()
+		defer cancel()
+
+		p.relay(id, direction, src, dst, srcAddr, dstAddr)
+	}
+
+	wg.Add(2)
+	go relay(DirectionUpstream, downstream, upstream,
+		downstream.RemoteAddr().String(), p.upstream)
+	go relay(DirectionDownstream, upstream, downstream,
+		p.upstream, downstream.RemoteAddr().String())
+	wg.Wait()
+}
+
+// relay copies everything from `src` to `dst` as it's read, decoding the
+// messages it carries. In case the traffic can't be decoded, it's still
+// relayed, but no longer decoded.
+//
+// Dummy packets and fragments are reported on their own as they go
+// through, and the message that fragments carry once it's reassembled.
+//
+func (p *Proxy) relay(id uint64, direction Direction, src, dst net.Conn, srcAddr, dstAddr string) {
+	var (
+		reader = &countingReader{r: io.TeeReader(src, dst)}
+		frames = NewFrameReader(reader)
+		before int
+	)
+
+	frames.PacketHandler = func(header *Header) {
+		msg := DecodeMessage(header, nil)
+
+		now := time.Now()
+		msg.Time = &now
+		msg.Source = srcAddr
+		msg.Destination = dstAddr
+
+		p.handle(&ProxiedMessage{
+			Connection:     id,
+			Direction:      direction,
+			Size:           LevinHeaderSizeBytes + int(header.Length),
+			DecodedMessage: msg,
+		})
+
+		// dummies aren't part of the message that follows them, unlike
+		// fragments.
+		//
+		if header.Flags&LevinPacketBegin != 0 && header.Flags&LevinPacketEnd != 0 {
+			before = reader.n
+		}
+	}
+
+	for {
+		before = reader.n
+
+		header, payload, err := frames.ReadMessage()
+		if err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+				return
+			}
+
+			p.handle(&ProxiedMessage{
+				Connection: id,
+				Direction:  direction,
+				Size:       reader.n - before,
+				DecodedMessage: &DecodedMessage{
+					Source:      srcAddr,
+					Destination: dstAddr,
+					Error:       fmt.Sprintf("read message: %v", err),
+				},
+			})
+
+			_, _ = io.Copy(dst, src)
+			return
+		}
+
+		msg := DecodeMessage(header, payload)
+
+		now := time.Now()
+		msg.Time = &now
+		msg.Source = srcAddr
+		msg.Destination = dstAddr
+
+		p.handle(&ProxiedMessage{
+			Connection:     id,
+			Direction:      direction,
+			Size:           reader.n - before,
+			DecodedMessage: msg,
+		})
+	}
+}
+
+func (p *Proxy) handle(m *ProxiedMessage) {
+	p.handlerMu.Lock()
+	defer p.handlerMu.Unlock()
+
+	p.cfg.MessageHandler(m)
+}
@@ -0,0 +1,164 @@
+package levin_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+func TestProxy(t *testing.T) {
+	spec.Run(t, "Proxy", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx      context.Context
+			cancel   context.CancelFunc
+			messages chan *levin.ProxiedMessage
+			served   chan error
+		)
+
+		listen := func() net.Listener {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			return listener
+		}
+
+		proxy := func(upstream string) string {
+			listener := listen()
+
+			p := levin.NewProxy(upstream, levin.WithMessageHandler(func(m *levin.ProxiedMessage) {
+				messages <- m
+			}))
+
+			go func() {
+				served <- p.Serve(ctx, listener)
+			}()
+
+			return listener.Addr().String()
+		}
+
+		next := func() *levin.ProxiedMessage {
+			select {
+			case m := <-messages:
+				return m
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for message")
+			}
+
+			return nil
+		}
+
+		it.Before(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			messages = make(chan *levin.ProxiedMessage, 100)
+			served = make(chan error, 1)
+		})
+
+		it.After(func() {
+			cancel()
+			assert.NoError(t, <-served)
+		})
+
+		it("relays traffic, decoding the messages in both directions", func() {
+			listener := listen()
+
+			server, err := levin.NewServer(levin.WithNodeData(levin.NodeData{
+				NetworkID: levin.MainnetNetworkId,
+				PeerID:    0xdeadbeef,
+			}))
+			require.NoError(t, err)
+
+			go func() {
+				_ = server.Serve(ctx, listener)
+			}()
+
+			client, err := levin.NewClient(ctx, proxy(listener.Addr().String()))
+			require.NoError(t, err)
+			defer client.Close()
+
+			node, err := client.Handshake(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(0xdeadbeef), node.Id)
+
+			request := next()
+			assert.Equal(t, levin.DirectionUpstream, request.Direction)
+			assert.Equal(t, listener.Addr().String(), request.Destination)
+			assert.Equal(t, "COMMAND_HANDSHAKE", request.Header.CommandName)
+			assert.Equal(t, int(request.Header.Length)+levin.LevinHeaderSizeBytes, request.Size)
+			assert.Contains(t, request.Storage, "node_data")
+
+			response := next()
+			assert.Equal(t, levin.DirectionDownstream, response.Direction)
+			assert.Equal(t, request.Connection, response.Connection)
+			assert.Equal(t, listener.Addr().String(), response.Source)
+			assert.Equal(t, "COMMAND_HANDSHAKE", response.Header.CommandName)
+			assert.Equal(t, int32(1), response.Header.ReturnCode)
+			assert.Equal(t, uint64(0xdeadbeef),
+				response.Storage["node_data"].(map[string]interface{})["peer_id"])
+		})
+
+		it("reports dummies and fragments as they go through", func() {
+			listener := listen()
+
+			go func() {
+				conn, err := listener.Accept()
+				if err != nil {
+					return
+				}
+				defer conn.Close()
+
+				_, _ = io.Copy(io.Discard, conn)
+			}()
+
+			conn, err := net.Dial("tcp", proxy(listener.Addr().String()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			writer := levin.NewFrameWriter(conn)
+			writer.FragmentSize = 80
+
+			require.NoError(t, writer.WriteDummy(48))
+			require.NoError(t, writer.WriteMessage(
+				levin.NewNotificationHeader(levin.NotifyNewTransactions, 0),
+				make([]byte, 50)))
+
+			dummy := next()
+			assert.Equal(t, "DUMMY", dummy.Header.CommandName)
+			assert.Equal(t, uint32(levin.LevinPacketBegin|levin.LevinPacketEnd), dummy.Header.Flags)
+			assert.Equal(t, 48, dummy.Size)
+
+			begin := next()
+			assert.Equal(t, "DUMMY", begin.Header.CommandName)
+			assert.Equal(t, uint32(levin.LevinPacketBegin), begin.Header.Flags)
+			assert.Equal(t, 80, begin.Size)
+
+			end := next()
+			assert.Equal(t, uint32(levin.LevinPacketEnd), end.Header.Flags)
+			assert.Equal(t, 80, end.Size)
+
+			m := next()
+			assert.Equal(t, "NOTIFY_NEW_TRANSACTIONS", m.Header.CommandName)
+			assert.Equal(t, 160, m.Size)
+		})
+
+		it("reports failures to reach the upstream peer", func() {
+			unreachable := listen()
+			unreachable.Close()
+
+			conn, err := net.Dial("tcp", proxy(unreachable.Addr().String()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			m := next()
+			assert.Contains(t, m.Error, "dial upstream")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -0,0 +1,207 @@
+package levin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+)
+
+// Server accepts connections from peers in the p2p network, acting as the
+// responder side of the handshake and answering their requests with what's
+// been configured for it: `node_data`, `payload_data` and the peer list.
+//
+type Server struct {
+	cfg ServerConfig
+
+	mu      sync.Mutex
+	clients map[*Client]struct{}
+}
+
+type ServerConfig struct {
+	// NodeData is what's announced to peers as `node_data` in handshake
+	// responses. Peer id is randomly generated if not set.
+	//
+	NodeData NodeData
+
+	// CoreSyncData is what's announced to peers as `payload_data` in
+	// handshake and timed sync responses.
+	//
+	CoreSyncData CoreSyncData
+
+	// Peers is the peer list served in handshake and timed sync
+	// responses.
+	//
+	Peers []*Peer
+
+	// ClientHandler is called (in its own goroutine) for every peer that
+	// connects, e.g., for registering notification handlers.
+	//
+	// Nothing is read from the peer until it returns, so that no
+	// notification is missed, thus, it must not wait on the peer (e.g.,
+	// by pinging it) - that should be done in another goroutine.
+	//
+	ClientHandler func(c *Client)
+}
+
+type ServerOption func(*ServerConfig)
+
+// WithNodeData sets what's announced to peers as `node_data`, w/ only the
+// fields set in `v` overriding the defaults (mainnet's network id, support
+// for fluffy blocks, and a random peer id).
+//
+func WithNodeData(v NodeData) func(*ServerConfig) {
+	return func(c *ServerConfig) {
+		if v.NetworkID != nil {
+			c.NodeData.NetworkID = v.NetworkID
+		}
+
+		if v.PeerID != 0 {
+			c.NodeData.PeerID = v.PeerID
+		}
+
+		if v.MyPort != 0 {
+			c.NodeData.MyPort = v.MyPort
+		}
+
+		if v.RPCPort != 0 {
+			c.NodeData.RPCPort = v.RPCPort
+		}
+
+		if v.RPCCreditsPerHash != 0 {
+			c.NodeData.RPCCreditsPerHash = v.RPCCreditsPerHash
+		}
+
+		if v.SupportFlags != 0 {
+			c.NodeData.SupportFlags = v.SupportFlags
+		}
+	}
+}
+
+func WithCoreSyncData(v CoreSyncData) func(*ServerConfig) {
+	return func(c *ServerConfig) {
+		c.CoreSyncData = v
+	}
+}
+
+func WithPeers(v []*Peer) func(*ServerConfig) {
+	return func(c *ServerConfig) {
+		c.Peers = v
+	}
+}
+
+func WithClientHandler(v func(c *Client)) func(*ServerConfig) {
+	return func(c *ServerConfig) {
+		c.ClientHandler = v
+	}
+}
+
+func NewServer(opts ...ServerOption) (*Server, error) {
+	cfg := ServerConfig{
+		NodeData: NodeData{
+			NetworkID:    MainnetNetworkId,
+			SupportFlags: SupportFlagFluffyBlocks,
+		},
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.NodeData.PeerID == 0 {
+		peerID, err := randomPeerID()
+		if err != nil {
+			return nil, fmt.Errorf("random peer id: %w", err)
+		}
+
+		cfg.NodeData.PeerID = peerID
+	}
+
+	return &Server{
+		cfg:     cfg,
+		clients: map[*Client]struct{}{},
+	}, nil
+}
+
+// ListenAndServe listens on the TCP address `addr` serving peers that
+// connect to it until `ctx` is done.
+//
+func (s *Server) ListenAndServe(ctx context.Context, addr string) error {
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	return s.Serve(ctx, listener)
+}
+
+// Serve accepts connections from `listener` until `ctx` is done, at which
+// point the listener and all of the connections accepted through it are
+// closed.
+//
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	defer s.closeClients()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("accept: %w", err)
+		}
+
+		s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	client := newClient(conn, localNode{
+		NodeData:         s.cfg.NodeData,
+		CoreSyncData:     s.cfg.CoreSyncData,
+		Peers:            s.cfg.Peers,
+		acceptHandshakes: true,
+	})
+
+	s.mu.Lock()
+	s.clients[client] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-client.Done()
+
+		s.mu.Lock()
+		delete(s.clients, client)
+		s.mu.Unlock()
+	}()
+
+	go func() {
+		if s.cfg.ClientHandler != nil {
+			s.cfg.ClientHandler(client)
+		}
+
+		client.start()
+	}()
+}
+
+func (s *Server) closeClients() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for client := range s.clients {
+		client.Close()
+	}
+}
@@ -0,0 +1,243 @@
+package levin_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+// nolint:funlen
+func TestServer(t *testing.T) {
+	spec.Run(t, "Server", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			addr   string
+			served chan error
+		)
+
+		serve := func(opts ...levin.ServerOption) {
+			server, err := levin.NewServer(opts...)
+			require.NoError(t, err)
+
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+
+			addr = listener.Addr().String()
+
+			go func() {
+				served <- server.Serve(ctx, listener)
+			}()
+		}
+
+		it.Before(func() {
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			served = make(chan error, 1)
+		})
+
+		it.After(func() {
+			cancel()
+			assert.NoError(t, <-served)
+		})
+
+		it("responds to handshakes w/ what's been configured", func() {
+			serve(
+				levin.WithNodeData(levin.NodeData{
+					NetworkID:         levin.MainnetNetworkId,
+					PeerID:            0xdeadbeef,
+					MyPort:            18080,
+					RPCPort:           18081,
+					RPCCreditsPerHash: 100,
+					SupportFlags:      levin.SupportFlagFluffyBlocks,
+				}),
+				levin.WithCoreSyncData(levin.CoreSyncData{
+					CurrentHeight:        2000000,
+					CumulativeDifficulty: 1 << 50,
+					TopID:                hash(0xaa),
+					TopVersion:           14,
+					PruningSeed:          0x181,
+				}),
+				levin.WithPeers([]*levin.Peer{
+					{
+						Address: levin.IPv4Address{
+							IP:   net.ParseIP("1.2.3.4"),
+							Port: 18080,
+						},
+						ID:          1,
+						PruningSeed: 0x181,
+						RPCPort:     18089,
+					},
+					{
+						Address: levin.IPv6Address{
+							IP:   net.ParseIP("2001:db8::1"),
+							Port: 18080,
+						},
+						ID:                2,
+						LastSeen:          1600000000,
+						RPCCreditsPerHash: 10,
+					},
+					{
+						Address: levin.TorAddress{
+							Host: "xmrabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz.onion",
+							Port: 18083,
+						},
+						ID: 3,
+					},
+					{
+						Address: levin.I2PAddress{
+							Host: "xmrabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstu.b32.i2p",
+							Port: 0,
+						},
+						ID: 4,
+					},
+				}),
+			)
+
+			client, err := levin.NewClient(ctx, addr)
+			require.NoError(t, err)
+			defer client.Close()
+
+			node, err := client.Handshake(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, uint64(0xdeadbeef), node.Id)
+			assert.Equal(t, uint16(18081), node.RPCPort)
+			assert.Equal(t, uint64(2000000), node.CurrentHeight)
+			assert.Equal(t, uint8(14), node.TopVersion)
+			require.Len(t, node.Peers, 4)
+
+			ipv4 := node.Peers["1.2.3.4:18080"]
+			require.NotNil(t, ipv4)
+			assert.Equal(t, levin.AddressTypeIPv4, ipv4.Address.Type())
+			assert.Equal(t, uint64(1), ipv4.ID)
+			assert.Equal(t, uint32(0x181), ipv4.PruningSeed)
+			assert.Equal(t, uint16(18089), ipv4.RPCPort)
+
+			ipv6 := node.Peers["[2001:db8::1]:18080"]
+			require.NotNil(t, ipv6)
+			assert.Equal(t, levin.AddressTypeIPv6, ipv6.Address.Type())
+			assert.Equal(t, int64(1600000000), ipv6.LastSeen)
+			assert.Equal(t, uint32(10), ipv6.RPCCreditsPerHash)
+
+			tor := node.Peers["xmrabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstuvwxyz.onion:18083"]
+			require.NotNil(t, tor)
+			assert.Equal(t, levin.ZoneTor, tor.Address.Zone())
+
+			i2p := node.Peers["xmrabcdefghijklmnopqrstuvwxyzabcdefghijklmnopqrstu.b32.i2p:0"]
+			require.NotNil(t, i2p)
+			assert.Equal(t, levin.ZoneI2P, i2p.Address.Zone())
+
+			assert.Equal(t, uint32(18080), node.NodeData.MyPort)
+			assert.Equal(t, uint32(100), node.NodeData.RPCCreditsPerHash)
+			assert.Equal(t, levin.SupportFlagFluffyBlocks, node.NodeData.SupportFlags)
+			assert.Equal(t, uint64(1<<50), node.CoreSyncData.CumulativeDifficulty)
+			assert.Equal(t, hash(0xaa), node.CoreSyncData.TopID)
+			assert.Equal(t, uint32(0x181), node.CoreSyncData.PruningSeed)
+			assert.Equal(t, levin.SupportFlagFluffyBlocks, node.SupportFlags)
+
+			assert.NoError(t, client.Ping(ctx))
+		})
+
+		it("drops peers from other networks", func() {
+			serve(levin.WithNodeData(levin.NodeData{
+				NetworkID: []byte("another network"),
+			}))
+
+			client, err := levin.NewClient(ctx, addr)
+			require.NoError(t, err)
+			defer client.Close()
+
+			_, err = client.Handshake(ctx)
+			assert.Error(t, err)
+		})
+
+		it("accepts peers from the same network", func() {
+			serve(levin.WithNodeData(levin.NodeData{
+				NetworkID: levin.StagenetNetworkId,
+			}))
+
+			client, err := levin.NewClient(ctx, addr,
+				levin.WithNetwork(monero.NetworkStagenet))
+			require.NoError(t, err)
+			defer client.Close()
+
+			_, err = client.Handshake(ctx)
+			assert.NoError(t, err)
+		})
+
+		it("keeps the defaults for node data not configured", func() {
+			serve(levin.WithNodeData(levin.NodeData{
+				PeerID: 0xdeadbeef,
+				MyPort: 18080,
+			}))
+
+			client, err := levin.NewClient(ctx, addr)
+			require.NoError(t, err)
+			defer client.Close()
+
+			node, err := client.Handshake(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, uint64(0xdeadbeef), node.Id)
+			assert.Equal(t, uint32(18080), node.NodeData.MyPort)
+			assert.Equal(t, levin.SupportFlagFluffyBlocks, node.SupportFlags)
+		})
+
+		it("lets the client handler register handlers before reading", func() {
+			received := make(chan levin.Notification, 1)
+			serve(levin.WithClientHandler(func(c *levin.Client) {
+				// give the peer the chance of notifying before the
+				// handler is registered.
+				//
+				time.Sleep(50 * time.Millisecond)
+
+				c.Handle(levin.NotifyNewTransactions, func(n levin.Notification) {
+					received <- n
+				})
+			}))
+
+			client, err := levin.NewClient(ctx, addr)
+			require.NoError(t, err)
+			defer client.Close()
+
+			_, err = client.Handshake(ctx)
+			require.NoError(t, err)
+
+			n := &levin.NewTransactions{Txs: [][]byte{{0x01}}, Padding: []byte{}}
+			require.NoError(t, client.Notify(ctx, n))
+
+			select {
+			case got := <-received:
+				assert.Equal(t, n.Txs, got.(*levin.NewTransactions).Txs)
+			case <-ctx.Done():
+				t.Fatal("notification not dispatched")
+			}
+		})
+
+		it("hands connected peers to the client handler", func() {
+			connected := make(chan *levin.Client, 1)
+			serve(levin.WithClientHandler(func(c *levin.Client) {
+				connected <- c
+			}))
+
+			client, err := levin.NewClient(ctx, addr)
+			require.NoError(t, err)
+			defer client.Close()
+
+			_, err = client.Handshake(ctx)
+			require.NoError(t, err)
+
+			inbound := <-connected
+			assert.NoError(t, inbound.Ping(ctx))
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
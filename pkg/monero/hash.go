@@ -0,0 +1,436 @@
+package monero
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// see https://github.com/monero-project/monero/blob/e45619e61e4831eea70a43fe6985f4d57ea02e9e/src/cryptonote_basic/cryptonote_format_utils.cpp
+// see https://github.com/monero-project/monero/blob/e45619e61e4831eea70a43fe6985f4d57ea02e9e/src/crypto/tree-hash.c
+
+// HashSize is the size of transaction and block hashes.
+//
+const HashSize = 32
+
+const (
+	txInGen       byte = 0xff
+	txInToKey     byte = 0x02
+	txOutToKey    byte = 0x02
+	txOutToTagged byte = 0x03
+
+	rctTypeNull            byte = 0
+	rctTypeFull            byte = 1
+	rctTypeSimple          byte = 2
+	rctTypeBulletproof     byte = 3
+	rctTypeBulletproof2    byte = 4
+	rctTypeCLSAG           byte = 5
+	rctTypeBulletproofPlus byte = 6
+)
+
+// ErrMalformedBlob indicates that a transaction or block blob couldn't be
+// parsed.
+//
+var ErrMalformedBlob = errors.New("malformed blob")
+
+// TransactionHash computes the hash (id) of the transaction `blob`.
+//
+// For version 1 transactions, that's the hash of the whole blob, while for
+// version 2 (RingCT) ones, it's the hash of the concatenation of the hashes
+// of the prefix, the base of the signatures, and their prunable part.
+//
+func TransactionHash(blob []byte) ([]byte, error) {
+	tx, err := parseTransaction(blob, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx.hash, nil
+}
+
+// BlockHashingBlob extracts from the block `blob` what gets hashed for
+// computing its id and its proof of work: the header, the merkle root of its
+// transactions (miner transaction included), and the number of them.
+//
+func BlockHashingBlob(blob []byte) ([]byte, error) {
+	r := &blobReader{b: blob}
+
+	// major version, minor version, timestamp.
+	//
+	for _, field := range []string{"major version", "minor version", "timestamp"} {
+		if _, err := r.varint(); err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	if _, err := r.bytes(HashSize + 4); err != nil {
+		return nil, fmt.Errorf("prev id and nonce: %w", err)
+	}
+
+	header := blob[:r.idx]
+
+	minerTx, err := parseTransaction(blob[r.idx:], true)
+	if err != nil {
+		return nil, fmt.Errorf("miner tx: %w", err)
+	}
+
+	r.idx += minerTx.size
+
+	count, err := r.varint()
+	if err != nil {
+		return nil, fmt.Errorf("tx hashes count: %w", err)
+	}
+
+	if count > uint64(r.remaining()/HashSize) {
+		return nil, fmt.Errorf("%d tx hashes: %w", count, ErrMalformedBlob)
+	}
+
+	hashes := [][]byte{minerTx.hash}
+	for i := uint64(0); i < count; i++ {
+		hash, _ := r.bytes(HashSize)
+		hashes = append(hashes, hash)
+	}
+
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%d trailing bytes: %w", r.remaining(), ErrMalformedBlob)
+	}
+
+	hashingBlob := append([]byte{}, header...)
+	hashingBlob = append(hashingBlob, TreeHash(hashes)...)
+	hashingBlob = append(hashingBlob, uvarint(uint64(len(hashes)))...)
+
+	return hashingBlob, nil
+}
+
+// BlockNonceOffset finds where the nonce of the block `blob` is, which, being
+// part of the header, is the same whether `blob` is the whole block or its
+// hashing blob.
+//
+func BlockNonceOffset(blob []byte) (int, error) {
+	r := &blobReader{b: blob}
+
+	for _, field := range []string{"major version", "minor version", "timestamp"} {
+		if _, err := r.varint(); err != nil {
+			return 0, fmt.Errorf("%s: %w", field, err)
+		}
+	}
+
+	if _, err := r.bytes(HashSize); err != nil {
+		return 0, fmt.Errorf("prev id: %w", err)
+	}
+
+	offset := r.idx
+
+	if _, err := r.bytes(4); err != nil {
+		return 0, fmt.Errorf("nonce: %w", err)
+	}
+
+	return offset, nil
+}
+
+// BlockHash computes the hash (id) of the block `blob`.
+//
+// ps.: the id of mainnet block 202612, which monerod hardcodes due to a bug
+// in the merkle root computation of the time, is not special cased.
+//
+func BlockHash(blob []byte) ([]byte, error) {
+	hashingBlob, err := BlockHashingBlob(blob)
+	if err != nil {
+		return nil, fmt.Errorf("block hashing blob: %w", err)
+	}
+
+	return keccak256(uvarint(uint64(len(hashingBlob))), hashingBlob), nil
+}
+
+// TreeHash computes the merkle root of `hashes` the way monero does for the
+// transactions of a block.
+//
+func TreeHash(hashes [][]byte) []byte {
+	switch len(hashes) {
+	case 0:
+		return make([]byte, HashSize)
+	case 1:
+		return append([]byte{}, hashes[0]...)
+	case 2:
+		return keccak256(hashes[0], hashes[1])
+	}
+
+	cnt := 1
+	for cnt*2 < len(hashes) {
+		cnt *= 2
+	}
+
+	ints := make([][]byte, cnt)
+	copy(ints, hashes[:2*cnt-len(hashes)])
+
+	for i, j := 2*cnt-len(hashes), 2*cnt-len(hashes); j < cnt; i, j = i+2, j+1 {
+		ints[j] = keccak256(hashes[i], hashes[i+1])
+	}
+
+	for cnt > 2 {
+		cnt /= 2
+
+		for i, j := 0, 0; j < cnt; i, j = i+2, j+1 {
+			ints[j] = keccak256(ints[i], ints[i+1])
+		}
+	}
+
+	return keccak256(ints[0], ints[1])
+}
+
+type parsedTransaction struct {
+	hash []byte
+
+	// size is the number of bytes that the transaction takes.
+	//
+	size int
+}
+
+// parseTransaction parses the transaction at the beginning of `blob` just
+// enough for computing its hash.
+//
+// Only miner transactions (whose signatures take no space) can be embedded
+// in something else (i.e., a block), as the size of the prunable part of the
+// signatures of any other transaction is not figured out.
+//
+func parseTransaction(blob []byte, embedded bool) (*parsedTransaction, error) {
+	r := &blobReader{b: blob}
+
+	version, err := r.varint()
+	if err != nil {
+		return nil, fmt.Errorf("version: %w", err)
+	}
+
+	if _, err := r.varint(); err != nil {
+		return nil, fmt.Errorf("unlock time: %w", err)
+	}
+
+	inputs, err := r.varint()
+	if err != nil {
+		return nil, fmt.Errorf("inputs count: %w", err)
+	}
+
+	coinbase := true
+
+	for i := uint64(0); i < inputs; i++ {
+		isGen, err := r.input()
+		if err != nil {
+			return nil, fmt.Errorf("input %d: %w", i, err)
+		}
+
+		coinbase = coinbase && isGen
+	}
+
+	outputs, err := r.varint()
+	if err != nil {
+		return nil, fmt.Errorf("outputs count: %w", err)
+	}
+
+	for i := uint64(0); i < outputs; i++ {
+		if err := r.output(); err != nil {
+			return nil, fmt.Errorf("output %d: %w", i, err)
+		}
+	}
+
+	extra, err := r.varint()
+	if err != nil {
+		return nil, fmt.Errorf("extra length: %w", err)
+	}
+
+	if extra > uint64(r.remaining()) {
+		return nil, fmt.Errorf("extra of %d bytes: %w", extra, ErrMalformedBlob)
+	}
+
+	if _, err := r.bytes(int(extra)); err != nil {
+		return nil, fmt.Errorf("extra: %w", err)
+	}
+
+	prefixEnd := r.idx
+
+	if embedded && (!coinbase || inputs == 0) {
+		return nil, fmt.Errorf("embedded non-miner tx: %w", ErrMalformedBlob)
+	}
+
+	if version == 1 {
+		if !embedded {
+			r.idx = len(blob)
+		}
+
+		return &parsedTransaction{
+			hash: keccak256(blob[:r.idx]),
+			size: r.idx,
+		}, nil
+	}
+
+	rctType, err := r.bytes(1)
+	if err != nil {
+		return nil, fmt.Errorf("rct type: %w", err)
+	}
+
+	prunableHash := make([]byte, HashSize)
+
+	if rctType[0] != rctTypeNull {
+		if embedded {
+			return nil, fmt.Errorf("embedded tx w/ rct type %d: %w",
+				rctType[0], ErrMalformedBlob)
+		}
+
+		// since bulletproofs2, only the amount is in ecdhInfo, w/ the
+		// mask being derived from the shared secret.
+		//
+		var ecdhInfoSize int
+		switch rctType[0] {
+		case rctTypeFull, rctTypeSimple, rctTypeBulletproof:
+			ecdhInfoSize = 64
+		case rctTypeBulletproof2, rctTypeCLSAG, rctTypeBulletproofPlus:
+			ecdhInfoSize = 8
+		default:
+			return nil, fmt.Errorf("rct type %d: %w", rctType[0], ErrMalformedBlob)
+		}
+
+		if _, err := r.varint(); err != nil {
+			return nil, fmt.Errorf("fee: %w", err)
+		}
+
+		// only simple signatures carry the pseudo outputs in the base,
+		// later types having moved them to the prunable part.
+		//
+		if rctType[0] == rctTypeSimple {
+			if inputs > uint64(r.remaining()/HashSize) {
+				return nil, fmt.Errorf("%d inputs: %w", inputs, ErrMalformedBlob)
+			}
+
+			if _, err := r.bytes(int(inputs) * HashSize); err != nil {
+				return nil, fmt.Errorf("pseudo outs: %w", err)
+			}
+		}
+
+		if outputs > uint64(r.remaining()) {
+			return nil, fmt.Errorf("%d outputs: %w", outputs, ErrMalformedBlob)
+		}
+
+		if _, err := r.bytes(int(outputs) * (ecdhInfoSize + HashSize)); err != nil {
+			return nil, fmt.Errorf("ecdh info and output commitments: %w", err)
+		}
+	}
+
+	baseEnd := r.idx
+
+	if !embedded && rctType[0] != rctTypeNull {
+		prunableHash = keccak256(blob[baseEnd:])
+		r.idx = len(blob)
+	}
+
+	return &parsedTransaction{
+		hash: keccak256(
+			keccak256(blob[:prefixEnd]),
+			keccak256(blob[prefixEnd:baseEnd]),
+			prunableHash,
+		),
+		size: r.idx,
+	}, nil
+}
+
+type blobReader struct {
+	b   []byte
+	idx int
+}
+
+func (r *blobReader) remaining() int {
+	return len(r.b) - r.idx
+}
+
+func (r *blobReader) bytes(n int) ([]byte, error) {
+	if n < 0 || n > r.remaining() {
+		return nil, fmt.Errorf("%d bytes w/ %d remaining: %w",
+			n, r.remaining(), ErrMalformedBlob)
+	}
+
+	b := r.b[r.idx : r.idx+n]
+	r.idx += n
+
+	return b, nil
+}
+
+func (r *blobReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.b[r.idx:])
+	if n <= 0 {
+		return 0, fmt.Errorf("varint: %w", ErrMalformedBlob)
+	}
+
+	r.idx += n
+
+	return v, nil
+}
+
+// input reads a transaction input, indicating whether it's a coinbase one.
+//
+func (r *blobReader) input() (bool, error) {
+	tag, err := r.bytes(1)
+	if err != nil {
+		return false, err
+	}
+
+	switch tag[0] {
+	case txInGen:
+		if _, err := r.varint(); err != nil {
+			return false, fmt.Errorf("height: %w", err)
+		}
+
+		return true, nil
+	case txInToKey:
+		if _, err := r.varint(); err != nil {
+			return false, fmt.Errorf("amount: %w", err)
+		}
+
+		offsets, err := r.varint()
+		if err != nil {
+			return false, fmt.Errorf("key offsets count: %w", err)
+		}
+
+		for i := uint64(0); i < offsets; i++ {
+			if _, err := r.varint(); err != nil {
+				return false, fmt.Errorf("key offset %d: %w", i, err)
+			}
+		}
+
+		if _, err := r.bytes(HashSize); err != nil {
+			return false, fmt.Errorf("key image: %w", err)
+		}
+
+		return false, nil
+	}
+
+	return false, fmt.Errorf("input type %x: %w", tag[0], ErrMalformedBlob)
+}
+
+func (r *blobReader) output() error {
+	if _, err := r.varint(); err != nil {
+		return fmt.Errorf("amount: %w", err)
+	}
+
+	tag, err := r.bytes(1)
+	if err != nil {
+		return err
+	}
+
+	size := HashSize
+	switch tag[0] {
+	case txOutToKey:
+	case txOutToTagged:
+		size++ // view tag
+	default:
+		return fmt.Errorf("output type %x: %w", tag[0], ErrMalformedBlob)
+	}
+
+	if _, err := r.bytes(size); err != nil {
+		return fmt.Errorf("key: %w", err)
+	}
+
+	return nil
+}
+
+func uvarint(v uint64) []byte {
+	b := make([]byte, binary.MaxVarintLen64)
+	return b[:binary.PutUvarint(b, v)]
+}
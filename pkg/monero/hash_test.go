@@ -0,0 +1,231 @@
+package monero_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+// genesisBlock builds the blob of the genesis block w/ miner transaction
+// `tx` and nonce `nonce` (see `generate_genesis_block`).
+//
+func genesisBlock(t *testing.T, tx string, nonce []byte) []byte {
+	txBlob, err := hex.DecodeString(tx)
+	require.NoError(t, err)
+
+	blob := []byte{
+		0x01, // major version
+		0x00, // minor version
+		0x00, // timestamp
+	}
+	blob = append(blob, make([]byte, monero.HashSize)...) // prev id
+	blob = append(blob, nonce...)
+	blob = append(blob, txBlob...)
+	blob = append(blob, 0x00) // tx hashes
+
+	return blob
+}
+
+// minerTx1302238 is the miner transaction of mainnet block 1302238.
+//
+const minerTx1302238 = "029abe4f01ffdebd4f01a3caca99eaea01021804724f0b0938d83473fcb7fbb93a2991ec98139020b2fe8e8dcaf65888d3dd2b0141d60c73bd6cfd6eddd30039279aefba252747167e5e77ad1fd373916d2a273f020800000049259da0dd00"
+
+// clsagTx and bulletproofPlusTx are made up transactions w/ signatures of
+// types CLSAG and BulletproofPlus (the latter also w/ view tagged outputs),
+// one input, two outputs and garbage as the prunable part.
+//
+const (
+	clsagTx           = "020001020002e80719202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f020002a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a00002b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b02101cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc058080d30ed0d0d0d0d0d0d0d0d1d1d1d1d1d1d1d1e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0"
+	bulletproofPlusTx = "020001020002e80719202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f020003a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a0a10003b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b0b12101cccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccccc068080d30ed0d0d0d0d0d0d0d0d1d1d1d1d1d1d1d1e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e0e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1e1f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0f0"
+)
+
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.TrimSpace(s))
+	require.NoError(t, err)
+
+	return b
+}
+
+func testdata(t *testing.T, name string) []byte {
+	b, err := os.ReadFile("testdata/" + name)
+	require.NoError(t, err)
+
+	return decodeHex(t, string(b))
+}
+
+func TestBlockHash(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		tx    string
+		nonce []byte
+		hash  string
+	}{
+		{
+			name:  "mainnet genesis",
+			tx:    "013c01ff0001ffffffffffff03029b2e4c0281c0b02e7c53291a94d1d0cbff8883f8024f5142ee494ffbbd08807121017767aafcde9be00dcfd098715ebcf7f410daebc582fda69d24a28e9d0bc890d1",
+			nonce: []byte{0x10, 0x27, 0x00, 0x00},
+			hash:  "418015bb9ae982a1975da7d79277c2705727a56894ba0fb246adaabb1f4632e3",
+		},
+		{
+			name:  "stagenet genesis",
+			tx:    "013c01ff0001ffffffffffff0302df5d56da0c7d643ddd1ce61901c7bdc5fb1738bfe39fbe69c28a3a7032729c0f2101168d0c4ca86fb55a4cf6a36d31431be1c53a3bd7411bb24e8832410289fa6f3b",
+			nonce: []byte{0x12, 0x27, 0x00, 0x00},
+			hash:  "76ee3cc98646292206cd3e86f74d88b4dcc1d937088645e9b0cbca84b7ce74eb",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := monero.BlockHash(genesisBlock(t, tc.tx, tc.nonce))
+			require.NoError(t, err)
+			assert.Equal(t, tc.hash, hex.EncodeToString(hash))
+		})
+	}
+
+	t.Run("w/ transactions", func(t *testing.T) {
+		// made up header w/ transactions from mainnet block 1302238.
+		//
+		blob := []byte{
+			0x05,                         // major version
+			0x05,                         // minor version
+			0x80, 0xba, 0xb8, 0xc7, 0x05, // timestamp
+		}
+		blob = append(blob, bytes.Repeat([]byte{0x77}, monero.HashSize)...) // prev id
+		blob = append(blob, 0x01, 0x02, 0x03, 0x04)                         // nonce
+		blob = append(blob, decodeHex(t, minerTx1302238)...)
+		blob = append(blob, 0x02) // tx hashes
+		blob = append(blob, decodeHex(t, "be9d2cf9b473dbbb2c59ffb07b5d812516f94d64121d87ad61956386a4bc3843")...)
+		blob = append(blob, decodeHex(t, "7197cbfd111e8c2174d8833b3a87c723dc8b394abc1faa9175bd73ff5f4d16b5")...)
+
+		hash, err := monero.BlockHash(blob)
+		require.NoError(t, err)
+		assert.Equal(t, "cc2fc4014b6d24da461980ae4e757092cef6860474f5e94149684e66ce2fd50b", hex.EncodeToString(hash))
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		blob := genesisBlock(t,
+			"013c01ff0001ffffffffffff03029b2e4c0281c0b02e7c53291a94d1d0cbff8883f8024f5142ee494ffbbd08807121017767aafcde9be00dcfd098715ebcf7f410daebc582fda69d24a28e9d0bc890d1",
+			[]byte{0x10, 0x27, 0x00, 0x00})
+
+		for _, b := range [][]byte{blob[:10], blob[:len(blob)-1], append(blob, 0x00)} {
+			_, err := monero.BlockHash(b)
+			assert.ErrorIs(t, err, monero.ErrMalformedBlob)
+		}
+	})
+}
+
+func TestBlockNonceOffset(t *testing.T) {
+	blob := genesisBlock(t,
+		"013c01ff0001ffffffffffff03029b2e4c0281c0b02e7c53291a94d1d0cbff8883f8024f5142ee494ffbbd08807121017767aafcde9be00dcfd098715ebcf7f410daebc582fda69d24a28e9d0bc890d1",
+		[]byte{0x10, 0x27, 0x00, 0x00})
+
+	offset, err := monero.BlockNonceOffset(blob)
+	require.NoError(t, err)
+	assert.Equal(t, 35, offset)
+	assert.Equal(t, []byte{0x10, 0x27, 0x00, 0x00}, blob[offset:offset+4])
+
+	// multi-byte timestamp.
+	//
+	blob = append([]byte{0x0e, 0x0e, 0x80, 0x80, 0x01}, blob[3:]...)
+
+	offset, err = monero.BlockNonceOffset(blob)
+	require.NoError(t, err)
+	assert.Equal(t, 37, offset)
+
+	_, err = monero.BlockNonceOffset(blob[:40])
+	assert.ErrorIs(t, err, monero.ErrMalformedBlob)
+}
+
+func TestTransactionHash(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		blob []byte
+		hash string
+	}{
+		{
+			name: "v1",
+			blob: decodeHex(t, "013c01ff0001ffffffffffff03029b2e4c0281c0b02e7c53291a94d1d0cbff8883f8024f5142ee494ffbbd08807121017767aafcde9be00dcfd098715ebcf7f410daebc582fda69d24a28e9d0bc890d1"),
+			hash: "c88ce9783b4f11190d7b9c17a69c1c52200f9faaee8e98dd07e6811175177139",
+		},
+		{
+			name: "v2 miner",
+			blob: decodeHex(t, minerTx1302238),
+			hash: "be30ee0ac38d83c86d84326c64b13eea5b40897a321004d17e589241d49199f7",
+		},
+		{
+			name: "rct simple",
+			blob: testdata(t, "rct-simple-tx.hex"), // from mainnet block 1302238
+			hash: "be9d2cf9b473dbbb2c59ffb07b5d812516f94d64121d87ad61956386a4bc3843",
+		},
+		{
+			name: "rct clsag",
+			blob: decodeHex(t, clsagTx),
+			hash: "571a30c4d0940ba86a31511a8d95c9d1fd9a2c8ac640fa380d1bab5d464c7483",
+		},
+		{
+			name: "rct bulletproof plus",
+			blob: decodeHex(t, bulletproofPlusTx),
+			hash: "380b1be8547d5d2a40834c2efa0923f64c2e1ba6341fc34044b0ed0a3232f3ac",
+		},
+	} {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			hash, err := monero.TransactionHash(tc.blob)
+			require.NoError(t, err)
+			assert.Equal(t, tc.hash, hex.EncodeToString(hash))
+		})
+	}
+
+	t.Run("unknown rct type", func(t *testing.T) {
+		blob := decodeHex(t, clsagTx)
+		blob[bytes.Index(blob, []byte{0x05, 0x80, 0x80, 0xd3, 0x0e})] = 0x07
+
+		_, err := monero.TransactionHash(blob)
+		assert.ErrorIs(t, err, monero.ErrMalformedBlob)
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		for _, b := range [][]byte{
+			{},
+			{0x02, 0x00, 0x01},
+			{0x02, 0x00, 0x01, 0x05},
+		} {
+			_, err := monero.TransactionHash(b)
+			assert.ErrorIs(t, err, monero.ErrMalformedBlob)
+		}
+	})
+}
+
+func TestTreeHash(t *testing.T) {
+	hashes := [][]byte{}
+	for i := 0; i < 9; i++ {
+		hash := make([]byte, monero.HashSize)
+		hash[0] = byte(i + 1)
+
+		hashes = append(hashes, hash)
+	}
+
+	for _, tc := range []struct {
+		count int
+		root  string
+	}{
+		{1, "0100000000000000000000000000000000000000000000000000000000000000"},
+		{2, "4d4453a7d68209f18749bd417e8ede31a7869bee89327e0fb663b0e9129e4a23"},
+		{3, "c380e4b616dcae9f1af45652ae329817a2e6177ef9c2fc4df8edd7aa1d0520a5"},
+		{4, "b7427f9168772043b1b25f80c3c6ba6f786ee3b3c3cc6f7721d40e1f57e263e1"},
+		{5, "b5918af559eddba9b26c26458b47e85cd27c629935946009d2bc72e1008a74b9"},
+		{8, "7f6c0357072ce1c43f9245e86b8d6b7f2deb8f7d3c68c72937fdc46a9eaa1c17"},
+		{9, "f75bcf4a63ca9a845ce40db8a880cb558eba09935732353867d93900c8bbe0fa"},
+	} {
+		assert.Equal(t, tc.root, hex.EncodeToString(monero.TreeHash(hashes[:tc.count])),
+			"%d hashes", tc.count)
+	}
+}
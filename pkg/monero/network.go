@@ -27,3 +27,25 @@ func (n Network) PublicAddressBase58Prefix() []byte {
 
 	panic(fmt.Errorf("'%s' is not a valid netowrk", n))
 }
+
+// Networks are all the known networks.
+//
+var Networks = []Network{
+	NetworkMainnet,
+	NetworkTestnet,
+	NetworkStagenet,
+	NetworkFakechain,
+}
+
+// ParseNetwork parses the name of a network, failing if it's not one of the
+// known ones.
+//
+func ParseNetwork(name string) (Network, error) {
+	for _, network := range Networks {
+		if string(network) == name {
+			return network, nil
+		}
+	}
+
+	return NetworkFakechain, fmt.Errorf("unknown network %s", name)
+}
@@ -8,8 +8,11 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	mhttp "github.com/cirocosta/go-monero/pkg/http"
+	"github.com/cirocosta/go-monero/pkg/levin"
 )
 
 const (
@@ -24,6 +27,15 @@ const (
 	versionJSONRPC = "2.0"
 )
 
+// binaryStrings are the methods and endpoints (w/out the leading `/`) whose
+// responses carry binary blobs as strings, which epee doesn't escape the way
+// JSON requires (see `epeeJSON`).
+//
+var binaryStrings = map[string]bool{
+	"get_txpool_backlog":      true,
+	"get_output_distribution": true,
+}
+
 // Client is a wrapper over a plain HTTP client providing methods that
 // correspond to all RPC invocations to a `monerod` daemon, including
 // restricted and non-restricted ones.
@@ -41,13 +53,21 @@ type Client struct {
 	// endpoints.
 	//
 	address *url.URL
+
+	// retryPolicy, if set, dictates how failed requests are retried.
+	//
+	// To set one, make use of `WithRetry` when instantiating the client via
+	// the `NewClient` constructor.
+	//
+	retryPolicy *RetryPolicy
 }
 
 // clientOptions is a set of options that can be overridden to tweak the
 // client's behavior.
 //
 type clientOptions struct {
-	HTTPClient *http.Client
+	HTTPClient  *http.Client
+	RetryPolicy *RetryPolicy
 }
 
 // ClientOption defines a functional option for overriding optional client
@@ -91,21 +111,25 @@ func NewClient(address string, opts ...ClientOption) (*Client, error) {
 	}
 
 	return &Client{
-		address: parsedAddress,
-		http:    options.HTTPClient,
+		address:     parsedAddress,
+		http:        options.HTTPClient,
+		retryPolicy: options.RetryPolicy,
 	}, nil
 }
 
+// Address is the address of the monerod instance that the client talks to.
+//
+func (c *Client) Address() string {
+	return c.address.String()
+}
+
 // ResponseEnvelope wraps all responses from the RPC server.
 //
 type ResponseEnvelope struct {
 	ID      string      `json:"id"`
 	JSONRPC string      `json:"jsonrpc"`
 	Result  interface{} `json:"result,omitempty"`
-	Error   struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-	} `json:"error,omitempty"`
+	Error   Error       `json:"error,omitempty"`
 }
 
 // RequestEnvelope wraps all requests made to the RPC server.
@@ -120,6 +144,12 @@ type RequestEnvelope struct {
 // Request makes requests to any endpoints, not assuming any particular format.
 //
 func (c *Client) RawRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
+	return c.retry(ctx, endpoint, func() error {
+		return c.rawRequest(ctx, endpoint, params, response)
+	})
+}
+
+func (c *Client) rawRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
 	address := *c.address
 	address.Path = endpoint
 
@@ -141,18 +171,91 @@ func (c *Client) RawRequest(ctx context.Context, endpoint string, params interfa
 
 	req.Header.Add("Content-Type", "application/json")
 
-	if err := c.submitRequest(req, response); err != nil {
+	var result json.RawMessage
+
+	if err := c.submitRequest(req, jsonDecoder(endpoint, &result)); err != nil {
 		return fmt.Errorf("submit request: %w", err)
 	}
 
+	if err := decodeResult(result, response); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+
 	return nil
 }
 
+// BinaryRequest makes requests to the endpoints that take and return epee's
+// portable storage binary format (those ending in `.bin`), encoding `params`
+// and decoding the response into `response` with `pkg/levin`'s codec.
+//
+// A nil `params` is sent as an empty portable storage object.
+//
+func (c *Client) BinaryRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
+	return c.retry(ctx, endpoint, func() error {
+		return c.binaryRequest(ctx, endpoint, params, response)
+	})
+}
+
+func (c *Client) binaryRequest(ctx context.Context, endpoint string, params interface{}, response interface{}) error {
+	address := *c.address
+	address.Path = endpoint
+
+	if params == nil {
+		params = struct{}{}
+	}
+
+	b, err := levin.Marshal(params)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", address.String(), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", "application/octet-stream")
+
+	status := struct {
+		Status string `epee:"status"`
+	}{}
+
+	decode := func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read all: %w", err)
+		}
+
+		ps, err := levin.NewPortableStorageFromBytes(b)
+		if err != nil {
+			return fmt.Errorf("new portable storage from bytes: %w", err)
+		}
+
+		if err := levin.UnmarshalPortableStorage(ps, &status); err != nil {
+			return fmt.Errorf("unmarshal status: %w", err)
+		}
+
+		return levin.UnmarshalPortableStorage(ps, response)
+	}
+
+	if err := c.submitRequest(req, decode); err != nil {
+		return fmt.Errorf("submit request: %w", err)
+	}
+
+	return checkStatus(status.Status)
+}
+
 // JSONRPC issues a request for a particular method under the JSONRPC endpoint
 // with the proper envolope for its requests and unwrapping of results for
 // responses.
 //
 func (c *Client) JSONRPC(ctx context.Context, method string, params interface{}, response interface{}) error {
+	return c.retry(ctx, method, func() error {
+		return c.jsonRPC(ctx, method, params, response)
+	})
+}
+
+func (c *Client) jsonRPC(ctx context.Context, method string, params interface{}, response interface{}) error {
 	address := *c.address
 	address.Path = endpointJSONRPC
 
@@ -173,28 +276,215 @@ func (c *Client) JSONRPC(ctx context.Context, method string, params interface{},
 
 	req.Header.Add("Content-Type", "application/json")
 
+	var result json.RawMessage
+
 	rpcResponseBody := &ResponseEnvelope{
-		Result: response,
+		Result: &result,
 	}
 
-	if err := c.submitRequest(req, rpcResponseBody); err != nil {
+	if err := c.submitRequest(req, jsonDecoder(method, rpcResponseBody)); err != nil {
 		return fmt.Errorf("submit request: %w", err)
 	}
 
 	if rpcResponseBody.Error.Code != 0 || rpcResponseBody.Error.Message != "" {
-		return fmt.Errorf("rpc error: code=%d message=%s",
-			rpcResponseBody.Error.Code,
-			rpcResponseBody.Error.Message,
-		)
+		rpcErr := rpcResponseBody.Error
+		return &rpcErr
+	}
+
+	if err := decodeResult(result, response); err != nil {
+		return fmt.Errorf("decode result: %w", err)
 	}
 
 	return nil
 }
 
+// BatchCall is a single method call of a batch of JSONRPC calls (see
+// `JSONRPCBatch`).
+//
+type BatchCall struct {
+	// Method is the name of the method to call.
+	//
+	Method string
+
+	// Params are the parameters of the call.
+	//
+	Params interface{}
+
+	// Result is where the result of the call gets decoded to.
+	//
+	Result interface{}
+
+	// Error is set if the call failed (e.g., the method errored or its
+	// result couldn't be decoded).
+	//
+	Error error
+}
+
+// JSONRPCBatch issues, in a single request to the JSONRPC endpoint, all the
+// method calls in `calls`, decoding their results to `Result` (or setting
+// `Error` if they failed).
+//
+// The error returned is only about the request as a whole (e.g., the daemon
+// not being reachable) - that of each call is in its `Error`.
+//
+func (c *Client) JSONRPCBatch(ctx context.Context, calls []*BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	// a batch is only as idempotent as the least idempotent of its calls.
+	//
+	method := calls[0].Method
+	if c.retryPolicy != nil {
+		for _, call := range calls {
+			if !c.retryPolicy.Idempotent(call.Method) {
+				method = call.Method
+				break
+			}
+		}
+	}
+
+	return c.retry(ctx, method, func() error {
+		return c.jsonRPCBatch(ctx, calls)
+	})
+}
+
+func (c *Client) jsonRPCBatch(ctx context.Context, calls []*BatchCall) error {
+	address := *c.address
+	address.Path = endpointJSONRPC
+
+	var epee bool
+
+	envelopes := make([]*RequestEnvelope, len(calls))
+	for idx, call := range calls {
+		epee = epee || hasBinaryStrings(call.Method)
+
+		envelopes[idx] = &RequestEnvelope{
+			ID:      strconv.Itoa(idx),
+			JSONRPC: versionJSONRPC,
+			Method:  call.Method,
+			Params:  call.Params,
+		}
+	}
+
+	b, err := json.Marshal(envelopes)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", address.String(), bytes.NewReader(b))
+	if err != nil {
+		return fmt.Errorf("new req '%s': %w", address.String(), err)
+	}
+
+	req.Header.Add("Content-Type", "application/json")
+
+	responses := []*batchResponseEnvelope{}
+
+	decode := func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read all: %w", err)
+		}
+
+		if epee {
+			b = epeeJSON(b)
+		}
+
+		// servers not supporting batches reply w/ a single error.
+		//
+		single := &batchResponseEnvelope{}
+		if err := json.Unmarshal(b, single); err == nil {
+			if err := single.err(); err != nil {
+				return fmt.Errorf("batch not supported: %w", err)
+			}
+
+			return fmt.Errorf("expected array of responses, got object")
+		}
+
+		return json.Unmarshal(b, &responses)
+	}
+
+	if err := c.submitRequest(req, decode); err != nil {
+		return fmt.Errorf("submit request: %w", err)
+	}
+
+	answered := make([]bool, len(calls))
+
+	for _, response := range responses {
+		idx, err := response.index()
+		if err != nil || idx < 0 || idx >= len(calls) || answered[idx] {
+			continue
+		}
+
+		answered[idx] = true
+		call := calls[idx]
+
+		if err := response.err(); err != nil {
+			call.Error = err
+			continue
+		}
+
+		if err := decodeResult(response.Result, call.Result); err != nil {
+			call.Error = fmt.Errorf("decode result: %w", err)
+		}
+	}
+
+	for idx, call := range calls {
+		if !answered[idx] {
+			call.Error = fmt.Errorf("no response for call %d", idx)
+		}
+	}
+
+	return nil
+}
+
+// batchResponseEnvelope is a response to a call of a batch, whose result is
+// only decoded once it's known which call it's for.
+//
+type batchResponseEnvelope struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// index parses the id of the response back into the index of the call it's
+// for, accepting it either as a string (as sent) or a number.
+//
+func (e *batchResponseEnvelope) index() (int, error) {
+	id := string(e.ID)
+
+	if unquoted, err := strconv.Unquote(id); err == nil {
+		id = unquoted
+	}
+
+	return strconv.Atoi(id)
+}
+
+func (e *batchResponseEnvelope) err() error {
+	if e.Error == nil || (e.Error.Code == 0 && e.Error.Message == "") {
+		return nil
+	}
+
+	return e.Error
+}
+
+// retry calls `fn` (making the request for `method`) retrying according to
+// the retry policy, if any.
+//
+func (c *Client) retry(ctx context.Context, method string, fn func() error) error {
+	if c.retryPolicy == nil {
+		return fn()
+	}
+
+	return c.retryPolicy.do(ctx, method, fn)
+}
+
 // submitRequest performs any generic HTTP request to the monero node targeted
-// by this client making no assumptions about a particular endpoint.
+// by this client making no assumptions about a particular endpoint, handing
+// the body of successful responses to `decode`.
 //
-func (c *Client) submitRequest(req *http.Request, response interface{}) error {
+func (c *Client) submitRequest(req *http.Request, decode func(io.Reader) error) error {
 	resp, err := c.http.Do(req)
 	if err != nil {
 		return fmt.Errorf("do: %w", err)
@@ -203,12 +493,126 @@ func (c *Client) submitRequest(req *http.Request, response interface{}) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		return fmt.Errorf("non-2xx status code: %d", resp.StatusCode)
+		return newHTTPError(resp.StatusCode, resp.Body)
 	}
 
-	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+	if err := decode(resp.Body); err != nil {
 		return fmt.Errorf("decode: %w", err)
 	}
 
 	return nil
 }
+
+// jsonDecoder decodes JSON-encoded bodies of responses to `method` into
+// `response`.
+//
+func jsonDecoder(method string, response interface{}) func(io.Reader) error {
+	return func(r io.Reader) error {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("read all: %w", err)
+		}
+
+		if hasBinaryStrings(method) {
+			b = epeeJSON(b)
+		}
+
+		return json.NewDecoder(bytes.NewReader(b)).Decode(response)
+	}
+}
+
+// hasBinaryStrings tells whether the responses to `method` (or endpoint)
+// carry binary blobs as strings.
+//
+func hasBinaryStrings(method string) bool {
+	return binaryStrings[strings.TrimPrefix(method, "/")]
+}
+
+// decodeResult decodes the JSON-encoded result `b` into `response`, erroring
+// if the `status` it carries indicates that the request didn't go through.
+//
+func decodeResult(b json.RawMessage, response interface{}) error {
+	if len(b) == 0 {
+		return nil
+	}
+
+	if response != nil {
+		if err := json.Unmarshal(b, response); err != nil {
+			return fmt.Errorf("unmarshal: %w", err)
+		}
+	}
+
+	// not all results are objects (and not all objects have a status),
+	// in which case there's nothing to check.
+	//
+	status := struct {
+		Status string `json:"status"`
+	}{}
+
+	if err := json.Unmarshal(b, &status); err != nil {
+		return nil
+	}
+
+	return checkStatus(status.Status)
+}
+
+// epeeJSON makes the JSON produced by epee valid by escaping what it leaves
+// as is (or escapes in a way not allowed by JSON) in strings: control
+// characters (`\v` included).
+//
+// This only matters for fields carrying binary blobs as strings, like the
+// backlog of `get_txpool_backlog`, so it's only done for the methods in
+// `binaryStrings`.
+//
+func epeeJSON(b []byte) []byte {
+	var (
+		res      []byte
+		inString bool
+	)
+
+	for idx := 0; idx < len(b); idx++ {
+		c := b[idx]
+
+		switch {
+		case c == '"':
+			inString = !inString
+		case c == '\\' && inString && idx+1 < len(b):
+			idx++
+
+			if b[idx] == 'v' {
+				res = append(lazyCopy(res, b, idx-1), `\u000b`...)
+				continue
+			}
+
+			if res != nil {
+				res = append(res, c, b[idx])
+			}
+
+			continue
+		case c < 0x20 && inString:
+			res = append(lazyCopy(res, b, idx), fmt.Sprintf(`\u%04x`, c)...)
+			continue
+		}
+
+		if res != nil {
+			res = append(res, c)
+		}
+	}
+
+	if res == nil {
+		return b
+	}
+
+	return res
+}
+
+// lazyCopy returns `res` if it has already been created, or a copy of the
+// first `n` bytes of `b` otherwise.
+//
+func lazyCopy(res, b []byte, n int) []byte {
+	if res != nil {
+		return res
+	}
+
+	return append(make([]byte, 0, len(b)+16), b[:n]...)
+}
@@ -3,7 +3,9 @@ package rpc_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/cirocosta/go-monero/pkg/levin"
 	"github.com/cirocosta/go-monero/pkg/rpc"
 )
 
@@ -136,6 +139,42 @@ func TestClient(t *testing.T) {
 			assert.Equal(t, result, map[string]string{"foo": "bar"})
 		})
 
+		it("tolerates epee's escaping of control characters in binary strings", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "{\"result\": {\"foo\": \"a\\vb\tc\\\\vd\"}}")
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			result := map[string]string{}
+
+			err = client.JSONRPC(ctx, "get_txpool_backlog", nil, &result)
+			assert.NoError(t, err)
+
+			assert.Equal(t, map[string]string{"foo": "a\vb\tc\\vd"}, result)
+		})
+
+		it("keeps json strict for methods w/out binary strings", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "{\"result\": {\"foo\": \"a\\vb\"}}")
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			result := map[string]string{}
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, &result)
+			assert.Error(t, err)
+		})
+
 		it("fails if rpc errored", func() {
 			handler := func(w http.ResponseWriter, r *http.Request) {
 				fmt.Fprintln(w, `{"id":"id", "jsonrpc":"jsonrpc", "error": {"code": -1, "message":"foo"}}`)
@@ -155,5 +194,336 @@ func TestClient(t *testing.T) {
 			assert.Contains(t, err.Error(), "foo")
 			assert.Contains(t, err.Error(), "-1")
 		})
+
+		it("fails w/ typed error if rpc errored", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "error": {"code": -9, "message":"Core is busy"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, nil)
+			require.Error(t, err)
+
+			var rpcErr *rpc.Error
+			require.True(t, errors.As(err, &rpcErr))
+			assert.Equal(t, rpc.ErrorCodeCoreBusy, rpcErr.Code)
+			assert.Equal(t, "Core is busy", rpcErr.Message)
+
+			assert.True(t, errors.Is(err, rpc.ErrBusy))
+			assert.False(t, errors.Is(err, rpc.ErrRestricted))
+		})
+
+		it("fails if result status is not OK", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":"0", "jsonrpc":"2.0", "result": {"status": "PAYMENT REQUIRED"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			result := map[string]string{}
+
+			err = client.JSONRPC(ctx, "rpc-method", nil, &result)
+			require.Error(t, err)
+
+			var statusErr *rpc.StatusError
+			require.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, "PAYMENT REQUIRED", statusErr.Status)
+			assert.True(t, errors.Is(err, rpc.ErrPaymentRequired))
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+// nolint:funlen
+func TestRawRequest(t *testing.T) {
+	spec.Run(t, "RawRequest", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			client *rpc.Client
+			err    error
+		)
+
+		it("fails w/ http error carrying the body", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprintln(w, "  access denied  ")
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/foo", nil, &struct{}{})
+			require.Error(t, err)
+
+			var httpErr *rpc.HTTPError
+			require.True(t, errors.As(err, &httpErr))
+			assert.Equal(t, http.StatusForbidden, httpErr.StatusCode)
+			assert.Equal(t, "access denied", httpErr.Body)
+		})
+
+		it("fails if status is not OK, still decoding the result", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"status": "BUSY", "height": 10}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			result := &struct {
+				Height uint64 `json:"height"`
+			}{}
+
+			err = client.RawRequest(ctx, "/foo", nil, result)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, rpc.ErrBusy))
+			assert.Equal(t, uint64(10), result.Height)
+		})
+
+		it("succeeds w/ OK or no status at all", func() {
+			for _, body := range []string{`{"status": "OK"}`, `{}`} {
+				body := body
+
+				handler := func(w http.ResponseWriter, r *http.Request) {
+					fmt.Fprintln(w, body)
+				}
+
+				daemon := httptest.NewServer(http.HandlerFunc(handler))
+
+				client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+				require.NoError(t, err)
+
+				err = client.RawRequest(ctx, "/foo", nil, &struct{}{})
+				assert.NoError(t, err)
+
+				daemon.Close()
+			}
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+// nolint:funlen
+func TestBinaryRequest(t *testing.T) {
+	spec.Run(t, "BinaryRequest", func(t *testing.T, when spec.G, it spec.S) {
+		type params struct {
+			Heights []uint64 `epee:"heights"`
+		}
+
+		type result struct {
+			Status string       `epee:"status"`
+			Hashes []levin.Hash `epee:"hashes,blob"`
+		}
+
+		var (
+			ctx    = context.Background()
+			client *rpc.Client
+			err    error
+		)
+
+		it("errors w/ non-200 response", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(500)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.BinaryRequest(ctx, "/foo.bin", nil, &result{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "non-2xx status")
+		})
+
+		it("errors w/ malformed response", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"status": "OK"}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.BinaryRequest(ctx, "/foo.bin", nil, &result{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "decode")
+		})
+
+		it("fails if status is not OK", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				b, err := levin.Marshal(result{Status: "BUSY"})
+				assert.NoError(t, err)
+
+				_, _ = w.Write(b)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.BinaryRequest(ctx, "/foo.bin", nil, &result{})
+			assert.True(t, errors.Is(err, rpc.ErrBusy))
+		})
+
+		it("sends params and decodes result in portable storage format", func() {
+			var (
+				endpoint string
+				method   string
+				received params
+			)
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				endpoint = r.URL.Path
+				method = r.Method
+
+				b, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.NoError(t, levin.Unmarshal(b, &received))
+
+				b, err = levin.Marshal(result{
+					Status: "OK",
+					Hashes: []levin.Hash{{0x01}, {0x02}},
+				})
+				assert.NoError(t, err)
+
+				_, _ = w.Write(b)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			res := &result{}
+			err = client.BinaryRequest(ctx, "/foo.bin", params{Heights: []uint64{1, 2}}, res)
+			require.NoError(t, err)
+
+			assert.Equal(t, "/foo.bin", endpoint)
+			assert.Equal(t, "POST", method)
+			assert.Equal(t, []uint64{1, 2}, received.Heights)
+			assert.Equal(t, "OK", res.Status)
+			assert.Equal(t, []levin.Hash{{0x01}, {0x02}}, res.Hashes)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+// nolint:funlen
+func TestJSONRPCBatch(t *testing.T) {
+	spec.Run(t, "JSONRPCBatch", func(t *testing.T, when spec.G, it spec.S) {
+		type result struct {
+			Foo string `json:"foo"`
+		}
+
+		var (
+			ctx    = context.Background()
+			client *rpc.Client
+			err    error
+		)
+
+		it("errors if batches are not supported", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, `{"id":0,"jsonrpc":"2.0","error":{"code":-32600,"message":"Invalid Request"}}`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			err = client.JSONRPCBatch(ctx, []*rpc.BatchCall{
+				{Method: "foo", Result: &result{}},
+			})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "batch not supported")
+			assert.Contains(t, err.Error(), "Invalid Request")
+		})
+
+		it("sends all calls and demultiplexes responses by id", func() {
+			var (
+				endpoint string
+				received []map[string]interface{}
+			)
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				endpoint = r.URL.Path
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+
+				fmt.Fprintln(w, `[
+					{"id":"1","jsonrpc":"2.0","error":{"code":-2,"message":"bad"}},
+					{"id":"0","jsonrpc":"2.0","result":{"foo":"bar"}}
+				]`)
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			calls := []*rpc.BatchCall{
+				{Method: "a", Params: map[string]int{"x": 1}, Result: &result{}},
+				{Method: "b", Result: &result{}},
+				{Method: "c", Result: &result{}},
+			}
+
+			err = client.JSONRPCBatch(ctx, calls)
+			require.NoError(t, err)
+
+			assert.Equal(t, "/json_rpc", endpoint)
+			require.Len(t, received, 3)
+			assert.Equal(t, "a", received[0]["method"])
+			assert.Equal(t, "c", received[2]["method"])
+
+			assert.NoError(t, calls[0].Error)
+			assert.Equal(t, "bar", calls[0].Result.(*result).Foo)
+
+			assert.Error(t, calls[1].Error)
+			assert.Contains(t, calls[1].Error.Error(), "bad")
+
+			assert.Error(t, calls[2].Error)
+			assert.Contains(t, calls[2].Error.Error(), "no response")
+		})
+
+		it("tolerates epee's escaping in batches w/ binary strings", func() {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, "[{\"id\":\"0\",\"jsonrpc\":\"2.0\",\"result\":{\"foo\":\"a\\vb\"}}]")
+			}
+
+			daemon := httptest.NewServer(http.HandlerFunc(handler))
+			defer daemon.Close()
+
+			client, err = rpc.NewClient(daemon.URL, rpc.WithHTTPClient(daemon.Client()))
+			require.NoError(t, err)
+
+			calls := []*rpc.BatchCall{
+				{Method: "get_txpool_backlog", Result: &result{}},
+			}
+
+			err = client.JSONRPCBatch(ctx, calls)
+			require.NoError(t, err)
+
+			assert.NoError(t, calls[0].Error)
+			assert.Equal(t, "a\vb", calls[0].Result.(*result).Foo)
+		})
 	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
 }
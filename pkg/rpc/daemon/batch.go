@@ -0,0 +1,118 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+)
+
+// GetBlocks retrieves, in a single round trip, the blocks identified by each
+// of `params` (see GetBlock).
+//
+func (c *Client) GetBlocks(
+	ctx context.Context, params []GetBlockRequestParameters,
+) ([]*GetBlockResult, error) {
+	results := make([]*GetBlockResult, len(params))
+	calls := make([]*rpc.BatchCall, len(params))
+
+	for idx := range params {
+		results[idx] = &GetBlockResult{}
+		calls[idx] = &rpc.BatchCall{
+			Method: methodGetBlock,
+			Params: params[idx],
+			Result: results[idx],
+		}
+	}
+
+	if err := c.batch(ctx, calls); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetBlockHeadersByHeight retrieves, in a single round trip, the headers of
+// the blocks at `heights`.
+//
+func (c *Client) GetBlockHeadersByHeight(
+	ctx context.Context, heights []uint64,
+) ([]BlockHeader, error) {
+	results := make([]*GetBlockHeaderByHeightResult, len(heights))
+	calls := make([]*rpc.BatchCall, len(heights))
+
+	for idx, height := range heights {
+		results[idx] = &GetBlockHeaderByHeightResult{}
+		calls[idx] = &rpc.BatchCall{
+			Method: methodGetBlockHeaderByHeight,
+			Params: map[string]interface{}{
+				"height": height,
+			},
+			Result: results[idx],
+		}
+	}
+
+	if err := c.batch(ctx, calls); err != nil {
+		return nil, err
+	}
+
+	headers := make([]BlockHeader, len(results))
+	for idx, result := range results {
+		headers[idx] = result.BlockHeader
+	}
+
+	return headers, nil
+}
+
+// GetBlockHeadersByHash retrieves, in a single round trip, the headers of
+// the blocks w/ hashes `hashes`, one call per hash.
+//
+// ps.: `get_block_header_by_hash` can take multiple hashes on its own (see
+// GetBlockHeaderByHash), but a failure on any of them fails them all, while
+// here the block that couldn't be found is told apart.
+//
+func (c *Client) GetBlockHeadersByHash(
+	ctx context.Context, hashes []string,
+) ([]BlockHeader, error) {
+	results := make([]*GetBlockHeaderByHashResult, len(hashes))
+	calls := make([]*rpc.BatchCall, len(hashes))
+
+	for idx, hash := range hashes {
+		results[idx] = &GetBlockHeaderByHashResult{}
+		calls[idx] = &rpc.BatchCall{
+			Method: methodGetBlockHeaderByHash,
+			Params: map[string]interface{}{
+				"hash": hash,
+			},
+			Result: results[idx],
+		}
+	}
+
+	if err := c.batch(ctx, calls); err != nil {
+		return nil, err
+	}
+
+	headers := make([]BlockHeader, len(results))
+	for idx, result := range results {
+		headers[idx] = result.BlockHeader
+	}
+
+	return headers, nil
+}
+
+// batch issues `calls` in a single batch, failing w/ the error of the first
+// call that failed.
+//
+func (c *Client) batch(ctx context.Context, calls []*rpc.BatchCall) error {
+	if err := c.JSONRPCBatch(ctx, calls); err != nil {
+		return fmt.Errorf("jsonrpc batch: %w", err)
+	}
+
+	for idx, call := range calls {
+		if call.Error != nil {
+			return fmt.Errorf("call %d (%s): %w", idx, call.Method, call.Error)
+		}
+	}
+
+	return nil
+}
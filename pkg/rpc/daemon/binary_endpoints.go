@@ -0,0 +1,167 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+const (
+	endpointGetBlocksBin                = "/get_blocks.bin"
+	endpointGetBlocksByHeightBin        = "/get_blocks_by_height.bin"
+	endpointGetHashesBin                = "/get_hashes.bin"
+	endpointGetOIndexesBin              = "/get_o_indexes.bin"
+	endpointGetOutsBin                  = "/get_outs.bin"
+	endpointGetOutputDistributionBin    = "/get_output_distribution.bin"
+	endpointGetTransactionPoolHashesBin = "/get_transaction_pool_hashes.bin"
+)
+
+// GetBlocksBin retrieves, in bulk, the blocks (and their transactions) that
+// follow either the most recent of `params.BlockIDs` that the daemon knows
+// about or `params.StartHeight`.
+//
+// This is what wallets use for scanning the chain.
+//
+func (c *Client) GetBlocksBin(
+	ctx context.Context, params GetBlocksBinRequestParameters,
+) (*GetBlocksBinResult, error) {
+	resp := &GetBlocksBinResult{}
+
+	err := c.BinaryRequest(ctx, endpointGetBlocksBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetBlocksByHeightBin retrieves the blocks (and their transactions) at the
+// given heights.
+//
+func (c *Client) GetBlocksByHeightBin(
+	ctx context.Context, heights []uint64,
+) (*GetBlocksByHeightBinResult, error) {
+	resp := &GetBlocksByHeightBinResult{}
+
+	params := struct {
+		Heights []uint64 `epee:"heights"`
+	}{heights}
+
+	err := c.BinaryRequest(ctx, endpointGetBlocksByHeightBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetHashesBin retrieves the hashes of the blocks that follow either the
+// most recent of `blockIDs` that the daemon knows about or `startHeight`.
+//
+func (c *Client) GetHashesBin(
+	ctx context.Context, blockIDs []levin.Hash, startHeight uint64,
+) (*GetHashesBinResult, error) {
+	resp := &GetHashesBinResult{}
+
+	params := struct {
+		BlockIDs    []levin.Hash `epee:"block_ids,blob"`
+		StartHeight uint64       `epee:"start_height"`
+	}{blockIDs, startHeight}
+
+	err := c.BinaryRequest(ctx, endpointGetHashesBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOIndexesBin retrieves the global indexes of the outputs of the
+// transaction `txid`.
+//
+func (c *Client) GetOIndexesBin(
+	ctx context.Context, txid levin.Hash,
+) (*GetOIndexesBinResult, error) {
+	resp := &GetOIndexesBinResult{}
+
+	params := struct {
+		TxID levin.Hash `epee:"txid,blob"`
+	}{txid}
+
+	err := c.BinaryRequest(ctx, endpointGetOIndexesBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutsBin retrieves the keys, commitments, and whether they're unlocked,
+// of the outputs of amount `Amount` (0 for RingCT ones) at global index
+// `Index`.
+//
+func (c *Client) GetOutsBin(
+	ctx context.Context, outputs []GetOutsBinOutput, gettxid bool,
+) (*GetOutsBinResult, error) {
+	resp := &GetOutsBinResult{}
+
+	params := struct {
+		Outputs []GetOutsBinOutput `epee:"outputs"`
+		GetTxID bool               `epee:"get_txid"`
+	}{outputs, gettxid}
+
+	err := c.BinaryRequest(ctx, endpointGetOutsBin, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputDistributionBin retrieves, for each amount, the number of outputs
+// created at each block in the range requested.
+//
+// Compressed distributions (see `Compress`) are decompressed into
+// `Distribution`.
+//
+func (c *Client) GetOutputDistributionBin(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionBinResult, error) {
+	resp := &GetOutputDistributionBinResult{}
+
+	request := struct {
+		GetOutputDistributionRequestParameters
+		Binary bool `epee:"binary"`
+	}{params, true}
+
+	err := c.BinaryRequest(ctx, endpointGetOutputDistributionBin, request, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	for idx := range resp.Distributions {
+		err := resp.Distributions[idx].decompress(params.Cumulative)
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetTransactionPoolHashesBin retrieves the hashes of the transactions in
+// the pool.
+//
+func (c *Client) GetTransactionPoolHashesBin(
+	ctx context.Context,
+) (*GetTransactionPoolHashesBinResult, error) {
+	resp := &GetTransactionPoolHashesBinResult{}
+
+	err := c.BinaryRequest(ctx, endpointGetTransactionPoolHashesBin, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("binary request: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,181 @@
+package daemon
+
+import (
+	"github.com/cirocosta/go-monero/pkg/levin"
+)
+
+// BinaryResultStatus contains the fields that results of calls to the
+// binary (`.bin`) endpoints carry for indicating how the request went (see
+// `RPCResultFooter`).
+//
+type BinaryResultStatus struct {
+	// Status dictates whether the request worked or not. "OK" means good.
+	//
+	Status string `epee:"status"`
+
+	// Untrusted indicates whether the result was obtained using the
+	// bootstrap mode, and is therefore not to be trusted.
+	//
+	Untrusted bool `epee:"untrusted"`
+}
+
+// GetBlocksBinRequestParameters are the parameters of a call to the
+// GetBlocksBin endpoint.
+//
+type GetBlocksBinRequestParameters struct {
+	// BlockIDs is a list of block ids, with the first 10 being the most
+	// recent ones sequentially, then going back in powers of 2, and the
+	// last being the genesis block - the daemon starts from the most
+	// recent one that it knows about.
+	//
+	BlockIDs []levin.Hash `epee:"block_ids,blob"`
+
+	// StartHeight is the height to start from when none of `BlockIDs`
+	// are known (or if it's higher than the height of the one found).
+	//
+	StartHeight uint64 `epee:"start_height"`
+
+	// Prune indicates whether the prunable part of the transactions
+	// should be left out.
+	//
+	Prune bool `epee:"prune"`
+
+	// NoMinerTx indicates whether the miner transactions should be left
+	// out of the output indices.
+	//
+	NoMinerTx bool `epee:"no_miner_tx,omitempty"`
+}
+
+// GetBlocksBinResult is the result of a call to the GetBlocksBin endpoint.
+//
+type GetBlocksBinResult struct {
+	// Blocks are the blocks along with their transactions.
+	//
+	Blocks []levin.BlockCompleteEntry `epee:"blocks"`
+
+	// StartHeight is the height of the first block in `Blocks`.
+	//
+	StartHeight uint64 `epee:"start_height"`
+
+	// CurrentHeight is the height of the chain of the daemon.
+	//
+	CurrentHeight uint64 `epee:"current_height"`
+
+	// OutputIndices holds, for each block, the global indexes of the
+	// outputs of each of its transactions (miner transaction first).
+	//
+	OutputIndices []BlockOutputIndices `epee:"output_indices"`
+
+	// DaemonTime is the unix time at the daemon.
+	//
+	DaemonTime uint64 `epee:"daemon_time"`
+
+	BinaryResultStatus
+}
+
+// BlockOutputIndices are the global indexes of the outputs of the
+// transactions of a block.
+//
+type BlockOutputIndices struct {
+	Indices []TxOutputIndices `epee:"indices"`
+}
+
+// TxOutputIndices are the global indexes of the outputs of a transaction.
+//
+type TxOutputIndices struct {
+	Indices []uint64 `epee:"indices"`
+}
+
+// GetBlocksByHeightBinResult is the result of a call to the
+// GetBlocksByHeightBin endpoint.
+//
+type GetBlocksByHeightBinResult struct {
+	Blocks []levin.BlockCompleteEntry `epee:"blocks"`
+
+	BinaryResultStatus
+}
+
+// GetHashesBinResult is the result of a call to the GetHashesBin endpoint.
+//
+type GetHashesBinResult struct {
+	// BlockIDs are the hashes of the blocks starting at `StartHeight`.
+	//
+	BlockIDs []levin.Hash `epee:"m_block_ids,blob"`
+
+	// StartHeight is the height of the first block in `BlockIDs`.
+	//
+	StartHeight uint64 `epee:"start_height"`
+
+	// CurrentHeight is the height of the chain of the daemon.
+	//
+	CurrentHeight uint64 `epee:"current_height"`
+
+	BinaryResultStatus
+}
+
+// GetOIndexesBinResult is the result of a call to the GetOIndexesBin
+// endpoint.
+//
+type GetOIndexesBinResult struct {
+	// OIndexes are the global indexes of the outputs of the
+	// transaction, in the order they appear in it.
+	//
+	OIndexes []uint64 `epee:"o_indexes"`
+
+	BinaryResultStatus
+}
+
+// GetOutsBinOutput identifies an output by its amount (0 for RingCT ones)
+// and global index.
+//
+type GetOutsBinOutput struct {
+	Amount uint64 `epee:"amount"`
+	Index  uint64 `epee:"index"`
+}
+
+// GetOutsBinResult is the result of a call to the GetOutsBin endpoint.
+//
+type GetOutsBinResult struct {
+	Outs []struct {
+		// Key is the public key of the output.
+		//
+		Key levin.Hash `epee:"key,blob"`
+
+		// Mask is the commitment to the amount of the output.
+		//
+		Mask levin.Hash `epee:"mask,blob"`
+
+		// Unlocked indicates whether the output can be spent.
+		//
+		Unlocked bool `epee:"unlocked"`
+
+		// Height is the height of the block that the output is in.
+		//
+		Height uint64 `epee:"height"`
+
+		// TxID is the hash of the transaction that the output is in
+		// (only set if requested).
+		//
+		TxID levin.Hash `epee:"txid,blob"`
+	} `epee:"outs"`
+
+	BinaryResultStatus
+}
+
+// GetOutputDistributionBinResult is the result of a call to the
+// GetOutputDistributionBin endpoint.
+//
+type GetOutputDistributionBinResult struct {
+	Distributions []OutputDistribution `epee:"distributions"`
+
+	BinaryResultStatus
+}
+
+// GetTransactionPoolHashesBinResult is the result of a call to the
+// GetTransactionPoolHashesBin endpoint.
+//
+type GetTransactionPoolHashesBinResult struct {
+	TxHashes []levin.Hash `epee:"tx_hashes,blob"`
+
+	BinaryResultStatus
+}
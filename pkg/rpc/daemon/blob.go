@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Blob is binary data that monerod puts as is in JSON strings (e.g.,
+// compressed output distributions), which, unlike what `[]byte` expects, is
+// not base64-encoded.
+//
+// It's encoded back to JSON as a hex string.
+//
+type Blob []byte
+
+func (b *Blob) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
+
+	blob, err := unquoteBlob(data)
+	if err != nil {
+		return fmt.Errorf("unquote blob: %w", err)
+	}
+
+	*b = blob
+	return nil
+}
+
+func (b Blob) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+// unquoteBlob extracts the bytes of a JSON string holding a binary blob the
+// way epee encodes it: bytes as is, except for those escaped.
+//
+// ps.: unlike `strconv.Unquote` or `json.Unmarshal`, `\u00XX` escapes give
+// back the byte XX rather than a UTF-8 encoded rune, and invalid UTF-8 is
+// left untouched.
+//
+func unquoteBlob(data []byte) ([]byte, error) {
+	if len(data) < 2 || data[0] != '"' || data[len(data)-1] != '"' {
+		return nil, fmt.Errorf("expected string, got %q", data)
+	}
+
+	data = data[1 : len(data)-1]
+	blob := make([]byte, 0, len(data))
+
+	for idx := 0; idx < len(data); idx++ {
+		if data[idx] != '\\' {
+			blob = append(blob, data[idx])
+			continue
+		}
+
+		idx++
+		if idx == len(data) {
+			return nil, fmt.Errorf("unterminated escape sequence")
+		}
+
+		switch data[idx] {
+		case 'b':
+			blob = append(blob, '\b')
+		case 'f':
+			blob = append(blob, '\f')
+		case 'n':
+			blob = append(blob, '\n')
+		case 'r':
+			blob = append(blob, '\r')
+		case 't':
+			blob = append(blob, '\t')
+		case 'v':
+			blob = append(blob, '\v')
+		case '"', '\\', '/':
+			blob = append(blob, data[idx])
+		case 'u':
+			if idx+4 >= len(data) {
+				return nil, fmt.Errorf("truncated unicode escape sequence")
+			}
+
+			v, err := hex.DecodeString(string(data[idx+1 : idx+5]))
+			if err != nil || v[0] != 0 {
+				return nil, fmt.Errorf("unexpected unicode escape "+
+					"sequence '%s'", data[idx-1:idx+5])
+			}
+
+			blob = append(blob, v[1])
+			idx += 4
+		default:
+			return nil, fmt.Errorf("unknown escape sequence '\\%c'",
+				data[idx])
+		}
+	}
+
+	return blob, nil
+}
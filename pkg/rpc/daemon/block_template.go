@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
+)
+
+// Block gives back the block of the template with `extraNonce` put in the
+// space reserved for it (at `ReservedOffset`), so that miners working on the
+// same template can be told apart.
+//
+// `extraNonce` must not be bigger than the space reserved (`ReserveSize`).
+//
+func (r *GetBlockTemplateResult) Block(extraNonce []byte) ([]byte, error) {
+	blob, err := hex.DecodeString(r.BlocktemplateBlob)
+	if err != nil {
+		return nil, fmt.Errorf("decode blocktemplate blob: %w", err)
+	}
+
+	if uint(len(extraNonce)) > r.ReserveSize {
+		return nil, fmt.Errorf("extra nonce of %d bytes doesn't fit "+
+			"the %d bytes reserved", len(extraNonce), r.ReserveSize)
+	}
+
+	if r.ReservedOffset < 0 || r.ReservedOffset+len(extraNonce) > len(blob) {
+		return nil, fmt.Errorf("reserved offset %d out of the %d "+
+			"bytes of the blob", r.ReservedOffset, len(blob))
+	}
+
+	copy(blob[r.ReservedOffset:], extraNonce)
+
+	return blob, nil
+}
+
+// HashingBlob gives back the hashing blob (what the proof of work is computed
+// over) of the block of the template w/ `extraNonce` (see `Block`).
+//
+// The nonce that miners iterate over can be found at `NonceOffset`.
+//
+func (r *GetBlockTemplateResult) HashingBlob(extraNonce []byte) ([]byte, error) {
+	blob, err := r.Block(extraNonce)
+	if err != nil {
+		return nil, fmt.Errorf("block: %w", err)
+	}
+
+	hashingBlob, err := monero.BlockHashingBlob(blob)
+	if err != nil {
+		return nil, fmt.Errorf("block hashing blob: %w", err)
+	}
+
+	return hashingBlob, nil
+}
+
+// NonceOffset is where the nonce is both in the block of the template and in
+// its hashing blob.
+//
+func (r *GetBlockTemplateResult) NonceOffset() (int, error) {
+	blob, err := hex.DecodeString(r.BlocktemplateBlob)
+	if err != nil {
+		return 0, fmt.Errorf("decode blocktemplate blob: %w", err)
+	}
+
+	offset, err := monero.BlockNonceOffset(blob)
+	if err != nil {
+		return 0, fmt.Errorf("block nonce offset: %w", err)
+	}
+
+	return offset, nil
+}
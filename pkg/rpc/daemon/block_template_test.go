@@ -0,0 +1,123 @@
+package daemon_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/monero"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+// blockTemplate builds a block template w/ `reserveSize` bytes reserved in
+// the extra field of the miner transaction for an extra nonce, giving back
+// the blob and where the reserved space is.
+//
+func blockTemplate(reserveSize int) ([]byte, int) {
+	key := bytes.Repeat([]byte{0xaa}, monero.HashSize)
+
+	tx := []byte{
+		0x01,       // version
+		0x3c,       // unlock time
+		0x01,       // inputs
+		0xff, 0x00, // gen input at height 0
+		0x01, // outputs
+		0x01, // amount
+		0x02, // to key
+	}
+	tx = append(tx, key...)
+
+	extra := append([]byte{0x01}, key...)          // tx pub key
+	extra = append(extra, 0x02, byte(reserveSize)) // extra nonce
+	reservedOffset := len(extra)
+	extra = append(extra, make([]byte, reserveSize)...)
+
+	tx = append(tx, byte(len(extra)))
+	tx = append(tx, extra...)
+	reservedOffset += len(tx) - len(extra)
+
+	blob := []byte{
+		0x0e,             // major version
+		0x0e,             // minor version
+		0x80, 0x80, 0x01, // timestamp
+	}
+	blob = append(blob, bytes.Repeat([]byte{0xbb}, monero.HashSize)...) // prev id
+	blob = append(blob, 0x00, 0x00, 0x00, 0x00)                         // nonce
+
+	reservedOffset += len(blob)
+
+	blob = append(blob, tx...)
+	blob = append(blob, 0x00) // tx hashes
+
+	return blob, reservedOffset
+}
+
+func TestGetBlockTemplateResult(t *testing.T) {
+	spec.Run(t, "GetBlockTemplateResult", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			blob   []byte
+			result *daemon.GetBlockTemplateResult
+		)
+
+		it.Before(func() {
+			var reservedOffset int
+
+			blob, reservedOffset = blockTemplate(8)
+			result = &daemon.GetBlockTemplateResult{
+				BlocktemplateBlob: hex.EncodeToString(blob),
+				ReservedOffset:    reservedOffset,
+				ReserveSize:       8,
+			}
+		})
+
+		it("splices the extra nonce in the reserved space", func() {
+			block, err := result.Block([]byte{0x01, 0x02, 0x03})
+			require.NoError(t, err)
+
+			require.Len(t, block, len(blob))
+			assert.Equal(t, []byte{0x01, 0x02, 0x03, 0x00},
+				block[result.ReservedOffset:result.ReservedOffset+4])
+			assert.Equal(t, blob[:result.ReservedOffset], block[:result.ReservedOffset])
+		})
+
+		it("gives back the hashing blob of the block w/ the extra nonce", func() {
+			hashingBlob, err := result.HashingBlob([]byte{0x01})
+			require.NoError(t, err)
+
+			block, err := result.Block([]byte{0x01})
+			require.NoError(t, err)
+
+			expected, err := monero.BlockHashingBlob(block)
+			require.NoError(t, err)
+			assert.Equal(t, expected, hashingBlob)
+
+			original, err := monero.BlockHashingBlob(blob)
+			require.NoError(t, err)
+			assert.NotEqual(t, original, hashingBlob)
+
+			offset, err := result.NonceOffset()
+			require.NoError(t, err)
+			assert.Equal(t, 37, offset)
+			assert.Equal(t, block[:offset], hashingBlob[:offset])
+		})
+
+		it("fails if the extra nonce doesn't fit", func() {
+			_, err := result.Block(make([]byte, 9))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "doesn't fit")
+		})
+
+		it("fails w/ reserved offset out of the blob", func() {
+			result.ReservedOffset = len(blob) - 2
+
+			_, err := result.Block(make([]byte, 4))
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "out of")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
@@ -1,6 +1,10 @@
 package daemon
 
-import "context"
+import (
+	"context"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+)
 
 // Requester is responsible for making concrete request to Monero's endpoints,
 // i.e., either `jsonrpc` methods or those "raw" endpoints.
@@ -13,6 +17,11 @@ type Requester interface {
 		ctx context.Context, method string, params, result interface{},
 	) error
 
+	// JSONRPCBatch is used for calling many methods under `/json_rpc` in
+	// a single round trip, w/ results (and errors) set in each call.
+	//
+	JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error
+
 	// RawRequest is used for making a request to an arbitrary endpoint
 	// `endpoint` whose response (in JSON format) should be unmarshalled to
 	// `response`.
@@ -23,6 +32,17 @@ type Requester interface {
 		params interface{},
 		response interface{},
 	) error
+
+	// BinaryRequest is used for making a request to an endpoint that
+	// takes and returns epee's portable storage binary format (those
+	// ending in `.bin`), whose response should be decoded to `response`.
+	//
+	BinaryRequest(
+		ctx context.Context,
+		endpoint string,
+		params interface{},
+		response interface{},
+	) error
 }
 
 // Client provides access to the daemon's JSONRPC methods and regular
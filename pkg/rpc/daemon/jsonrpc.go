@@ -7,6 +7,8 @@ import (
 )
 
 const (
+	methodAddAuxPow              = "add_aux_pow"
+	methodCalcPow                = "calc_pow"
 	methodGenerateBlocks         = "generateblocks"
 	methodGetAlternateChains     = "get_alternate_chains"
 	methodGetBans                = "get_bans"
@@ -17,16 +19,24 @@ const (
 	methodGetBlockHeaderByHeight = "get_block_header_by_height"
 	methodGetBlockTemplate       = "get_block_template"
 	methodGetCoinbaseTxSum       = "get_coinbase_tx_sum"
+	methodFlushCache             = "flush_cache"
+	methodFlushTxpool            = "flush_txpool"
 	methodGetConnections         = "get_connections"
 	methodGetFeeEstimate         = "get_fee_estimate"
 	methodGetInfo                = "get_info"
 	methodGetLastBlockHeader     = "get_last_block_header"
+	methodGetMinerData           = "get_miner_data"
+	methodGetOutputDistribution  = "get_output_distribution"
+	methodGetOutputHistogram     = "get_output_histogram"
+	methodGetTxpoolBacklog       = "get_txpool_backlog"
 	methodGetVersion             = "get_version"
 	methodHardForkInfo           = "hard_fork_info"
 	methodOnGetBlockHash         = "on_get_block_hash"
+	methodPruneBlockchain        = "prune_blockchain"
 	methodRPCAccessTracking      = "rpc_access_tracking"
 	methodRelayTx                = "relay_tx"
 	methodSetBans                = "set_bans"
+	methodSubmitBlock            = "submit_block"
 	methodSyncInfo               = "sync_info"
 )
 
@@ -238,6 +248,8 @@ func (c *Client) GetBlockTemplate(
 		return nil, fmt.Errorf("jsonrpc: %w", err)
 	}
 
+	resp.ReserveSize = reserveSize
+
 	return resp, nil
 }
 
@@ -415,3 +427,231 @@ func (c *Client) SyncInfo(ctx context.Context) (*SyncInfoResult, error) {
 
 	return resp, nil
 }
+
+// FlushTxpool removes the transactions `txids` from the pool, or all of them
+// if none is specified.
+//
+// (restricted).
+//
+func (c *Client) FlushTxpool(
+	ctx context.Context, txids []string,
+) (*FlushTxpoolResult, error) {
+	resp := &FlushTxpoolResult{}
+	params := map[string]interface{}{
+		"txids": txids,
+	}
+
+	err := c.JSONRPC(ctx, methodFlushTxpool, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTxpoolBacklog retrieves the weight, fee and time in the pool of each of
+// the transactions in the pool.
+//
+func (c *Client) GetTxpoolBacklog(
+	ctx context.Context,
+) (*GetTxpoolBacklogResult, error) {
+	resp := &GetTxpoolBacklogResult{}
+
+	err := c.JSONRPC(ctx, methodGetTxpoolBacklog, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SubmitBlock submits mined blocks (`blobs`, hex-encoded) to the network.
+//
+func (c *Client) SubmitBlock(
+	ctx context.Context, blobs []string,
+) (*SubmitBlockResult, error) {
+	resp := &SubmitBlockResult{}
+
+	err := c.JSONRPC(ctx, methodSubmitBlock, blobs, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetOutputDistribution retrieves, for each amount, the number of outputs
+// created at each block in the range requested - what wallets base the
+// selection of decoys on.
+//
+// Compressed distributions (see `Compress`) are decompressed into
+// `Distribution`.
+//
+func (c *Client) GetOutputDistribution(
+	ctx context.Context, params GetOutputDistributionRequestParameters,
+) (*GetOutputDistributionResult, error) {
+	resp := &GetOutputDistributionResult{}
+
+	// compressed distributions are only sent in binary form.
+	//
+	request := struct {
+		GetOutputDistributionRequestParameters
+		Binary bool `json:"binary"`
+	}{params, params.Compress}
+
+	err := c.JSONRPC(ctx, methodGetOutputDistribution, request, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	for idx := range resp.Distributions {
+		err := resp.Distributions[idx].decompress(params.Cumulative)
+		if err != nil {
+			return nil, fmt.Errorf("decompress: %w", err)
+		}
+	}
+
+	return resp, nil
+}
+
+// GetOutputHistogramRequestParameters is the set of parameters to be passed
+// to the GetOutputHistogram RPC method.
+//
+type GetOutputHistogramRequestParameters struct {
+	// Amounts are the amounts to get the histogram of (all if none).
+	//
+	Amounts []uint64 `json:"amounts"`
+
+	// MinCount is the minimum number of instances for an amount to be
+	// included.
+	//
+	MinCount uint64 `json:"min_count,omitempty"`
+
+	// MaxCount is the maximum number of instances for an amount to be
+	// included (0 for no limit).
+	//
+	MaxCount uint64 `json:"max_count,omitempty"`
+
+	// Unlocked indicates whether only unlocked outputs should be
+	// counted.
+	//
+	Unlocked bool `json:"unlocked"`
+
+	// RecentCutoff is the unix time after which outputs are counted as
+	// recent.
+	//
+	RecentCutoff uint64 `json:"recent_cutoff,omitempty"`
+}
+
+// GetOutputHistogram retrieves, for each amount, how many outputs of it
+// there are.
+//
+func (c *Client) GetOutputHistogram(
+	ctx context.Context, params GetOutputHistogramRequestParameters,
+) (*GetOutputHistogramResult, error) {
+	resp := &GetOutputHistogramResult{}
+
+	err := c.JSONRPC(ctx, methodGetOutputHistogram, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PruneBlockchain prunes the blockchain, or, if `check` is set, just checks
+// whether it's pruned.
+//
+// (restricted).
+//
+func (c *Client) PruneBlockchain(
+	ctx context.Context, check bool,
+) (*PruneBlockchainResult, error) {
+	resp := &PruneBlockchainResult{}
+	params := map[string]interface{}{
+		"check": check,
+	}
+
+	err := c.JSONRPC(ctx, methodPruneBlockchain, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// FlushCache flushes the caches of transactions (`badTxs`) and blocks
+// (`badBlocks`) found to be invalid.
+//
+// (restricted).
+//
+func (c *Client) FlushCache(
+	ctx context.Context, badTxs, badBlocks bool,
+) (*FlushCacheResult, error) {
+	resp := &FlushCacheResult{}
+	params := map[string]interface{}{
+		"bad_txs":    badTxs,
+		"bad_blocks": badBlocks,
+	}
+
+	err := c.JSONRPC(ctx, methodFlushCache, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetMinerData retrieves what's needed for building a block template
+// without the help of the daemon.
+//
+func (c *Client) GetMinerData(ctx context.Context) (*GetMinerDataResult, error) {
+	resp := &GetMinerDataResult{}
+
+	err := c.JSONRPC(ctx, methodGetMinerData, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// CalcPow computes the proof of work hash of a block, giving it back
+// hex-encoded.
+//
+// (restricted).
+//
+func (c *Client) CalcPow(
+	ctx context.Context, params CalcPowRequestParameters,
+) (string, error) {
+	var resp string
+
+	err := c.JSONRPC(ctx, methodCalcPow, params, &resp)
+	if err != nil {
+		return "", fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
+
+// AddAuxPow adds the merkle root of the blocks of chains to be merge mined
+// (`auxPow`) to the block template `blocktemplateBlob` (hex-encoded).
+//
+// (restricted).
+//
+func (c *Client) AddAuxPow(
+	ctx context.Context, blocktemplateBlob string, auxPow []AuxPow,
+) (*AddAuxPowResult, error) {
+	resp := &AddAuxPowResult{}
+	params := map[string]interface{}{
+		"blocktemplate_blob": blocktemplateBlob,
+		"aux_pow":            auxPow,
+	}
+
+	err := c.JSONRPC(ctx, methodAddAuxPow, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("jsonrpc: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// GetOutputDistributionRequestParameters are the parameters of a call to
+// either the GetOutputDistribution RPC method or the GetOutputDistributionBin
+// endpoint.
+//
+type GetOutputDistributionRequestParameters struct {
+	// Amounts are the amounts to get the distributions of (0 for RingCT
+	// outputs).
+	//
+	Amounts []uint64 `json:"amounts" epee:"amounts"`
+
+	// FromHeight is the height to start the distribution at.
+	//
+	FromHeight uint64 `json:"from_height" epee:"from_height"`
+
+	// ToHeight is the height to end the distribution at (0 for the top
+	// of the chain).
+	//
+	ToHeight uint64 `json:"to_height" epee:"to_height"`
+
+	// Cumulative indicates whether each entry should be the sum of the
+	// outputs created up to that block (as opposed to in it).
+	//
+	Cumulative bool `json:"cumulative" epee:"cumulative"`
+
+	// Compress indicates whether the daemon should send the
+	// distribution varint-encoded, which is much smaller.
+	//
+	Compress bool `json:"compress" epee:"compress"`
+}
+
+// OutputDistribution is the distribution of the outputs of a given amount
+// over a range of blocks.
+//
+type OutputDistribution struct {
+	Amount uint64 `json:"amount" epee:"amount"`
+
+	// StartHeight is the height of the block that the first entry in
+	// `Distribution` corresponds to.
+	//
+	StartHeight uint64 `json:"start_height" epee:"start_height"`
+
+	// Base is the number of outputs created before `StartHeight` (0 if
+	// cumulative, as it's then already accounted for in `Distribution`).
+	//
+	Base uint64 `json:"base" epee:"base"`
+
+	// Distribution holds the number of outputs created at (or up to,
+	// if cumulative) each block.
+	//
+	Distribution []uint64 `json:"distribution" epee:"distribution,blob"`
+
+	// Cumulative indicates whether `Distribution` is cumulative or not
+	// (i.e., what was requested).
+	//
+	Cumulative bool `json:"cumulative" epee:"-"`
+
+	// Compress indicates whether the distribution came compressed.
+	//
+	Compress bool `json:"compress" epee:"compress"`
+
+	// CompressedData is the varint-encoded distribution, as sent by
+	// the daemon - it's cleared once decompressed into `Distribution`.
+	//
+	CompressedData Blob `json:"compressed_data,omitempty" epee:"compressed_data"`
+}
+
+// CumulativeDistribution gives back the number of outputs created up to (and
+// including) each block of the distribution.
+//
+func (d *OutputDistribution) CumulativeDistribution() []uint64 {
+	values := make([]uint64, len(d.Distribution))
+	if d.Cumulative {
+		copy(values, d.Distribution)
+		return values
+	}
+
+	sum := d.Base
+	for idx, v := range d.Distribution {
+		sum += v
+		values[idx] = sum
+	}
+
+	return values
+}
+
+// BlockDistribution gives back the number of outputs created at each block
+// of the distribution.
+//
+// ps.: for cumulative distributions, the first entry also includes the
+// outputs created before `StartHeight`, as that's not sent by the daemon.
+//
+func (d *OutputDistribution) BlockDistribution() []uint64 {
+	values := make([]uint64, len(d.Distribution))
+	if !d.Cumulative {
+		copy(values, d.Distribution)
+		return values
+	}
+
+	prev := uint64(0)
+	for idx, v := range d.Distribution {
+		values[idx] = v - prev
+		prev = v
+	}
+
+	return values
+}
+
+// decompress fills `Distribution` out of `CompressedData` (if compressed),
+// recording whether it's `cumulative`.
+//
+func (d *OutputDistribution) decompress(cumulative bool) error {
+	d.Cumulative = cumulative
+
+	if !d.Compress {
+		return nil
+	}
+
+	values, err := decompressIntegers(d.CompressedData)
+	if err != nil {
+		return fmt.Errorf("distribution of amount %d: %w", d.Amount, err)
+	}
+
+	d.Distribution, d.CompressedData = values, nil
+	return nil
+}
+
+// decompressIntegers decodes the concatenation of varint-encoded integers
+// that epee compresses integer arrays to (see `compress_integer_array`).
+//
+func decompressIntegers(b []byte) ([]uint64, error) {
+	values := []uint64{}
+
+	for len(b) > 0 {
+		v, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, fmt.Errorf("malformed varint at %d bytes from the end",
+				len(b))
+		}
+
+		values = append(values, v)
+		b = b[n:]
+	}
+
+	return values, nil
+}
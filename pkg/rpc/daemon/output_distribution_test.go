@@ -0,0 +1,120 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+// nolint:funlen
+func TestGetOutputDistribution(t *testing.T) {
+	spec.Run(t, "GetOutputDistribution", func(t *testing.T, when spec.G, it spec.S) {
+		var ctx = context.Background()
+
+		// serve serves `distribution` as the only distribution in the
+		// response, sending back the params of the request.
+		//
+		serve := func(distribution string) (*daemon.Client, <-chan map[string]interface{}) {
+			params := make(chan map[string]interface{}, 1)
+
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				req := struct {
+					Params map[string]interface{} `json:"params"`
+				}{}
+
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+				params <- req.Params
+
+				fmt.Fprintf(w, `{"id":"0","jsonrpc":"2.0","result":{"distributions":[%s],"status":"OK"}}`,
+					distribution)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			t.Cleanup(server.Close)
+
+			client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(server.Client()))
+			require.NoError(t, err)
+
+			return daemon.NewClient(client), params
+		}
+
+		it("decompresses varint-encoded distributions", func() {
+			compressed := []byte{}
+			for _, v := range []uint64{1, 300, 0, 2} {
+				b := make([]byte, binary.MaxVarintLen64)
+				compressed = append(compressed, b[:binary.PutUvarint(b, v)]...)
+			}
+
+			client, params := serve(fmt.Sprintf(
+				`{"amount":0,"base":10,"start_height":100,"binary":true,"compress":true,"compressed_data":"%s"}`,
+				epeeEscape(compressed)))
+
+			resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+				Amounts:    []uint64{0},
+				FromHeight: 100,
+				Compress:   true,
+			})
+			require.NoError(t, err)
+
+			sent := <-params
+			assert.Equal(t, true, sent["binary"])
+			assert.Equal(t, true, sent["compress"])
+			assert.Equal(t, false, sent["cumulative"])
+
+			require.Len(t, resp.Distributions, 1)
+			distribution := resp.Distributions[0]
+
+			assert.Equal(t, uint64(100), distribution.StartHeight)
+			assert.Equal(t, []uint64{1, 300, 0, 2}, distribution.Distribution)
+			assert.Empty(t, distribution.CompressedData)
+
+			assert.Equal(t, []uint64{1, 300, 0, 2}, distribution.BlockDistribution())
+			assert.Equal(t, []uint64{11, 311, 311, 313}, distribution.CumulativeDistribution())
+		})
+
+		it("takes cumulative distributions as such", func() {
+			client, params := serve(
+				`{"amount":0,"base":0,"start_height":5,"binary":false,"compress":false,"distribution":[5,7,7]}`)
+
+			resp, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+				Amounts:    []uint64{0},
+				FromHeight: 5,
+				Cumulative: true,
+			})
+			require.NoError(t, err)
+
+			sent := <-params
+			assert.Equal(t, false, sent["binary"])
+			assert.Equal(t, true, sent["cumulative"])
+
+			require.Len(t, resp.Distributions, 1)
+			distribution := resp.Distributions[0]
+
+			assert.True(t, distribution.Cumulative)
+			assert.Equal(t, []uint64{5, 7, 7}, distribution.CumulativeDistribution())
+			assert.Equal(t, []uint64{5, 2, 0}, distribution.BlockDistribution())
+		})
+
+		it("fails w/ malformed compressed data", func() {
+			client, _ := serve(`{"amount":0,"compress":true,"compressed_data":"ÿ"}`)
+
+			_, err := client.GetOutputDistribution(ctx, daemon.GetOutputDistributionRequestParameters{
+				Compress: true,
+			})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "malformed varint")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
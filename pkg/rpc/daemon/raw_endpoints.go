@@ -3,25 +3,39 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
 )
 
 const (
-	endpointGetHeight               = "/get_height"
-	endpointGetLimit                = "/get_limit"
-	endpointGetNetStats             = "/get_net_stats"
-	endpointGetOuts                 = "/get_outs"
-	endpointGetPeerList             = "/get_peer_list"
-	endpointGetPublicNodes          = "/get_public_nodes"
-	endpointGetTransactionPool      = "/get_transaction_pool"
-	endpointGetTransactionPoolStats = "/get_transaction_pool_stats"
-	endpointGetTransactions         = "/get_transactions"
-	endpointMiningStatus            = "/mining_status"
-	endpointSetLimit                = "/set_limit"
-	endpointSetLogLevel             = "/set_log_level"
-	endpointSetLogCategories        = "/set_log_categories"
-	endpointStartMining             = "/start_mining"
-	endpointStopMining              = "/stop_mining"
+	endpointGetAltBlocksHashes       = "/get_alt_blocks_hashes"
+	endpointGetHeight                = "/get_height"
+	endpointGetLimit                 = "/get_limit"
+	endpointGetNetStats              = "/get_net_stats"
+	endpointGetOuts                  = "/get_outs"
+	endpointGetPeerList              = "/get_peer_list"
+	endpointGetPublicNodes           = "/get_public_nodes"
+	endpointGetTransactionPool       = "/get_transaction_pool"
+	endpointGetTransactionPoolHashes = "/get_transaction_pool_hashes"
+	endpointGetTransactionPoolStats  = "/get_transaction_pool_stats"
+	endpointGetTransactions          = "/get_transactions"
+	endpointInPeers                  = "/in_peers"
+	endpointIsKeyImageSpent          = "/is_key_image_spent"
+	endpointMiningStatus             = "/mining_status"
+	endpointOutPeers                 = "/out_peers"
+	endpointPopBlocks                = "/pop_blocks"
+	endpointSaveBc                   = "/save_bc"
+	endpointSendRawTransaction       = "/send_raw_transaction"
+	endpointSetBootstrapDaemon       = "/set_bootstrap_daemon"
+	endpointSetLimit                 = "/set_limit"
+	endpointSetLogLevel              = "/set_log_level"
+	endpointSetLogCategories         = "/set_log_categories"
+	endpointStartMining              = "/start_mining"
+	endpointStopMining               = "/stop_mining"
+	endpointUpdate                   = "/update"
 )
 
 func (c *Client) StopMining(
@@ -257,3 +271,222 @@ func (c *Client) GetTransactions(
 
 	return resp, nil
 }
+
+// SendRawTransaction broadcasts a transaction (`params.TxAsHex`) to the
+// network.
+//
+// A transaction being rejected is reported as a *SendRawTransactionError,
+// which carries the result w/ the reasons why (see `errors.As`).
+//
+func (c *Client) SendRawTransaction(
+	ctx context.Context, params SendRawTransactionRequestParameters,
+) (*SendRawTransactionResult, error) {
+	resp := &SendRawTransactionResult{}
+
+	err := c.RawRequest(ctx, endpointSendRawTransaction, params, resp)
+	if err != nil {
+		var statusErr *rpc.StatusError
+		if errors.As(err, &statusErr) {
+			err = &SendRawTransactionError{Result: resp, Err: err}
+		}
+
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SendRawTransactionError is the error for transactions that the daemon
+// rejected, carrying the result that tells why.
+//
+type SendRawTransactionError struct {
+	Result *SendRawTransactionResult
+	Err    error
+}
+
+func (e *SendRawTransactionError) Error() string {
+	reasons := e.Result.Reasons()
+	if len(reasons) == 0 {
+		return fmt.Sprintf("rejected: %v", e.Err)
+	}
+
+	return fmt.Sprintf("rejected: %v (%s)", e.Err,
+		strings.Join(reasons, ", "))
+}
+
+func (e *SendRawTransactionError) Unwrap() error {
+	return e.Err
+}
+
+// IsKeyImageSpent checks whether the key images `keyImages` have been spent,
+// either in the chain or by a transaction in the pool.
+//
+func (c *Client) IsKeyImageSpent(
+	ctx context.Context, keyImages []string,
+) (*IsKeyImageSpentResult, error) {
+	resp := &IsKeyImageSpentResult{}
+	params := map[string]interface{}{
+		"key_images": keyImages,
+	}
+
+	err := c.RawRequest(ctx, endpointIsKeyImageSpent, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetTransactionPoolHashes retrieves the hashes of the transactions in the
+// pool.
+//
+func (c *Client) GetTransactionPoolHashes(
+	ctx context.Context,
+) (*GetTransactionPoolHashesResult, error) {
+	resp := &GetTransactionPoolHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetTransactionPoolHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// PopBlocks removes the `blocks` most recent blocks from the chain.
+//
+// (restricted).
+//
+func (c *Client) PopBlocks(
+	ctx context.Context, blocks uint64,
+) (*PopBlocksResult, error) {
+	resp := &PopBlocksResult{}
+	params := map[string]interface{}{
+		"nblocks": blocks,
+	}
+
+	err := c.RawRequest(ctx, endpointPopBlocks, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SaveBc saves the blockchain to disk.
+//
+// (restricted).
+//
+func (c *Client) SaveBc(ctx context.Context) (*SaveBcResult, error) {
+	resp := &SaveBcResult{}
+
+	err := c.RawRequest(ctx, endpointSaveBc, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// InPeers limits the number of incoming peers to `limit`, or, if `limit` is
+// negative, just retrieves the current limit.
+//
+// (restricted).
+//
+func (c *Client) InPeers(ctx context.Context, limit int64) (*InPeersResult, error) {
+	resp := &InPeersResult{}
+	params := peersLimitParams("in_peers", limit)
+
+	err := c.RawRequest(ctx, endpointInPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// OutPeers limits the number of outgoing peers to `limit`, or, if `limit` is
+// negative, just retrieves the current limit.
+//
+// (restricted).
+//
+func (c *Client) OutPeers(ctx context.Context, limit int64) (*OutPeersResult, error) {
+	resp := &OutPeersResult{}
+	params := peersLimitParams("out_peers", limit)
+
+	err := c.RawRequest(ctx, endpointOutPeers, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+func peersLimitParams(field string, limit int64) map[string]interface{} {
+	if limit < 0 {
+		return map[string]interface{}{
+			"set": false,
+		}
+	}
+
+	return map[string]interface{}{
+		"set": true,
+		field: limit,
+	}
+}
+
+// Update checks for updates of monerod (`UpdateCommandCheck`) or downloads
+// them (`UpdateCommandDownload`) to `path` (a default location if empty).
+//
+// (restricted).
+//
+func (c *Client) Update(
+	ctx context.Context, command UpdateCommand, path string,
+) (*UpdateResult, error) {
+	resp := &UpdateResult{}
+	params := map[string]interface{}{
+		"command": command,
+		"path":    path,
+	}
+
+	err := c.RawRequest(ctx, endpointUpdate, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// SetBootstrapDaemon sets the daemon that the node should forward requests
+// it can't serve (while syncing) to.
+//
+// (restricted).
+//
+func (c *Client) SetBootstrapDaemon(
+	ctx context.Context, params SetBootstrapDaemonRequestParameters,
+) (*SetBootstrapDaemonResult, error) {
+	resp := &SetBootstrapDaemonResult{}
+
+	err := c.RawRequest(ctx, endpointSetBootstrapDaemon, params, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// GetAltBlocksHashes retrieves the hashes of the blocks in alternative chains
+// (see GetAlternateChains) that the node knows about.
+//
+func (c *Client) GetAltBlocksHashes(
+	ctx context.Context,
+) (*GetAltBlocksHashesResult, error) {
+	resp := &GetAltBlocksHashesResult{}
+
+	err := c.RawRequest(ctx, endpointGetAltBlocksHashes, nil, resp)
+	if err != nil {
+		return nil, fmt.Errorf("raw request: %w", err)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,77 @@
+package daemon_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+func TestSendRawTransaction(t *testing.T) {
+	spec.Run(t, "SendRawTransaction", func(t *testing.T, when spec.G, it spec.S) {
+		var ctx = context.Background()
+
+		serve := func(result string) *daemon.Client {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, result)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			t.Cleanup(server.Close)
+
+			client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(server.Client()))
+			require.NoError(t, err)
+
+			return daemon.NewClient(client)
+		}
+
+		it("gives back the result of accepted transactions", func() {
+			client := serve(`{"status":"OK","not_relayed":true}`)
+
+			resp, err := client.SendRawTransaction(ctx,
+				daemon.SendRawTransactionRequestParameters{TxAsHex: "aa"})
+			require.NoError(t, err)
+
+			assert.True(t, resp.NotRelayed)
+		})
+
+		it("fails w/ the result of rejected transactions", func() {
+			client := serve(`{"status":"Failed","reason":"","double_spend":true}`)
+
+			_, err := client.SendRawTransaction(ctx,
+				daemon.SendRawTransactionRequestParameters{TxAsHex: "aa"})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "double spend")
+
+			var rejected *daemon.SendRawTransactionError
+			require.True(t, errors.As(err, &rejected))
+			assert.Equal(t, "Failed", rejected.Result.Status)
+			assert.True(t, rejected.Result.DoubleSpend)
+			assert.Equal(t, []string{"double spend"}, rejected.Result.Reasons())
+
+			var statusErr *rpc.StatusError
+			assert.True(t, errors.As(err, &statusErr))
+		})
+
+		it("fails w/out a result for other errors", func() {
+			client := serve(`not json`)
+
+			_, err := client.SendRawTransaction(ctx,
+				daemon.SendRawTransactionRequestParameters{TxAsHex: "aa"})
+			require.Error(t, err)
+
+			var rejected *daemon.SendRawTransactionError
+			assert.False(t, errors.As(err, &rejected))
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
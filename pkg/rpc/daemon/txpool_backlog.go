@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// txpoolBacklogEntrySize is the size of each entry in the backlog blob: the
+// weight, fee and time in the pool, each a little-endian uint64.
+//
+const txpoolBacklogEntrySize = 24
+
+// TxpoolBacklogEntry describes a transaction in the pool.
+//
+type TxpoolBacklogEntry struct {
+	Weight     uint64 `json:"weight"`
+	Fee        uint64 `json:"fee"`
+	TimeInPool uint64 `json:"time_in_pool"`
+}
+
+// TxpoolBacklog is the backlog of transactions in the pool.
+//
+// monerod sends it as the raw bytes of the entries put in a JSON string (as
+// opposed to, say, an array of objects), so it needs special handling for
+// getting them back.
+//
+type TxpoolBacklog []TxpoolBacklogEntry
+
+func (b *TxpoolBacklog) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
+
+	blob, err := unquoteBlob(data)
+	if err != nil {
+		return fmt.Errorf("unquote blob: %w", err)
+	}
+
+	if len(blob)%txpoolBacklogEntrySize != 0 {
+		return fmt.Errorf("blob size %d not a multiple of %d",
+			len(blob), txpoolBacklogEntrySize)
+	}
+
+	backlog := make(TxpoolBacklog, len(blob)/txpoolBacklogEntrySize)
+	for idx := range backlog {
+		entry := blob[idx*txpoolBacklogEntrySize:]
+
+		backlog[idx] = TxpoolBacklogEntry{
+			Weight:     binary.LittleEndian.Uint64(entry[0:8]),
+			Fee:        binary.LittleEndian.Uint64(entry[8:16]),
+			TimeInPool: binary.LittleEndian.Uint64(entry[16:24]),
+		}
+	}
+
+	*b = backlog
+	return nil
+}
@@ -0,0 +1,109 @@
+package daemon_test
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+// epeeEscape escapes `b` for being put in a JSON string the way epee does,
+// leaving bytes that don't need escaping as they are.
+//
+func epeeEscape(b []byte) string {
+	escaped := []byte{}
+
+	for _, c := range b {
+		switch c {
+		case '\b':
+			escaped = append(escaped, `\b`...)
+		case '\f':
+			escaped = append(escaped, `\f`...)
+		case '\n':
+			escaped = append(escaped, `\n`...)
+		case '\r':
+			escaped = append(escaped, `\r`...)
+		case '\t':
+			escaped = append(escaped, `\t`...)
+		case '\v':
+			escaped = append(escaped, `\v`...)
+		case '"', '\\', '/':
+			escaped = append(escaped, '\\', c)
+		default:
+			if c < 0x20 {
+				escaped = append(escaped, fmt.Sprintf(`\u%04x`, c)...)
+				continue
+			}
+
+			escaped = append(escaped, c)
+		}
+	}
+
+	return string(escaped)
+}
+
+func TestGetTxpoolBacklog(t *testing.T) {
+	spec.Run(t, "GetTxpoolBacklog", func(t *testing.T, when spec.G, it spec.S) {
+		var ctx = context.Background()
+
+		serve := func(backlog string) *daemon.Client {
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, `{"id":"0","jsonrpc":"2.0","result":{"backlog":"%s","status":"OK"}}`,
+					backlog)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(handler))
+			t.Cleanup(server.Close)
+
+			client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(server.Client()))
+			require.NoError(t, err)
+
+			return daemon.NewClient(client)
+		}
+
+		it("decodes the entries out of the binary blob", func() {
+			expected := daemon.TxpoolBacklog{
+				{Weight: 0x0b, Fee: 0xff22_5c0a_0001_2f80, TimeInPool: 1},
+				{Weight: 1500, Fee: 30_000_000, TimeInPool: 0x0c0d_0908},
+			}
+
+			blob := []byte{}
+			for _, entry := range expected {
+				b := make([]byte, 24)
+				binary.LittleEndian.PutUint64(b[0:], entry.Weight)
+				binary.LittleEndian.PutUint64(b[8:], entry.Fee)
+				binary.LittleEndian.PutUint64(b[16:], entry.TimeInPool)
+
+				blob = append(blob, b...)
+			}
+
+			resp, err := serve(epeeEscape(blob)).GetTxpoolBacklog(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, "OK", resp.Status)
+			assert.Equal(t, expected, resp.Backlog)
+		})
+
+		it("decodes an empty backlog", func() {
+			resp, err := serve("").GetTxpoolBacklog(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, resp.Backlog)
+		})
+
+		it("fails w/ truncated entries", func() {
+			_, err := serve(epeeEscape(make([]byte, 25))).GetTxpoolBacklog(ctx)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "not a multiple of 24")
+		})
+	}, spec.Report(report.Log{}), spec.Parallel(), spec.Random())
+}
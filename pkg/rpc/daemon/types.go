@@ -274,10 +274,36 @@ type GetBlockTemplateResult struct {
 	//
 	PrevHash string `json:"prev_hash"`
 
-	// ReservedOffset TODO
+	// ReservedOffset is the offset in `BlocktemplateBlob` of the space
+	// reserved (in the extra field of the miner transaction) for an extra
+	// nonce - see `Block`.
 	//
 	ReservedOffset int `json:"reserved_offset"`
 
+	// ReserveSize is the size of the space reserved for an extra nonce,
+	// as requested (it's not sent back by the daemon).
+	//
+	ReserveSize uint `json:"-"`
+
+	// SeedHash is the hash of the block whose RandomX dataset should be
+	// used for computing the proof of work.
+	//
+	SeedHash string `json:"seed_hash"`
+
+	// SeedHeight is the height of the block of `SeedHash`.
+	//
+	SeedHeight uint64 `json:"seed_height"`
+
+	// NextSeedHash is the hash of the next seed block, if it's about to
+	// change.
+	//
+	NextSeedHash string `json:"next_seed_hash"`
+
+	// WideDifficulty is the difficulty of the next block as a
+	// hexadecimal string representing a 128-bit number.
+	//
+	WideDifficulty string `json:"wide_difficulty"`
+
 	RPCResultFooter `json:",inline"`
 }
 
@@ -925,3 +951,464 @@ type StartMiningResult struct {
 type StopMiningResult struct {
 	RPCResultFooter `json:",inline"`
 }
+
+// SendRawTransactionRequestParameters is the set of parameters to be passed
+// to the SendRawTransaction RPC method.
+//
+type SendRawTransactionRequestParameters struct {
+	// TxAsHex is the full transaction blob, hex-encoded.
+	//
+	TxAsHex string `json:"tx_as_hex"`
+
+	// DoNotRelay indicates that the transaction should be added to the
+	// pool of the node, but not relayed to the network.
+	//
+	DoNotRelay bool `json:"do_not_relay"`
+
+	// DoSanityChecks indicates whether the node should check that the
+	// transaction looks sane (e.g., ring members not being too old)
+	// before accepting it. Left unset, the node does check.
+	//
+	DoSanityChecks *bool `json:"do_sanity_checks,omitempty"`
+}
+
+// SendRawTransactionResult is the result of a call to the SendRawTransaction
+// RPC method.
+//
+type SendRawTransactionResult struct {
+	// Reason is a human-readable explanation of why the transaction was
+	// rejected (if it was).
+	//
+	Reason string `json:"reason"`
+
+	// NotRelayed indicates that the transaction was not relayed.
+	//
+	NotRelayed bool `json:"not_relayed"`
+
+	// LowMixin indicates that the ring size is too low.
+	//
+	LowMixin bool `json:"low_mixin"`
+
+	// DoubleSpend indicates that the transaction spends a key image that
+	// has already been spent.
+	//
+	DoubleSpend bool `json:"double_spend"`
+
+	// InvalidInput indicates that an input is invalid.
+	//
+	InvalidInput bool `json:"invalid_input"`
+
+	// InvalidOutput indicates that an output is invalid.
+	//
+	InvalidOutput bool `json:"invalid_output"`
+
+	// TooBig indicates that the transaction is too big.
+	//
+	TooBig bool `json:"too_big"`
+
+	// Overspend indicates that the transaction spends more than what its
+	// inputs amount to.
+	//
+	Overspend bool `json:"overspend"`
+
+	// FeeTooLow indicates that the fee is too low.
+	//
+	FeeTooLow bool `json:"fee_too_low"`
+
+	// TooFewOutputs indicates that the transaction has fewer outputs
+	// than required.
+	//
+	TooFewOutputs bool `json:"too_few_outputs"`
+
+	// SanityCheckFailed indicates that the transaction failed the sanity
+	// checks.
+	//
+	SanityCheckFailed bool `json:"sanity_check_failed"`
+
+	// TxExtraTooBig indicates that the extra field is too big.
+	//
+	TxExtraTooBig bool `json:"tx_extra_too_big"`
+
+	// NonzeroUnlockTime indicates that the unlock time is not zero.
+	//
+	NonzeroUnlockTime bool `json:"nonzero_unlock_time"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// Reasons lists why the transaction was rejected, if it was.
+//
+func (r *SendRawTransactionResult) Reasons() []string {
+	reasons := []string{}
+
+	if r.Reason != "" {
+		reasons = append(reasons, r.Reason)
+	}
+
+	for _, flag := range []struct {
+		reason string
+		set    bool
+	}{
+		{"low mixin", r.LowMixin},
+		{"double spend", r.DoubleSpend},
+		{"invalid input", r.InvalidInput},
+		{"invalid output", r.InvalidOutput},
+		{"too big", r.TooBig},
+		{"overspend", r.Overspend},
+		{"fee too low", r.FeeTooLow},
+		{"too few outputs", r.TooFewOutputs},
+		{"sanity check failed", r.SanityCheckFailed},
+		{"tx extra too big", r.TxExtraTooBig},
+		{"nonzero unlock time", r.NonzeroUnlockTime},
+	} {
+		if flag.set {
+			reasons = append(reasons, flag.reason)
+		}
+	}
+
+	return reasons
+}
+
+// KeyImageSpentStatus is the status of a key image as reported by the
+// IsKeyImageSpent RPC method.
+//
+type KeyImageSpentStatus int
+
+const (
+	KeyImageUnspent           KeyImageSpentStatus = 0
+	KeyImageSpentInBlockchain KeyImageSpentStatus = 1
+	KeyImageSpentInPool       KeyImageSpentStatus = 2
+)
+
+func (s KeyImageSpentStatus) String() string {
+	switch s {
+	case KeyImageUnspent:
+		return "unspent"
+	case KeyImageSpentInBlockchain:
+		return "spent in blockchain"
+	case KeyImageSpentInPool:
+		return "spent in pool"
+	}
+
+	return "unknown"
+}
+
+// IsKeyImageSpentResult is the result of a call to the IsKeyImageSpent RPC
+// method.
+//
+type IsKeyImageSpentResult struct {
+	// SpentStatus holds the status of each of the key images, in the
+	// same order as they were requested.
+	//
+	SpentStatus []KeyImageSpentStatus `json:"spent_status"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetTransactionPoolHashesResult is the result of a call to the
+// GetTransactionPoolHashes RPC method.
+//
+type GetTransactionPoolHashesResult struct {
+	TxHashes []string `json:"tx_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// FlushTxpoolResult is the result of a call to the FlushTxpool RPC method.
+//
+type FlushTxpoolResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// GetTxpoolBacklogResult is the result of a call to the GetTxpoolBacklog RPC
+// method.
+//
+type GetTxpoolBacklogResult struct {
+	Backlog TxpoolBacklog `json:"backlog"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SubmitBlockResult is the result of a call to the SubmitBlock RPC method.
+//
+type SubmitBlockResult struct {
+	// BlockID is the hash of the block submitted.
+	//
+	BlockID string `json:"block_id"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetOutputDistributionResult is the result of a call to the
+// GetOutputDistribution RPC method.
+//
+type GetOutputDistributionResult struct {
+	Distributions []OutputDistribution `json:"distributions"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// GetOutputHistogramResult is the result of a call to the GetOutputHistogram
+// RPC method.
+//
+type GetOutputHistogramResult struct {
+	Histogram []struct {
+		// Amount is the amount of the outputs.
+		//
+		Amount uint64 `json:"amount"`
+
+		// TotalInstances is the number of outputs of the amount.
+		//
+		TotalInstances uint64 `json:"total_instances"`
+
+		// UnlockedInstances is the number of unlocked outputs of the
+		// amount.
+		//
+		UnlockedInstances uint64 `json:"unlocked_instances"`
+
+		// RecentInstances is the number of outputs of the amount
+		// created after the recent cutoff.
+		//
+		RecentInstances uint64 `json:"recent_instances"`
+	} `json:"histogram"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// PopBlocksResult is the result of a call to the PopBlocks RPC method.
+//
+type PopBlocksResult struct {
+	// Height is the height of the chain after the blocks were removed.
+	//
+	Height uint64 `json:"height"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SaveBcResult is the result of a call to the SaveBc RPC method.
+//
+type SaveBcResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// InPeersResult is the result of a call to the InPeers RPC method.
+//
+type InPeersResult struct {
+	// InPeers is the limit of incoming peers.
+	//
+	InPeers uint64 `json:"in_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// OutPeersResult is the result of a call to the OutPeers RPC method.
+//
+type OutPeersResult struct {
+	// OutPeers is the limit of outgoing peers.
+	//
+	OutPeers uint64 `json:"out_peers"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// UpdateCommand is what the Update RPC method should do.
+//
+type UpdateCommand string
+
+const (
+	UpdateCommandCheck    UpdateCommand = "check"
+	UpdateCommandDownload UpdateCommand = "download"
+)
+
+// UpdateResult is the result of a call to the Update RPC method.
+//
+type UpdateResult struct {
+	// Update indicates whether there's an update available.
+	//
+	Update bool `json:"update"`
+
+	// Version is the version available.
+	//
+	Version string `json:"version"`
+
+	// UserURI is the URI for humans to download the update from.
+	//
+	UserURI string `json:"user_uri"`
+
+	// AutoURI is the URI for automatically downloading the update.
+	//
+	AutoURI string `json:"auto_uri"`
+
+	// Hash is the hash of the update.
+	//
+	Hash string `json:"hash"`
+
+	// Path is where the update was downloaded to.
+	//
+	Path string `json:"path"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// SetBootstrapDaemonRequestParameters is the set of parameters to be passed
+// to the SetBootstrapDaemon RPC method.
+//
+type SetBootstrapDaemonRequestParameters struct {
+	// Address is the address of the bootstrap daemon ("auto" for
+	// picking one automatically, empty for disabling it).
+	//
+	Address string `json:"address"`
+
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// Proxy is a socks proxy to connect to the bootstrap daemon
+	// through.
+	//
+	Proxy string `json:"proxy,omitempty"`
+}
+
+// SetBootstrapDaemonResult is the result of a call to the SetBootstrapDaemon
+// RPC method.
+//
+type SetBootstrapDaemonResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// GetAltBlocksHashesResult is the result of a call to the GetAltBlocksHashes
+// RPC method.
+//
+type GetAltBlocksHashesResult struct {
+	BlksHashes []string `json:"blks_hashes"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// PruneBlockchainResult is the result of a call to the PruneBlockchain RPC
+// method.
+//
+type PruneBlockchainResult struct {
+	// Pruned indicates whether the blockchain is pruned.
+	//
+	Pruned bool `json:"pruned"`
+
+	// PruningSeed is the seed determining which part of the prunable
+	// data is kept.
+	//
+	PruningSeed uint32 `json:"pruning_seed"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// FlushCacheResult is the result of a call to the FlushCache RPC method.
+//
+type FlushCacheResult struct {
+	RPCResultFooter `json:",inline"`
+}
+
+// GetMinerDataResult is the result of a call to the GetMinerData RPC method.
+//
+type GetMinerDataResult struct {
+	// MajorVersion is the major version of the next block.
+	//
+	MajorVersion uint8 `json:"major_version"`
+
+	// Height is the height of the next block.
+	//
+	Height uint64 `json:"height"`
+
+	// PrevID is the hash of the most recent block.
+	//
+	PrevID string `json:"prev_id"`
+
+	// SeedHash is the hash of the block whose RandomX dataset should be
+	// used for computing the proof of work of the next block.
+	//
+	SeedHash string `json:"seed_hash"`
+
+	// Difficulty is the difficulty of the next block as a hexadecimal
+	// string representing a 128-bit number.
+	//
+	Difficulty string `json:"difficulty"`
+
+	// MedianWeight is the median weight of the recent blocks.
+	//
+	MedianWeight uint64 `json:"median_weight"`
+
+	// AlreadyGeneratedCoins is the total amount of coins emitted so far.
+	//
+	AlreadyGeneratedCoins uint64 `json:"already_generated_coins"`
+
+	// TxBacklog holds the transactions in the pool that could be
+	// included in the next block.
+	//
+	TxBacklog []struct {
+		ID     string `json:"id"`
+		Weight uint64 `json:"weight"`
+		Fee    uint64 `json:"fee"`
+	} `json:"tx_backlog"`
+
+	RPCResultFooter `json:",inline"`
+}
+
+// CalcPowRequestParameters is the set of parameters to be passed to the
+// CalcPow RPC method.
+//
+type CalcPowRequestParameters struct {
+	// MajorVersion is the major version of the block.
+	//
+	MajorVersion uint8 `json:"major_version"`
+
+	// Height is the height of the block.
+	//
+	Height uint64 `json:"height"`
+
+	// BlockBlob is the hashing blob of the block, hex-encoded.
+	//
+	BlockBlob string `json:"block_blob"`
+
+	// SeedHash is the hash of the block whose RandomX dataset should be
+	// used (found automatically if empty).
+	//
+	SeedHash string `json:"seed_hash"`
+}
+
+// AuxPow identifies the block of a merge-mined chain.
+//
+type AuxPow struct {
+	// ID is the unique id of the chain.
+	//
+	ID string `json:"id"`
+
+	// Hash is the hash of the block of the chain to be merge mined.
+	//
+	Hash string `json:"hash"`
+}
+
+// AddAuxPowResult is the result of a call to the AddAuxPow RPC method.
+//
+type AddAuxPowResult struct {
+	// BlocktemplateBlob is the block template w/ the merkle root of the
+	// merge-mined chains put in the extra field of the miner transaction.
+	//
+	BlocktemplateBlob string `json:"blocktemplate_blob"`
+
+	// BlockhashingBlob is the hashing blob of `BlocktemplateBlob`.
+	//
+	BlockhashingBlob string `json:"blockhashing_blob"`
+
+	// MerkleRoot is the root of the merkle tree of the merge-mined
+	// chains.
+	//
+	MerkleRoot string `json:"merkle_root"`
+
+	// MerkleTreeDepth is the depth of the merkle tree.
+	//
+	MerkleTreeDepth uint64 `json:"merkle_tree_depth"`
+
+	// AuxPow holds the chains in the order they are in the tree.
+	//
+	AuxPow []AuxPow `json:"aux_pow"`
+
+	RPCResultFooter `json:",inline"`
+}
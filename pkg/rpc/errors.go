@@ -0,0 +1,166 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Error codes that monerod uses in the errors of JSONRPC methods (see
+// `core_rpc_server_error_codes.h`), along with those from the JSONRPC spec.
+//
+const (
+	ErrorCodeWrongParam           = -1
+	ErrorCodeTooBigHeight         = -2
+	ErrorCodeTooBigReserveSize    = -3
+	ErrorCodeWrongWalletAddress   = -4
+	ErrorCodeInternalError        = -5
+	ErrorCodeWrongBlockblob       = -6
+	ErrorCodeBlockNotAccepted     = -7
+	ErrorCodeCoreBusy             = -9
+	ErrorCodeWrongBlockblobSize   = -10
+	ErrorCodeUnsupportedRPC       = -11
+	ErrorCodeMiningToSubaddress   = -12
+	ErrorCodeRegtestRequired      = -13
+	ErrorCodePaymentRequired      = -14
+	ErrorCodeInvalidClient        = -15
+	ErrorCodePaymentTooLow        = -16
+	ErrorCodeDuplicatePayment     = -17
+	ErrorCodeStalePayment         = -18
+	ErrorCodeRestricted           = -19
+	ErrorCodeUnsupportedBootstrap = -20
+	ErrorCodePaymentNotEnabled    = -21
+
+	ErrorCodeParseError     = -32700
+	ErrorCodeInvalidRequest = -32600
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+)
+
+// Statuses that monerod sets in the `status` field of results.
+//
+const (
+	StatusOK              = "OK"
+	StatusBusy            = "BUSY"
+	StatusPaymentRequired = "PAYMENT REQUIRED"
+)
+
+var (
+	// ErrBusy indicates that the daemon couldn't serve the request as it
+	// was busy (e.g., still syncing), thus, it might be worth trying
+	// again later.
+	//
+	ErrBusy = errors.New("daemon busy")
+
+	// ErrRestricted indicates that the request is not allowed by a
+	// daemon running in restricted mode.
+	//
+	// ps.: restricted JSONRPC methods and endpoints are not even exposed
+	// by a restricted daemon, leading to `ErrorCodeMethodNotFound` and
+	// 404s respectively, which are not reported as ErrRestricted.
+	//
+	ErrRestricted = errors.New("restricted")
+
+	// ErrPaymentRequired indicates that the daemon requires payment (in
+	// credits) for serving the request.
+	//
+	ErrPaymentRequired = errors.New("payment required")
+)
+
+// Error is an error reported by a JSONRPC method.
+//
+// It matches (as in `errors.Is`) ErrBusy, ErrRestricted and
+// ErrPaymentRequired according to its code.
+//
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("rpc error: code=%d message=%s", e.Code, e.Message)
+}
+
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrBusy:
+		return e.Code == ErrorCodeCoreBusy
+	case ErrRestricted:
+		return e.Code == ErrorCodeRestricted
+	case ErrPaymentRequired:
+		return e.Code == ErrorCodePaymentRequired
+	}
+
+	return false
+}
+
+// StatusError is the error for results whose `status` indicates that the
+// request didn't go through.
+//
+// It matches (as in `errors.Is`) ErrBusy and ErrPaymentRequired according
+// to the status.
+//
+type StatusError struct {
+	Status string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status: %s", e.Status)
+}
+
+func (e *StatusError) Is(target error) bool {
+	switch target {
+	case ErrBusy:
+		return e.Status == StatusBusy
+	case ErrPaymentRequired:
+		return e.Status == StatusPaymentRequired
+	}
+
+	return false
+}
+
+// HTTPError is the error for responses w/ a non-2xx status code, carrying
+// the beginning of the body for context.
+//
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("non-2xx status code: %d", e.StatusCode)
+	}
+
+	return fmt.Sprintf("non-2xx status code: %d: %s", e.StatusCode, e.Body)
+}
+
+// httpErrorBodySize is the maximum number of bytes of the body of a non-2xx
+// response kept in an HTTPError.
+//
+const httpErrorBodySize = 512
+
+// newHTTPError creates an HTTPError for a response w/ status code
+// `statusCode` and body `body`.
+//
+func newHTTPError(statusCode int, body io.Reader) *HTTPError {
+	b, _ := io.ReadAll(io.LimitReader(body, httpErrorBodySize))
+
+	return &HTTPError{
+		StatusCode: statusCode,
+		Body:       strings.TrimSpace(string(b)),
+	}
+}
+
+// checkStatus errors if `status` (that of the `status` field of a result)
+// indicates that the request didn't go through - results w/out one (like
+// those from the wallet) are fine.
+//
+func checkStatus(status string) error {
+	if status == "" || status == StatusOK {
+		return nil
+	}
+
+	return &StatusError{Status: status}
+}
@@ -0,0 +1,5 @@
+// Package pool provides a requester that spreads requests across a pool of
+// monerod nodes, routing them to those found to be healthy and failing over
+// to the others when one fails.
+//
+package pool
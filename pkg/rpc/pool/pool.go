@@ -0,0 +1,454 @@
+package pool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/daemon"
+)
+
+// DefaultMaxHeightLag is the default number of blocks that a node can be
+// behind the best one known for it to still be considered healthy.
+//
+const DefaultMaxHeightLag = 3
+
+// DefaultFailureCooldown is the default time after which requests are routed
+// again to a node that failed one.
+//
+const DefaultFailureCooldown = 30 * time.Second
+
+// Strategy dictates which of the healthy nodes a request is routed to first.
+//
+type Strategy int
+
+const (
+	// RoundRobin routes requests to each of the healthy nodes in turn.
+	//
+	RoundRobin Strategy = iota
+
+	// LowestLatency routes requests to the healthy node that took the
+	// least time to respond to the last health check.
+	//
+	LowestLatency
+)
+
+func (s Strategy) String() string {
+	switch s {
+	case RoundRobin:
+		return "round-robin"
+	case LowestLatency:
+		return "lowest-latency"
+	}
+
+	return fmt.Sprintf("unknown (%d)", int(s))
+}
+
+// ParseStrategy parses the name of a strategy (as given by its `String`).
+//
+func ParseStrategy(v string) (Strategy, error) {
+	for _, s := range []Strategy{RoundRobin, LowestLatency} {
+		if v == s.String() {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unknown strategy '%s'", v)
+}
+
+// NodeState is what's known about a node of the pool as of its last health
+// check (or failed request).
+//
+type NodeState struct {
+	// Address is the address of the node.
+	//
+	Address string
+
+	// Healthy indicates whether requests are routed to the node. Nodes
+	// that haven't been checked yet are considered healthy.
+	//
+	Healthy bool
+
+	// Synchronized indicates whether the node considered itself in sync
+	// with the network.
+	//
+	Synchronized bool
+
+	// Height is the height of the chain of the node.
+	//
+	Height uint64
+
+	// Latency is the time that the node took to respond to the health
+	// check.
+	//
+	Latency time.Duration
+
+	// LastError is the error of the last health check or request that
+	// failed, if that's what made the node unhealthy.
+	//
+	LastError error
+
+	// LastChecked is when the node was last checked.
+	//
+	LastChecked time.Time
+}
+
+// node is a member of the pool.
+//
+type node struct {
+	client *rpc.Client
+	state  NodeState
+
+	// failedAt is when the node was last marked unhealthy for failing a
+	// request (as opposed to a health check).
+	//
+	failedAt time.Time
+}
+
+// Pool is a requester (see `daemon.Requester`) that routes requests to the
+// healthy nodes of a pool of monerod nodes according to a strategy, failing
+// over to the next one when a node fails w/ a transient error (see
+// `rpc.Retryable`).
+//
+// Requests that are not idempotent (see `rpc.Idempotent`) only fail over
+// when it's certain that they were not processed by the node that failed.
+//
+// Nodes' health is determined by `CheckHealth` (or continuously, by
+// `Monitor`), as well as by requests failing - nodes that failed a request
+// are given another chance after a cooldown, so that they're not left out
+// for good when nothing checks their health.
+//
+type Pool struct {
+	strategy        Strategy
+	maxHeightLag    uint64
+	failureCooldown time.Duration
+
+	mu    sync.Mutex
+	nodes []*node
+	next  int
+}
+
+// Option defines a functional option for overriding the defaults of a
+// pool.
+//
+type Option func(p *Pool)
+
+// WithStrategy is a functional option for setting the strategy used for
+// routing requests (defaults to RoundRobin).
+//
+func WithStrategy(v Strategy) func(p *Pool) {
+	return func(p *Pool) {
+		p.strategy = v
+	}
+}
+
+// WithMaxHeightLag is a functional option for setting how many blocks a
+// node can be behind the best one known to still be considered healthy
+// (defaults to DefaultMaxHeightLag).
+//
+func WithMaxHeightLag(v uint64) func(p *Pool) {
+	return func(p *Pool) {
+		p.maxHeightLag = v
+	}
+}
+
+// WithFailureCooldown is a functional option for setting how long to wait
+// before routing requests again to a node that failed one (defaults to
+// DefaultFailureCooldown).
+//
+func WithFailureCooldown(v time.Duration) func(p *Pool) {
+	return func(p *Pool) {
+		p.failureCooldown = v
+	}
+}
+
+// New instantiates a pool of the nodes that `clients` talk to.
+//
+func New(clients []*rpc.Client, opts ...Option) (*Pool, error) {
+	if len(clients) == 0 {
+		return nil, fmt.Errorf("at least one client must be specified")
+	}
+
+	p := &Pool{
+		strategy:        RoundRobin,
+		maxHeightLag:    DefaultMaxHeightLag,
+		failureCooldown: DefaultFailureCooldown,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, client := range clients {
+		p.nodes = append(p.nodes, &node{
+			client: client,
+			state: NodeState{
+				Address: client.Address(),
+				Healthy: true,
+			},
+		})
+	}
+
+	return p, nil
+}
+
+// Nodes gives back the current state of each of the nodes of the pool.
+//
+func (p *Pool) Nodes() []NodeState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	states := make([]NodeState, len(p.nodes))
+	for idx, n := range p.nodes {
+		states[idx] = n.state
+	}
+
+	return states
+}
+
+// CheckHealth checks, concurrently, the health of every node by retrieving
+// its general information (see `daemon.GetInfo`): those that responded,
+// are synchronized, and are no more than the maximum height lag behind the
+// best height known (by any node, or their peers) are deemed healthy.
+//
+func (p *Pool) CheckHealth(ctx context.Context) {
+	type check struct {
+		info    *daemon.GetInfoResult
+		latency time.Duration
+		err     error
+	}
+
+	checks := make([]check, len(p.nodes))
+
+	var wg sync.WaitGroup
+	for idx, n := range p.nodes {
+		wg.Add(1)
+
+		go func(idx int, n *node) {
+			defer wg.Done()
+
+			start := time.Now()
+			info, err := daemon.NewClient(n.client).GetInfo(ctx)
+
+			checks[idx] = check{info, time.Since(start), err}
+		}(idx, n)
+	}
+
+	wg.Wait()
+
+	var best uint64
+	for _, c := range checks {
+		if c.err != nil {
+			continue
+		}
+
+		if c.info.Height > best {
+			best = c.info.Height
+		}
+
+		if c.info.TargetHeight > best {
+			best = c.info.TargetHeight
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for idx, c := range checks {
+		p.nodes[idx].failedAt = time.Time{}
+
+		state := &p.nodes[idx].state
+		state.LastChecked = now
+		state.LastError = c.err
+
+		if c.err != nil {
+			state.Healthy = false
+			continue
+		}
+
+		state.Synchronized = c.info.Synchronized
+		state.Height = c.info.Height
+		state.Latency = c.latency
+		state.Healthy = c.info.Synchronized &&
+			best-c.info.Height <= p.maxHeightLag
+	}
+}
+
+// Monitor checks the health of the nodes every `interval` until the context
+// is done.
+//
+func (p *Pool) Monitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.CheckHealth(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// JSONRPC calls the method `method` under `/json_rpc` of one of the nodes
+// (see `rpc.Client.JSONRPC`).
+//
+func (p *Pool) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	return p.do(method, func(c *rpc.Client) error {
+		return c.JSONRPC(ctx, method, params, result)
+	})
+}
+
+// JSONRPCBatch calls, in a single round trip, all the methods in `calls` in
+// one of the nodes (see `rpc.Client.JSONRPCBatch`).
+//
+func (p *Pool) JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+
+	// a batch is only as idempotent as the least idempotent of its calls.
+	//
+	method := calls[0].Method
+	for _, call := range calls {
+		if !rpc.Idempotent(call.Method) {
+			method = call.Method
+			break
+		}
+	}
+
+	return p.do(method, func(c *rpc.Client) error {
+		return c.JSONRPCBatch(ctx, calls)
+	})
+}
+
+// RawRequest makes a request to the endpoint `endpoint` of one of the nodes
+// (see `rpc.Client.RawRequest`).
+//
+func (p *Pool) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(endpoint, func(c *rpc.Client) error {
+		return c.RawRequest(ctx, endpoint, params, response)
+	})
+}
+
+// BinaryRequest makes a request to the binary endpoint `endpoint` of one of
+// the nodes (see `rpc.Client.BinaryRequest`).
+//
+func (p *Pool) BinaryRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	return p.do(endpoint, func(c *rpc.Client) error {
+		return c.BinaryRequest(ctx, endpoint, params, response)
+	})
+}
+
+// do makes the request for `method` (`fn`) to the nodes in the order given
+// by the strategy until one succeeds, or fails w/ an error that it
+// shouldn't fail over on.
+//
+func (p *Pool) do(method string, fn func(c *rpc.Client) error) error {
+	var err error
+
+	for _, n := range p.candidates() {
+		err = fn(n.client)
+		if err == nil {
+			p.markSucceeded(n)
+			return nil
+		}
+
+		if !failover(method, err) {
+			return err
+		}
+
+		p.markFailed(n, err)
+	}
+
+	return fmt.Errorf("all nodes failed: %w", err)
+}
+
+// failover tells whether the request for `method` that failed w/ `err`
+// should be tried in another node.
+//
+func failover(method string, err error) bool {
+	if !rpc.Retryable(err) {
+		return false
+	}
+
+	return rpc.Idempotent(method) || rpc.NotProcessed(err)
+}
+
+// candidates gives back the nodes that a request should be tried in, in
+// order: the healthy ones and those whose cooldown after failing a request
+// is over (or all of them if none is), sorted according to the strategy.
+//
+func (p *Pool) candidates() []*node {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	nodes := []*node{}
+	for _, n := range p.nodes {
+		cooledDown := !n.failedAt.IsZero() &&
+			time.Since(n.failedAt) >= p.failureCooldown
+
+		if n.state.Healthy || cooledDown {
+			nodes = append(nodes, n)
+		}
+	}
+
+	if len(nodes) == 0 {
+		nodes = append(nodes, p.nodes...)
+	}
+
+	switch p.strategy {
+	case LowestLatency:
+		sort.SliceStable(nodes, func(i, j int) bool {
+			return nodes[i].state.Latency < nodes[j].state.Latency
+		})
+	default:
+		offset := p.next % len(nodes)
+		p.next++
+
+		rotated := make([]*node, 0, len(nodes))
+		rotated = append(rotated, nodes[offset:]...)
+		nodes = append(rotated, nodes[:offset]...)
+	}
+
+	return nodes
+}
+
+// markFailed marks the node `n` as unhealthy for having failed w/ `err`.
+//
+func (p *Pool) markFailed(n *node, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n.state.Healthy = false
+	n.state.LastError = err
+	n.failedAt = time.Now()
+}
+
+// markSucceeded marks the node `n` as healthy again if it had been marked
+// unhealthy for failing a request.
+//
+func (p *Pool) markSucceeded(n *node) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if n.failedAt.IsZero() {
+		return
+	}
+
+	n.state.Healthy = true
+	n.state.LastError = nil
+	n.failedAt = time.Time{}
+}
@@ -0,0 +1,258 @@
+package pool_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/pool"
+)
+
+// fakeDaemon is a monerod that responds to `get_info` w/ its `height`
+// and `synchronized`, and to any other request w/ `status` (or an http
+// 500 if `broken`), counting them.
+//
+type fakeDaemon struct {
+	height       uint64
+	synchronized bool
+	delay        time.Duration
+	broken       bool
+
+	requests int32
+	server   *httptest.Server
+}
+
+func (d *fakeDaemon) start(t *testing.T) *rpc.Client {
+	d.server = httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(d.delay)
+
+			if r.URL.Path == "/json_rpc" {
+				req := &rpc.RequestEnvelope{}
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(req))
+
+				if req.Method == "get_info" {
+					fmt.Fprintf(w, `{"result": {"status": "OK", "height": %d, "synchronized": %t}}`,
+						d.height, d.synchronized)
+					return
+				}
+			}
+
+			atomic.AddInt32(&d.requests, 1)
+
+			if d.broken {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+
+			fmt.Fprintf(w, `{"status": "OK", "height": %d}`, d.height)
+		},
+	))
+
+	client, err := rpc.NewClient(d.server.URL, rpc.WithHTTPClient(d.server.Client()))
+	require.NoError(t, err)
+
+	return client
+}
+
+// nolint:funlen
+func TestPool(t *testing.T) {
+	spec.Run(t, "Pool", func(t *testing.T, when spec.G, it spec.S) {
+		var ctx = context.Background()
+
+		type heightResult struct {
+			Height uint64 `json:"height"`
+		}
+
+		newPool := func(daemons []*fakeDaemon, opts ...pool.Option) *pool.Pool {
+			clients := make([]*rpc.Client, len(daemons))
+			for idx, d := range daemons {
+				clients[idx] = d.start(t)
+			}
+
+			p, err := pool.New(clients, opts...)
+			require.NoError(t, err)
+
+			return p
+		}
+
+		closeAll := func(daemons []*fakeDaemon) {
+			for _, d := range daemons {
+				d.server.Close()
+			}
+		}
+
+		it("fails w/out clients", func() {
+			_, err := pool.New(nil)
+			assert.Error(t, err)
+		})
+
+		it("marks unsynchronized, lagging and unreachable nodes unhealthy", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true},
+				{height: 100, synchronized: false},
+				{height: 90, synchronized: true},
+				{height: 98, synchronized: true},
+				{height: 100, synchronized: true},
+			}
+
+			p := newPool(daemons)
+			defer closeAll(daemons)
+
+			daemons[4].server.Close()
+
+			p.CheckHealth(ctx)
+
+			nodes := p.Nodes()
+			require.Len(t, nodes, 5)
+
+			assert.True(t, nodes[0].Healthy)
+			assert.False(t, nodes[1].Healthy)
+			assert.False(t, nodes[2].Healthy)
+			assert.True(t, nodes[3].Healthy)
+			assert.False(t, nodes[4].Healthy)
+
+			assert.Equal(t, uint64(98), nodes[3].Height)
+			assert.Equal(t, daemons[0].server.URL, nodes[0].Address)
+			assert.NoError(t, nodes[0].LastError)
+			assert.Error(t, nodes[4].LastError)
+		})
+
+		it("routes requests to healthy nodes in turn", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true},
+				{height: 100, synchronized: false},
+				{height: 100, synchronized: true},
+			}
+
+			p := newPool(daemons, pool.WithStrategy(pool.RoundRobin))
+			defer closeAll(daemons)
+
+			p.CheckHealth(ctx)
+
+			for i := 0; i < 4; i++ {
+				err := p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, int32(2), daemons[0].requests)
+			assert.Equal(t, int32(0), daemons[1].requests)
+			assert.Equal(t, int32(2), daemons[2].requests)
+		})
+
+		it("routes requests to the fastest node", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true, delay: 50 * time.Millisecond},
+				{height: 100, synchronized: true},
+			}
+
+			p := newPool(daemons, pool.WithStrategy(pool.LowestLatency))
+			defer closeAll(daemons)
+
+			p.CheckHealth(ctx)
+
+			for i := 0; i < 3; i++ {
+				err := p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, int32(0), daemons[0].requests)
+			assert.Equal(t, int32(3), daemons[1].requests)
+		})
+
+		it("fails over to the next node, marking the failed one unhealthy", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true, broken: true},
+				{height: 101, synchronized: true},
+			}
+
+			p := newPool(daemons)
+			defer closeAll(daemons)
+
+			result := &heightResult{}
+
+			err := p.RawRequest(ctx, "/get_height", nil, result)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(101), result.Height)
+
+			nodes := p.Nodes()
+			assert.False(t, nodes[0].Healthy)
+			assert.Error(t, nodes[0].LastError)
+			assert.True(t, nodes[1].Healthy)
+		})
+
+		it("routes requests again to failed nodes after a cooldown", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true, broken: true},
+				{height: 100, synchronized: true},
+			}
+
+			p := newPool(daemons, pool.WithFailureCooldown(50*time.Millisecond))
+			defer closeAll(daemons)
+
+			err := p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+			require.NoError(t, err)
+			assert.False(t, p.Nodes()[0].Healthy)
+
+			daemons[0].broken = false
+
+			err = p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), daemons[0].requests)
+
+			time.Sleep(100 * time.Millisecond)
+
+			for i := 0; i < 2; i++ {
+				err = p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, int32(2), daemons[0].requests)
+
+			nodes := p.Nodes()
+			assert.True(t, nodes[0].Healthy)
+			assert.NoError(t, nodes[0].LastError)
+		})
+
+		it("doesn't fail over non-idempotent requests possibly processed", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true, broken: true},
+				{height: 100, synchronized: true},
+			}
+
+			p := newPool(daemons)
+			defer closeAll(daemons)
+
+			err := p.RawRequest(ctx, "/send_raw_transaction", nil, &struct{}{})
+			assert.Error(t, err)
+			assert.Equal(t, int32(0), daemons[1].requests)
+		})
+
+		it("fails if all nodes fail", func() {
+			daemons := []*fakeDaemon{
+				{height: 100, synchronized: true, broken: true},
+				{height: 100, synchronized: true, broken: true},
+			}
+
+			p := newPool(daemons)
+			defer closeAll(daemons)
+
+			err := p.RawRequest(ctx, "/get_height", nil, &heightResult{})
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "all nodes failed")
+			assert.Equal(t, int32(1), daemons[0].requests)
+			assert.Equal(t, int32(1), daemons[1].requests)
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,5 @@
+// Package quorum provides a requester that cross-checks the results of
+// requests across multiple (untrusted) monerod nodes, only trusting those
+// that enough of them agree on.
+//
+package quorum
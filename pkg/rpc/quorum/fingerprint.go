@@ -0,0 +1,120 @@
+package quorum
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// volatile are the fields (normalized, see `normalizeKey`) that differ
+// between nodes even when they agree on the state of the chain, thus, left
+// out when comparing results.
+//
+var volatile = map[string]bool{
+	"status":            true,
+	"untrusted":         true,
+	"credits":           true,
+	"tophash":           true,
+	"depth":             true,
+	"confirmations":     true,
+	"receivedtimestamp": true,
+	"relayed":           true,
+	"lastrelayedtime":   true,
+	"doublespendseen":   true,
+	"daemontime":        true,
+	"currentheight":     true,
+}
+
+// compared are the only fields (normalized, see `normalizeKey`) compared
+// for the methods (or endpoints) whose results are mostly about the node
+// itself rather than the chain.
+//
+var compared = map[string][]string{
+	"get_info": {"height"},
+	"getinfo":  {"height"},
+}
+
+// fingerprint gives back a canonical representation of the result `value`
+// (either a raw JSON result or a decoded one) of the request for `method`,
+// such that those from nodes agreeing on it are equal.
+//
+func fingerprint(method string, value interface{}) (string, error) {
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("marshal: %w", err)
+		}
+
+		raw = b
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return "", fmt.Errorf("decode: %w", err)
+	}
+
+	if fields, found := compared[strings.TrimPrefix(method, "/")]; found {
+		v = only(v, fields)
+	}
+
+	b, err := json.Marshal(strip(v))
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	return string(b), nil
+}
+
+// strip removes the volatile fields from objects anywhere in `v`.
+//
+func strip(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if volatile[normalizeKey(key)] {
+				delete(v, key)
+				continue
+			}
+
+			v[key] = strip(value)
+		}
+	case []interface{}:
+		for idx, value := range v {
+			v[idx] = strip(value)
+		}
+	}
+
+	return v
+}
+
+// only keeps, from the top-level object `v`, just the fields `fields`.
+//
+func only(v interface{}, fields []string) interface{} {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+
+	res := map[string]interface{}{}
+	for key, value := range object {
+		for _, field := range fields {
+			if normalizeKey(key) == field {
+				res[key] = value
+			}
+		}
+	}
+
+	return res
+}
+
+// normalizeKey normalizes the name of a field so that those from JSON
+// (`top_hash`) and from decoded binary results (`TopHash`) match.
+//
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
@@ -0,0 +1,394 @@
+package quorum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+)
+
+// NodeResult is how a node responded to a request.
+//
+type NodeResult struct {
+	// Address is the address of the node.
+	//
+	Address string
+
+	// Err is the error that the request to the node failed with, if it
+	// did.
+	//
+	Err error
+
+	// Agreed indicates whether the node's result is the one that the
+	// quorum agreed upon (or the most common one, if none was reached).
+	//
+	Agreed bool
+}
+
+// Report is the outcome of a request sent to all the nodes.
+//
+type Report struct {
+	// Method is the JSONRPC method or endpoint requested.
+	//
+	Method string
+
+	// Nodes is how each of the nodes responded.
+	//
+	Nodes []NodeResult
+}
+
+// Divergent gives back the nodes that either failed or didn't agree w/ the
+// result of the others.
+//
+func (r Report) Divergent() []NodeResult {
+	divergent := []NodeResult{}
+
+	for _, node := range r.Nodes {
+		if !node.Agreed {
+			divergent = append(divergent, node)
+		}
+	}
+
+	return divergent
+}
+
+// Error is the error for requests whose result not enough nodes agreed on.
+//
+type Error struct {
+	Report
+
+	// Threshold is the number of nodes that needed to agree.
+	//
+	Threshold int
+
+	// Agreeing is the number of nodes agreeing on the most common result.
+	//
+	Agreeing int
+}
+
+func (e *Error) Error() string {
+	divergent := []string{}
+
+	for _, node := range e.Divergent() {
+		if node.Err != nil {
+			divergent = append(divergent,
+				fmt.Sprintf("%s (%v)", node.Address, node.Err))
+			continue
+		}
+
+		divergent = append(divergent, node.Address)
+	}
+
+	return fmt.Sprintf("no quorum for '%s': %d/%d agree (need %d), "+
+		"divergent: %s", e.Method, e.Agreeing, len(e.Nodes),
+		e.Threshold, strings.Join(divergent, ", "))
+}
+
+// Quorum is a requester (see `daemon.Requester`) that sends requests to all
+// of a set of nodes in parallel, only giving back a result when at least
+// `threshold` of them agree on it.
+//
+// Results are compared w/out the fields that naturally differ between nodes
+// (see `fingerprint`), and for `get_info`, only the height is.
+//
+// Requests that are not idempotent (see `rpc.Idempotent`), like relaying a
+// transaction, can't be cross-checked, and go to the first node only.
+//
+type Quorum struct {
+	clients   []*rpc.Client
+	threshold int
+	reporter  func(Report)
+}
+
+// Option defines a functional option for overriding the defaults of a
+// quorum.
+//
+type Option func(q *Quorum)
+
+// WithReporter is a functional option for getting notified of the requests
+// for which some node diverged from the rest (whether a quorum was reached
+// or not).
+//
+func WithReporter(v func(Report)) func(q *Quorum) {
+	return func(q *Quorum) {
+		q.reporter = v
+	}
+}
+
+// New instantiates a quorum of the nodes that `clients` talk to, w/
+// `threshold` of them having to agree on a result.
+//
+func New(clients []*rpc.Client, threshold int, opts ...Option) (*Quorum, error) {
+	if threshold < 1 || threshold > len(clients) {
+		return nil, fmt.Errorf("threshold must be between 1 and the "+
+			"number of clients (%d), got %d", len(clients), threshold)
+	}
+
+	q := &Quorum{
+		clients:   clients,
+		threshold: threshold,
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q, nil
+}
+
+// JSONRPC calls the method `method` under `/json_rpc` of all the nodes (see
+// `rpc.Client.JSONRPC`).
+//
+func (q *Quorum) JSONRPC(
+	ctx context.Context, method string, params, result interface{},
+) error {
+	if !rpc.Idempotent(method) {
+		return q.clients[0].JSONRPC(ctx, method, params, result)
+	}
+
+	responses := q.all(func(c *rpc.Client) (interface{}, error) {
+		raw := json.RawMessage{}
+		err := c.JSONRPC(ctx, method, params, &raw)
+
+		return raw, err
+	})
+
+	raw, err := q.decide(method, responses)
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(raw.(json.RawMessage), result)
+}
+
+// JSONRPCBatch calls, in a single round trip, all the methods in `calls` in
+// all the nodes (see `rpc.Client.JSONRPCBatch`), w/ each call checked
+// separately.
+//
+func (q *Quorum) JSONRPCBatch(ctx context.Context, calls []*rpc.BatchCall) error {
+	for _, call := range calls {
+		if !rpc.Idempotent(call.Method) {
+			return q.clients[0].JSONRPCBatch(ctx, calls)
+		}
+	}
+
+	// the calls that each of the nodes got, w/ their raw results.
+	//
+	nodeCalls := make([][]*rpc.BatchCall, len(q.clients))
+
+	batches := q.all(func(c *rpc.Client) (interface{}, error) {
+		copies := make([]*rpc.BatchCall, len(calls))
+		for idx, call := range calls {
+			copies[idx] = &rpc.BatchCall{
+				Method: call.Method,
+				Params: call.Params,
+				Result: &json.RawMessage{},
+			}
+		}
+
+		return copies, c.JSONRPCBatch(ctx, copies)
+	})
+
+	for idx, batch := range batches {
+		if batch.err == nil {
+			nodeCalls[idx] = batch.value.([]*rpc.BatchCall)
+		}
+	}
+
+	for idx, call := range calls {
+		responses := make([]response, len(batches))
+
+		for node, batch := range batches {
+			responses[node] = response{address: batch.address, err: batch.err}
+			if batch.err != nil {
+				continue
+			}
+
+			nodeCall := nodeCalls[node][idx]
+
+			responses[node].err = nodeCall.Error
+			if nodeCall.Error == nil {
+				responses[node].value = *nodeCall.Result.(*json.RawMessage)
+			}
+		}
+
+		raw, err := q.decide(call.Method, responses)
+		if err != nil {
+			call.Error = err
+			continue
+		}
+
+		call.Error = unmarshal(raw.(json.RawMessage), call.Result)
+	}
+
+	return nil
+}
+
+// RawRequest makes a request to the endpoint `endpoint` of all the nodes
+// (see `rpc.Client.RawRequest`).
+//
+func (q *Quorum) RawRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	if !rpc.Idempotent(endpoint) {
+		return q.clients[0].RawRequest(ctx, endpoint, params, response)
+	}
+
+	responses := q.all(func(c *rpc.Client) (interface{}, error) {
+		raw := json.RawMessage{}
+		err := c.RawRequest(ctx, endpoint, params, &raw)
+
+		return raw, err
+	})
+
+	raw, err := q.decide(endpoint, responses)
+	if err != nil {
+		return err
+	}
+
+	return unmarshal(raw.(json.RawMessage), response)
+}
+
+// BinaryRequest makes a request to the binary endpoint `endpoint` of all
+// the nodes (see `rpc.Client.BinaryRequest`).
+//
+func (q *Quorum) BinaryRequest(
+	ctx context.Context, endpoint string, params, response interface{},
+) error {
+	if !rpc.Idempotent(endpoint) {
+		return q.clients[0].BinaryRequest(ctx, endpoint, params, response)
+	}
+
+	rv := reflect.ValueOf(response)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("expected non-nil pointer, got %T", response)
+	}
+
+	responses := q.all(func(c *rpc.Client) (interface{}, error) {
+		value := reflect.New(rv.Elem().Type()).Interface()
+		err := c.BinaryRequest(ctx, endpoint, params, value)
+
+		return value, err
+	})
+
+	value, err := q.decide(endpoint, responses)
+	if err != nil {
+		return err
+	}
+
+	rv.Elem().Set(reflect.ValueOf(value).Elem())
+	return nil
+}
+
+// response is the response of a node to a request.
+//
+type response struct {
+	address string
+	value   interface{}
+	err     error
+}
+
+// all makes the request `fn` to all the nodes in parallel, giving back
+// their responses in the same order as the clients.
+//
+func (q *Quorum) all(fn func(c *rpc.Client) (interface{}, error)) []response {
+	responses := make([]response, len(q.clients))
+
+	var wg sync.WaitGroup
+	for idx, client := range q.clients {
+		wg.Add(1)
+
+		go func(idx int, client *rpc.Client) {
+			defer wg.Done()
+
+			value, err := fn(client)
+			responses[idx] = response{client.Address(), value, err}
+		}(idx, client)
+	}
+
+	wg.Wait()
+
+	return responses
+}
+
+// decide gives back the value of the result that most nodes agree on for
+// the request for `method`, as long as enough of them do, reporting those
+// that diverged.
+//
+func (q *Quorum) decide(method string, responses []response) (interface{}, error) {
+	var (
+		fingerprints = make([]string, len(responses))
+		counts       = map[string]int{}
+		best         string
+	)
+
+	for idx, resp := range responses {
+		if resp.err != nil {
+			continue
+		}
+
+		fp, err := fingerprint(method, resp.value)
+		if err != nil {
+			responses[idx].err = fmt.Errorf("fingerprint: %w", err)
+			continue
+		}
+
+		fingerprints[idx] = fp
+		counts[fp]++
+
+		if counts[fp] > counts[best] {
+			best = fp
+		}
+	}
+
+	report := Report{
+		Method: method,
+		Nodes:  make([]NodeResult, len(responses)),
+	}
+
+	var value interface{}
+	for idx, resp := range responses {
+		agreed := resp.err == nil && fingerprints[idx] == best
+		if agreed {
+			value = resp.value
+		}
+
+		report.Nodes[idx] = NodeResult{
+			Address: resp.address,
+			Err:     resp.err,
+			Agreed:  agreed,
+		}
+	}
+
+	if counts[best] < len(responses) && q.reporter != nil {
+		q.reporter(report)
+	}
+
+	if counts[best] < q.threshold {
+		return nil, &Error{
+			Report:    report,
+			Threshold: q.threshold,
+			Agreeing:  counts[best],
+		}
+	}
+
+	return value, nil
+}
+
+// unmarshal decodes the agreed upon raw result into `result`.
+//
+func unmarshal(raw json.RawMessage, result interface{}) error {
+	if result == nil || len(raw) == 0 {
+		return nil
+	}
+
+	if err := json.Unmarshal(raw, result); err != nil {
+		return fmt.Errorf("unmarshal: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,234 @@
+package quorum_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/levin"
+	"github.com/cirocosta/go-monero/pkg/rpc"
+	"github.com/cirocosta/go-monero/pkg/rpc/quorum"
+)
+
+// nolint:funlen
+func TestQuorum(t *testing.T) {
+	spec.Run(t, "Quorum", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx     = context.Background()
+			servers []*httptest.Server
+		)
+
+		// nodes starts a node per body, each responding to any request
+		// w/ it (or an http 500 if empty), counting the requests.
+		//
+		nodes := func(requests *int32, bodies ...string) []*rpc.Client {
+			clients := make([]*rpc.Client, len(bodies))
+
+			for idx, body := range bodies {
+				body := body
+
+				server := httptest.NewServer(http.HandlerFunc(
+					func(w http.ResponseWriter, r *http.Request) {
+						atomic.AddInt32(requests, 1)
+
+						if body == "" {
+							w.WriteHeader(http.StatusInternalServerError)
+							return
+						}
+
+						fmt.Fprintln(w, body)
+					},
+				))
+				servers = append(servers, server)
+
+				client, err := rpc.NewClient(server.URL, rpc.WithHTTPClient(server.Client()))
+				require.NoError(t, err)
+
+				clients[idx] = client
+			}
+
+			return clients
+		}
+
+		it.After(func() {
+			for _, server := range servers {
+				server.Close()
+			}
+		})
+
+		type heightResult struct {
+			Height uint64 `json:"height"`
+			Hash   string `json:"hash"`
+		}
+
+		it("validates the threshold", func() {
+			var requests int32
+			clients := nodes(&requests, `{}`, `{}`)
+
+			_, err := quorum.New(clients, 0)
+			assert.Error(t, err)
+
+			_, err = quorum.New(clients, 3)
+			assert.Error(t, err)
+		})
+
+		it("gives back the result that enough nodes agree on, reporting the divergent ones", func() {
+			var (
+				requests int32
+				reports  []quorum.Report
+				mu       sync.Mutex
+			)
+
+			clients := nodes(&requests,
+				`{"status": "OK", "height": 10, "hash": "aa", "credits": 1}`,
+				`{"status": "OK", "height": 10, "hash": "aa", "untrusted": true}`,
+				`{"status": "OK", "height": 10, "hash": "bb"}`,
+			)
+
+			q, err := quorum.New(clients, 2, quorum.WithReporter(func(r quorum.Report) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				reports = append(reports, r)
+			}))
+			require.NoError(t, err)
+
+			result := &heightResult{}
+
+			err = q.RawRequest(ctx, "/get_height", nil, result)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(10), result.Height)
+			assert.Equal(t, "aa", result.Hash)
+			assert.Equal(t, int32(3), requests)
+
+			require.Len(t, reports, 1)
+			assert.Equal(t, "/get_height", reports[0].Method)
+
+			divergent := reports[0].Divergent()
+			require.Len(t, divergent, 1)
+			assert.Equal(t, clients[2].Address(), divergent[0].Address)
+		})
+
+		it("fails if not enough nodes agree", func() {
+			var requests int32
+
+			clients := nodes(&requests,
+				`{"status": "OK", "height": 10, "hash": "aa"}`,
+				`{"status": "OK", "height": 10, "hash": "bb"}`,
+				``,
+			)
+
+			q, err := quorum.New(clients, 2)
+			require.NoError(t, err)
+
+			err = q.RawRequest(ctx, "/get_height", nil, &heightResult{})
+			require.Error(t, err)
+
+			var quorumErr *quorum.Error
+			require.True(t, errors.As(err, &quorumErr))
+			assert.Equal(t, 1, quorumErr.Agreeing)
+			assert.Equal(t, 2, quorumErr.Threshold)
+			assert.Len(t, quorumErr.Divergent(), 2)
+			assert.Contains(t, err.Error(), "non-2xx")
+		})
+
+		it("compares only the height of get_info", func() {
+			var requests int32
+
+			clients := nodes(&requests,
+				`{"result": {"status": "OK", "height": 10, "incoming_connections_count": 1}}`,
+				`{"result": {"status": "OK", "height": 10, "incoming_connections_count": 5}}`,
+			)
+
+			q, err := quorum.New(clients, 2)
+			require.NoError(t, err)
+
+			result := &heightResult{}
+
+			err = q.JSONRPC(ctx, "get_info", nil, result)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(10), result.Height)
+		})
+
+		it("cross-checks each call of a batch", func() {
+			var requests int32
+
+			clients := nodes(&requests,
+				`[{"id": "0", "result": {"height": 1}}, {"id": "1", "result": {"height": 2}}]`,
+				`[{"id": "0", "result": {"height": 1}}, {"id": "1", "result": {"height": 3}}]`,
+			)
+
+			q, err := quorum.New(clients, 2)
+			require.NoError(t, err)
+
+			calls := []*rpc.BatchCall{
+				{Method: "get_block_header_by_height", Result: &heightResult{}},
+				{Method: "get_block_header_by_height", Result: &heightResult{}},
+			}
+
+			err = q.JSONRPCBatch(ctx, calls)
+			require.NoError(t, err)
+
+			assert.NoError(t, calls[0].Error)
+			assert.Equal(t, uint64(1), calls[0].Result.(*heightResult).Height)
+
+			var quorumErr *quorum.Error
+			assert.True(t, errors.As(calls[1].Error, &quorumErr))
+		})
+
+		it("cross-checks binary results", func() {
+			type result struct {
+				Status      string `epee:"status"`
+				Height      uint64 `epee:"height"`
+				DaemonTime  uint64 `epee:"daemon_time"`
+				StartHeight uint64 `epee:"start_height"`
+			}
+
+			bodies := []string{}
+			for _, r := range []result{
+				{Status: "OK", Height: 10, DaemonTime: 1},
+				{Status: "OK", Height: 10, DaemonTime: 2},
+			} {
+				b, err := levin.Marshal(r)
+				require.NoError(t, err)
+
+				bodies = append(bodies, string(b))
+			}
+
+			var requests int32
+			clients := nodes(&requests, bodies...)
+
+			q, err := quorum.New(clients, 2)
+			require.NoError(t, err)
+
+			res := &result{}
+
+			err = q.BinaryRequest(ctx, "/foo.bin", nil, res)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(10), res.Height)
+		})
+
+		it("sends non-idempotent requests to the first node only", func() {
+			var requests int32
+
+			clients := nodes(&requests, `{"status": "OK"}`, `{"status": "OK"}`)
+
+			q, err := quorum.New(clients, 2)
+			require.NoError(t, err)
+
+			err = q.RawRequest(ctx, "/send_raw_transaction", nil, &struct{}{})
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), requests)
+		})
+	}, spec.Report(report.Terminal{}))
+}
@@ -0,0 +1,211 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// nonIdempotent are the methods and endpoints (w/out the leading `/`) of
+// both monerod and monero-wallet-rpc whose effects would be repeated if
+// retried after having been processed.
+//
+var nonIdempotent = map[string]bool{
+	// daemon
+	//
+	"send_raw_transaction": true,
+	"sendrawtransaction":   true,
+	"relay_tx":             true,
+	"submit_block":         true,
+	"submitblock":          true,
+	"generateblocks":       true,
+	"pop_blocks":           true,
+	"flush_txpool":         true,
+
+	// wallet
+	//
+	"transfer":         true,
+	"transfer_split":   true,
+	"sweep_all":        true,
+	"sweep_single":     true,
+	"sweep_dust":       true,
+	"submit_transfer":  true,
+	"create_address":   true,
+	"create_account":   true,
+	"add_address_book": true,
+}
+
+// RetryPolicy dictates how requests that failed should be retried.
+//
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times that a request is
+	// made (the first attempt included).
+	//
+	MaxAttempts int
+
+	// Backoff is how long to wait before the first retry, doubling for
+	// each of the following ones (up to `MaxBackoff`), w/ jitter.
+	//
+	Backoff time.Duration
+
+	// MaxBackoff caps the time waited between attempts.
+	//
+	MaxBackoff time.Duration
+
+	// RetryOn tells whether a request that failed with `err` should be
+	// retried. Defaults to `Retryable`.
+	//
+	RetryOn func(err error) bool
+
+	// Idempotent tells whether making the request for `method` (either
+	// the JSONRPC method or the endpoint) more than once is harmless.
+	//
+	// Those that are not are only retried when it's certain that they
+	// weren't processed (see `NotProcessed`), so that, e.g., a
+	// transaction isn't relayed twice. Defaults to `Idempotent`.
+	//
+	Idempotent func(method string) bool
+}
+
+// WithRetry is a functional option for retrying failed requests according
+// to `policy`, w/ zero values in it replaced by sensible defaults.
+//
+func WithRetry(policy RetryPolicy) func(o *clientOptions) {
+	return func(o *clientOptions) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = 3
+		}
+
+		if policy.Backoff <= 0 {
+			policy.Backoff = 500 * time.Millisecond
+		}
+
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = 30 * time.Second
+		}
+
+		if policy.RetryOn == nil {
+			policy.RetryOn = Retryable
+		}
+
+		if policy.Idempotent == nil {
+			policy.Idempotent = Idempotent
+		}
+
+		o.RetryPolicy = &policy
+	}
+}
+
+// Idempotent tells whether the method (or endpoint) `method` of either
+// monerod or monero-wallet-rpc can be made more than once w/out having its
+// effects repeated.
+//
+func Idempotent(method string) bool {
+	return !nonIdempotent[strings.TrimPrefix(method, "/")]
+}
+
+// Retryable tells whether the error is (possibly) transient, i.e., the
+// daemon being busy, unavailable (5xx or 429), or the connection to it
+// failing.
+//
+// Errors due to the context being done are never retryable.
+//
+func Retryable(err error) bool {
+	if errors.Is(err, context.Canceled) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, ErrBusy) {
+		return true
+	}
+
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode >= 500 ||
+			httpErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// NotProcessed tells whether the request that failed w/ `err` certainly
+// wasn't processed by the daemon: either it couldn't even be reached, or it
+// refused to do so for being busy.
+//
+func NotProcessed(err error) bool {
+	if errors.Is(err, ErrBusy) {
+		return true
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return opErr.Op == "dial"
+	}
+
+	return false
+}
+
+// do calls `fn` (the request for `method`) until it either succeeds, fails
+// w/ an error that shouldn't be retried, or runs out of attempts, giving
+// back the last error.
+//
+func (p *RetryPolicy) do(ctx context.Context, method string, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.shouldRetry(method, err) {
+			return err
+		}
+
+		timer := time.NewTimer(p.backoff(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry tells whether the request for `method` that failed w/ `err`
+// should be made again.
+//
+func (p *RetryPolicy) shouldRetry(method string, err error) bool {
+	if !p.RetryOn(err) {
+		return false
+	}
+
+	return p.Idempotent(method) || NotProcessed(err)
+}
+
+// backoff computes how long to wait after the attempt number `attempt`:
+// exponentially increasing, capped at `MaxBackoff`, w/ "equal jitter" (half
+// of it being random).
+//
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+
+	if d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	half := d / 2
+
+	// nolint:gosec
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
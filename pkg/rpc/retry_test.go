@@ -0,0 +1,209 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sclevine/spec"
+	"github.com/sclevine/spec/report"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cirocosta/go-monero/pkg/rpc"
+)
+
+// nolint:funlen
+func TestRetry(t *testing.T) {
+	spec.Run(t, "WithRetry", func(t *testing.T, when spec.G, it spec.S) {
+		var (
+			ctx    = context.Background()
+			policy = rpc.RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+			}
+		)
+
+		// daemon serves `responses` in order (the last one
+		// indefinitely), counting the requests made.
+		//
+		daemon := func(attempts *int32, responses ...func(w http.ResponseWriter)) *httptest.Server {
+			return httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					idx := int(atomic.AddInt32(attempts, 1)) - 1
+					if idx >= len(responses) {
+						idx = len(responses) - 1
+					}
+
+					responses[idx](w)
+				},
+			))
+		}
+
+		busy := func(w http.ResponseWriter) {
+			fmt.Fprintln(w, `{"status": "BUSY"}`)
+		}
+
+		unavailable := func(w http.ResponseWriter) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+
+		ok := func(w http.ResponseWriter) {
+			fmt.Fprintln(w, `{"status": "OK", "height": 10}`)
+		}
+
+		it("retries until the request succeeds", func() {
+			var attempts int32
+
+			server := daemon(&attempts, busy, unavailable, ok)
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(policy),
+			)
+			require.NoError(t, err)
+
+			result := &struct {
+				Height uint64 `json:"height"`
+			}{}
+
+			err = client.RawRequest(ctx, "/get_height", nil, result)
+			require.NoError(t, err)
+			assert.Equal(t, int32(3), attempts)
+			assert.Equal(t, uint64(10), result.Height)
+		})
+
+		it("gives up after max attempts w/ the last error", func() {
+			var attempts int32
+
+			server := daemon(&attempts, busy)
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/get_height", nil, &struct{}{})
+			assert.True(t, errors.Is(err, rpc.ErrBusy))
+			assert.Equal(t, int32(3), attempts)
+		})
+
+		it("doesn't retry errors that aren't transient", func() {
+			var attempts int32
+
+			server := daemon(&attempts, func(w http.ResponseWriter) {
+				fmt.Fprintln(w, `{"error": {"code": -1, "message": "wrong param"}}`)
+			})
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.JSONRPC(ctx, "get_block", nil, nil)
+			assert.Error(t, err)
+			assert.Equal(t, int32(1), attempts)
+		})
+
+		it("doesn't retry non-idempotent requests that might've been processed", func() {
+			var attempts int32
+
+			server := daemon(&attempts, unavailable, ok)
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/send_raw_transaction", nil, &struct{}{})
+			assert.Error(t, err)
+			assert.Equal(t, int32(1), attempts)
+		})
+
+		it("retries non-idempotent requests certainly not processed", func() {
+			var attempts int32
+
+			server := daemon(&attempts, busy, ok)
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(policy),
+			)
+			require.NoError(t, err)
+
+			err = client.RawRequest(ctx, "/send_raw_transaction", nil, &struct{}{})
+			assert.NoError(t, err)
+			assert.Equal(t, int32(2), attempts)
+		})
+
+		it("stops once the context is done", func() {
+			var attempts int32
+
+			server := daemon(&attempts, busy)
+			defer server.Close()
+
+			client, err := rpc.NewClient(server.URL,
+				rpc.WithHTTPClient(server.Client()),
+				rpc.WithRetry(rpc.RetryPolicy{
+					MaxAttempts: 10,
+					Backoff:     time.Hour,
+				}),
+			)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+			defer cancel()
+
+			err = client.RawRequest(ctx, "/get_height", nil, &struct{}{})
+			assert.True(t, errors.Is(err, rpc.ErrBusy))
+			assert.Equal(t, int32(1), attempts)
+		})
+	}, spec.Report(report.Terminal{}), spec.Parallel(), spec.Random())
+}
+
+func TestRetryable(t *testing.T) {
+	spec.Run(t, "Retryable", func(t *testing.T, when spec.G, it spec.S) {
+		for _, tc := range []struct {
+			desc      string
+			err       error
+			retryable bool
+		}{
+			{"busy", &rpc.StatusError{Status: rpc.StatusBusy}, true},
+			{"core busy", &rpc.Error{Code: rpc.ErrorCodeCoreBusy}, true},
+			{"5xx", &rpc.HTTPError{StatusCode: 502}, true},
+			{"429", &rpc.HTTPError{StatusCode: 429}, true},
+			{"4xx", &rpc.HTTPError{StatusCode: 404}, false},
+			{"rpc error", &rpc.Error{Code: rpc.ErrorCodeWrongParam}, false},
+			{"status failed", &rpc.StatusError{Status: "Failed"}, false},
+			{"canceled", fmt.Errorf("do: %w", context.Canceled), false},
+		} {
+			tc := tc
+
+			it(tc.desc, func() {
+				err := fmt.Errorf("wrapped: %w", tc.err)
+				assert.Equal(t, tc.retryable, rpc.Retryable(err))
+			})
+		}
+
+		it("knows which methods aren't idempotent", func() {
+			assert.True(t, rpc.Idempotent("get_info"))
+			assert.True(t, rpc.Idempotent("/get_height"))
+			assert.False(t, rpc.Idempotent("/send_raw_transaction"))
+			assert.False(t, rpc.Idempotent("relay_tx"))
+			assert.False(t, rpc.Idempotent("transfer"))
+		})
+	}, spec.Report(report.Terminal{}))
+}